@@ -0,0 +1,296 @@
+package godoc
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// ANSI escape sequences of the styles used by [ANSIText].
+const (
+	ansiBold  = "\x1b[1m"
+	ansiDim   = "\x1b[2m"
+	ansiReset = "\x1b[0m"
+)
+
+// ANSIText returns the documentation of result as plain text with basic ANSI
+// styling, a lighter alternative to rendering [PackageDoc.Markdown] with a
+// Markdown renderer: headings and declared names are bold, and the types of
+// signatures are dim. Constructors and methods are listed under their types.
+//
+// The styling is left out, leaving plain text, if the NO_COLOR environment
+// variable is set to a non-empty value or standard output is not a
+// terminal. Results other than [PackageDoc] and [SymbolDoc] are returned as
+// their Text.
+func ANSIText(result Result) string {
+	return ansiText(result, colorEnabled())
+}
+
+// PlainText returns the documentation of result laid out as by [ANSIText],
+// without styling, e.g. to write it to a file.
+func PlainText(result Result) string {
+	return ansiText(result, false)
+}
+
+// colorEnabled reports whether output to standard output may be colored.
+func colorEnabled() bool {
+	return os.Getenv("NO_COLOR") == "" && term.IsTerminal(int(os.Stdout.Fd()))
+}
+
+// ansiText renders result as text, styled with ANSI escape sequences if
+// color is true.
+func ansiText(result Result, color bool) string {
+	w := ansiWriter{color: color}
+
+	switch r := result.(type) {
+	case PackageDoc:
+		w.pkg(r)
+	case SymbolDoc:
+		w.symbol(r)
+	default:
+		return result.Text()
+	}
+
+	return w.sb.String()
+}
+
+// ansiWriter accumulates ANSI-styled text.
+type ansiWriter struct {
+	sb    strings.Builder
+	color bool
+}
+
+// pkg writes the documentation of a package.
+func (w *ansiWriter) pkg(p PackageDoc) {
+	w.line(w.bold("package "+p.Name) + w.dim(fmt.Sprintf(" // import %q", p.ImportPath)))
+	w.line("")
+	w.doc(p.DocText, "")
+
+	if len(p.Consts) > 0 {
+		w.heading("CONSTANTS")
+		for _, c := range p.Consts {
+			w.value("const", c)
+		}
+	}
+
+	if len(p.Vars) > 0 {
+		w.heading("VARIABLES")
+		for _, v := range p.Vars {
+			w.value("var", v)
+		}
+	}
+
+	if funcs := p.standaloneFuncs(); len(funcs) > 0 {
+		w.heading("FUNCTIONS")
+		for _, f := range funcs {
+			w.line(w.funcSignature(f))
+			w.doc(f.Doc, "    ")
+		}
+	}
+
+	if len(p.Types) > 0 {
+		w.heading("TYPES")
+		for _, t := range p.Types {
+			w.line(w.typeDecl(t.Name, t.Decl))
+			w.doc(t.Doc, "    ")
+
+			for _, f := range t.Constructors {
+				w.line(w.funcSignature(f))
+				w.doc(f.Doc, "    ")
+			}
+
+			// Interface methods are part of the declaration.
+			if t.Kind != "interface" {
+				w.methods(t.Methods)
+			}
+		}
+	}
+}
+
+// symbol writes the documentation of a symbol.
+func (w *ansiWriter) symbol(s SymbolDoc) {
+	w.line(w.dim(fmt.Sprintf("package %s // import %q", s.Package, s.ImportPath)))
+	w.line("")
+
+	switch {
+	case s.Kind == "type" && s.TypeDoc != nil:
+		w.line(w.typeDecl(s.Name, s.Decl))
+		w.doc(s.DocText, "    ")
+		if s.TypeDoc.Kind != "interface" {
+			w.methods(s.Methods)
+		}
+	case s.Kind == "method" && s.FuncDoc != nil:
+		w.line(w.methodSignature(symbolMethod(s)))
+		w.doc(s.DocText, "    ")
+	case s.FuncDoc != nil:
+		w.line(w.funcSignature(*s.FuncDoc))
+		w.doc(s.DocText, "    ")
+	default:
+		decl := s.Kind + " " + w.bold(s.Name)
+		if s.Value != "" {
+			decl += " = " + s.Value
+		}
+
+		w.line(decl)
+		w.doc(s.DocText, "    ")
+	}
+
+	for _, t := range s.InlineTypes {
+		w.line(t.Decl)
+		w.line("")
+	}
+}
+
+// line writes s followed by a newline.
+func (w *ansiWriter) line(s string) {
+	w.sb.WriteString(s)
+	w.sb.WriteByte('\n')
+}
+
+// heading writes a section heading.
+func (w *ansiWriter) heading(title string) {
+	w.line(w.bold(title))
+	w.line("")
+}
+
+// doc writes doc comment text with each line indented by indent, followed
+// by a blank line.
+func (w *ansiWriter) doc(text, indent string) {
+	text = strings.TrimRight(text, "\n")
+	if text != "" {
+		for l := range strings.SplitSeq(text, "\n") {
+			if l != "" {
+				l = indent + l
+			}
+
+			w.line(l)
+		}
+	}
+
+	w.line("")
+}
+
+// value writes a constant or variable group declared with keyword.
+func (w *ansiWriter) value(keyword string, v ValueDoc) {
+	for i, name := range v.Names {
+		decl := keyword + " " + w.bold(name)
+		if i < len(v.Values) && v.Values[i] != "" {
+			decl += " = " + v.Values[i]
+		}
+
+		w.line(decl)
+	}
+
+	w.doc(v.Doc, "    ")
+}
+
+// methods writes the signatures and doc comments of the given methods.
+func (w *ansiWriter) methods(methods []MethodDoc) {
+	for _, m := range methods {
+		w.line(w.methodSignature(m))
+		w.doc(m.Doc, "    ")
+	}
+}
+
+// typeDecl returns the declaration of the type named name with the name in
+// bold.
+func (w *ansiWriter) typeDecl(name, decl string) string {
+	prefix := "type " + name
+	if !strings.HasPrefix(decl, prefix) {
+		return decl
+	}
+
+	return "type " + w.bold(name) + decl[len(prefix):]
+}
+
+// funcSignature renders the signature of f like [funcSignature], with the
+// name in bold and types dim.
+func (w *ansiWriter) funcSignature(f FuncDoc) string {
+	return fmt.Sprintf("func %s%s(%s)%s", w.bold(f.Name), w.typeParams(f.TypeParams), w.params(f.Args), w.results(f.Returns))
+}
+
+// methodSignature renders the signature of m like [methodSignature], with
+// the name in bold and types dim.
+func (w *ansiWriter) methodSignature(m MethodDoc) string {
+	recvType := m.RecvType
+	if recvType == "" && m.Recv != "" {
+		recvType = m.Recv
+		if m.RecvPointer {
+			recvType = "*" + recvType
+		}
+	}
+
+	recv := ""
+	switch {
+	case recvType != "" && m.RecvName != "":
+		recv = fmt.Sprintf("(%s %s) ", m.RecvName, w.dim(recvType))
+	case recvType != "":
+		recv = fmt.Sprintf("(%s) ", w.dim(recvType))
+	}
+
+	return fmt.Sprintf("func %s%s(%s)%s", recv, w.bold(m.Name), w.params(m.Args), w.results(m.Returns))
+}
+
+// params formats args like [formatParams], with dim types.
+func (w *ansiWriter) params(args []ArgInfo) string {
+	parts := make([]string, 0, len(args))
+	for _, arg := range args {
+		switch {
+		case arg.Name != "" && arg.Type != "":
+			parts = append(parts, arg.Name+" "+w.dim(arg.Type))
+		case arg.Type != "":
+			parts = append(parts, w.dim(arg.Type))
+		case arg.Name != "":
+			parts = append(parts, arg.Name)
+		default:
+			parts = append(parts, "_")
+		}
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+// results formats returns like [formatResults], with dim types.
+func (w *ansiWriter) results(returns []ArgInfo) string {
+	if len(returns) == 0 {
+		return ""
+	}
+
+	if len(returns) == 1 && returns[0].Name == "" {
+		return " " + w.params(returns)
+	}
+
+	return " (" + w.params(returns) + ")"
+}
+
+// typeParams formats typeParams like [formatTypeParams], with dim
+// constraints.
+func (w *ansiWriter) typeParams(typeParams []ArgInfo) string {
+	if len(typeParams) == 0 {
+		return ""
+	}
+
+	return "[" + w.params(typeParams) + "]"
+}
+
+// bold returns s in bold.
+func (w *ansiWriter) bold(s string) string {
+	return w.style(ansiBold, s)
+}
+
+// dim returns s dimmed.
+func (w *ansiWriter) dim(s string) string {
+	return w.style(ansiDim, s)
+}
+
+// style returns s wrapped in the given escape sequence, or s as is if color
+// is disabled.
+func (w *ansiWriter) style(code, s string) string {
+	if !w.color || s == "" {
+		return s
+	}
+
+	return code + s + ansiReset
+}
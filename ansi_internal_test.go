@@ -0,0 +1,85 @@
+package godoc
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestANSIText(t *testing.T) {
+	p := PackageDoc{
+		ImportPath: "example.com/foo",
+		Name:       "foo",
+		DocText:    "Package foo does things.\n",
+		Consts:     []ValueDoc{{Names: []string{"A"}, Values: []string{"1"}, Doc: "A is one.\n"}},
+		Funcs: []FuncDoc{{
+			Name:    "New",
+			Args:    []ArgInfo{{Name: "opts", Type: "...Option"}},
+			Returns: []ArgInfo{{Type: "*Client"}},
+			Doc:     "New returns a Client.\n",
+		}},
+		Types: []TypeDoc{{
+			Name: "Client",
+			Kind: "struct",
+			Decl: "type Client struct{}",
+			Methods: []MethodDoc{{
+				Recv: "Client", RecvName: "c", RecvType: "*Client", Name: "Do",
+				Returns: []ArgInfo{{Type: "error"}},
+			}},
+		}},
+	}
+
+	plain := ansiText(p, false)
+	if strings.Contains(plain, "\x1b[") {
+		t.Errorf("expected no escape sequences without color, got %q", plain)
+	}
+
+	for _, want := range []string{
+		"package foo // import \"example.com/foo\"\n\nPackage foo does things.\n",
+		"CONSTANTS\n\nconst A = 1\n    A is one.\n",
+		"func New(opts ...Option) *Client\n    New returns a Client.\n",
+		"TYPES\n\ntype Client struct{}\n",
+		"func (c *Client) Do() error\n",
+	} {
+		if !strings.Contains(plain, want) {
+			t.Errorf("expected text to contain %q, got:\n%s", want, plain)
+		}
+	}
+
+	colored := ansiText(p, true)
+	for _, want := range []string{
+		ansiBold + "CONSTANTS" + ansiReset,
+		"func " + ansiBold + "New" + ansiReset + "(opts " + ansiDim + "...Option" + ansiReset + ") " + ansiDim + "*Client" + ansiReset,
+		"type " + ansiBold + "Client" + ansiReset + " struct{}",
+		"func (c " + ansiDim + "*Client" + ansiReset + ") " + ansiBold + "Do" + ansiReset + "()",
+	} {
+		if !strings.Contains(colored, want) {
+			t.Errorf("expected colored text to contain %q, got:\n%q", want, colored)
+		}
+	}
+
+	s := SymbolDoc{ImportPath: "example.com/foo", Package: "foo", Kind: "const", Name: "A", Value: "1", DocText: "A is one.\n"}
+	if got, want := ansiText(s, false), "package foo // import \"example.com/foo\"\n\nconst A = 1\n    A is one.\n\n"; got != want {
+		t.Errorf("expected symbol text %q, got %q", want, got)
+	}
+
+	// Standard output is not a terminal under go test.
+	if got := ANSIText(p); got != plain {
+		t.Errorf("expected uncolored text when stdout is not a terminal, got %q", got)
+	}
+
+	// Constructors are listed under their type only.
+	p.Types[0].Constructors = p.Funcs
+	plain = ansiText(p, false)
+	if strings.Contains(plain, "FUNCTIONS") {
+		t.Errorf("expected no FUNCTIONS section for constructors, got:\n%s", plain)
+	}
+
+	if want := "type Client struct{}\n\nfunc New(opts ...Option) *Client\n    New returns a Client.\n"; !strings.Contains(plain, want) {
+		t.Errorf("expected the constructor under its type, got:\n%s", plain)
+	}
+
+	t.Setenv("NO_COLOR", "1")
+	if colorEnabled() {
+		t.Errorf("expected NO_COLOR to disable colors")
+	}
+}
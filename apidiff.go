@@ -0,0 +1,416 @@
+package godoc
+
+import (
+	"go/token"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// APIChange describes a single difference between two versions of a
+// package's exported API.
+type APIChange struct {
+	Symbol   string `json:"symbol" jsonschema:"changed symbol (e.g. Func, Type.Method, Type.Field)"`
+	Kind     string `json:"kind" jsonschema:"symbol kind (const, var, func, type, method, or field)"`
+	Change   string `json:"change" jsonschema:"change type (added, removed, or changed)"`
+	Old      string `json:"old,omitempty" jsonschema:"previous declaration"`
+	New      string `json:"new,omitempty" jsonschema:"new declaration"`
+	Breaking bool   `json:"breaking" jsonschema:"whether the change breaks existing users"`
+}
+
+// APIDiff lists the differences between two versions of a package's
+// exported API.
+type APIDiff struct {
+	Changes     []APIChange `json:"changes" jsonschema:"API changes ordered by symbol"`
+	Breaking    int         `json:"breaking" jsonschema:"number of breaking changes"`
+	NonBreaking int         `json:"non_breaking" jsonschema:"number of non-breaking changes"`
+}
+
+// HasBreaking reports whether the diff contains any breaking change, i.e.
+// whether it requires a new major version.
+func (d APIDiff) HasBreaking() bool {
+	return d.Breaking > 0
+}
+
+// DiffAPI compares the exported API of two versions of a package.
+//
+// Removed symbols, methods, and fields, changed signatures, changed constant
+// values, changed type declarations, and methods added to interfaces are
+// classified as breaking. Added symbols, methods, and struct fields are not.
+// Signatures are compared by their type parameters and their argument and
+// result types, so renaming a parameter is not a change. Unexported names
+// are ignored, as in [PackageDoc.APISignature].
+func DiffAPI(oldPkg, newPkg PackageDoc) APIDiff {
+	var changes []APIChange
+
+	changes = diffValues(changes, "const", oldPkg.Consts, newPkg.Consts)
+	changes = diffValues(changes, "var", oldPkg.Vars, newPkg.Vars)
+	changes = diffFuncs(changes, oldPkg.Funcs, newPkg.Funcs)
+	changes = diffTypes(changes, oldPkg.Types, newPkg.Types)
+
+	sort.SliceStable(changes, func(i, j int) bool {
+		if changes[i].Symbol != changes[j].Symbol {
+			return changes[i].Symbol < changes[j].Symbol
+		}
+
+		return changes[i].Change < changes[j].Change
+	})
+
+	diff := APIDiff{Changes: changes}
+	for _, c := range changes {
+		if c.Breaking {
+			diff.Breaking++
+		} else {
+			diff.NonBreaking++
+		}
+	}
+
+	return diff
+}
+
+// diffEntry is a symbol under comparison, keyed by name.
+type diffEntry struct {
+	decl string // Rendered declaration, reported in APIChange
+	key  string // Comparison key; differing keys mean a changed symbol
+}
+
+// diffEntries appends the added, removed, and changed entries between
+// oldEntries and newEntries. Additions are breaking if addBreaks is set.
+func diffEntries(changes []APIChange, kind string, oldEntries, newEntries map[string]diffEntry, addBreaks bool) []APIChange {
+	for name, o := range oldEntries {
+		n, ok := newEntries[name]
+		switch {
+		case !ok:
+			changes = append(changes, APIChange{Symbol: name, Kind: kind, Change: "removed", Old: o.decl, Breaking: true})
+		case o.key != n.key:
+			changes = append(changes, APIChange{Symbol: name, Kind: kind, Change: "changed", Old: o.decl, New: n.decl, Breaking: true})
+		}
+	}
+
+	for name, n := range newEntries {
+		if _, ok := oldEntries[name]; !ok {
+			changes = append(changes, APIChange{Symbol: name, Kind: kind, Change: "added", New: n.decl, Breaking: addBreaks})
+		}
+	}
+
+	return changes
+}
+
+// diffValues compares constants or variables. Constants whose evaluated
+// value is known in both versions are compared by value.
+func diffValues(changes []APIChange, kind string, oldValues, newValues []ValueDoc) []APIChange {
+	entries := func(values []ValueDoc) map[string]diffEntry {
+		m := make(map[string]diffEntry)
+		for _, v := range values {
+			for i, name := range v.Names {
+				if !token.IsExported(name) {
+					continue
+				}
+
+				var val string
+				if i < len(v.Values) {
+					val = v.Values[i]
+				}
+
+				// Variables may be reassigned, so only constants are
+				// compared by value.
+				key := val
+				if kind != "const" {
+					key = ""
+				}
+
+				m[name] = diffEntry{decl: valueDecl(kind, name, val), key: key}
+			}
+		}
+
+		return m
+	}
+
+	oldEntries, newEntries := entries(oldValues), entries(newValues)
+
+	// Values that are unknown on either side cannot be compared.
+	for name, o := range oldEntries {
+		if n, ok := newEntries[name]; ok && (o.key == "" || n.key == "") {
+			n.key = o.key
+			newEntries[name] = n
+		}
+	}
+
+	return diffEntries(changes, kind, oldEntries, newEntries, false)
+}
+
+// valueDecl renders a constant or variable declaration.
+func valueDecl(kind, name, value string) string {
+	if value == "" {
+		return kind + " " + name
+	}
+
+	return kind + " " + name + " = " + value
+}
+
+// diffFuncs compares package-level functions.
+func diffFuncs(changes []APIChange, oldFuncs, newFuncs []FuncDoc) []APIChange {
+	entries := func(funcs []FuncDoc) map[string]diffEntry {
+		m := make(map[string]diffEntry, len(funcs))
+		for _, f := range funcs {
+			if token.IsExported(f.Name) {
+				m[f.Name] = diffEntry{decl: funcSignature(f), key: signatureKey("", f.TypeParams, f.Args, f.Returns)}
+			}
+		}
+
+		return m
+	}
+
+	return diffEntries(changes, "func", entries(oldFuncs), entries(newFuncs), false)
+}
+
+// diffTypes compares types along with their methods and struct fields.
+// Types are compared by their declaration as summarized by
+// [PackageDoc.APISignature], along with their embedded interfaces.
+func diffTypes(changes []APIChange, oldTypes, newTypes []TypeDoc) []APIChange {
+	entries := func(types []TypeDoc) map[string]diffEntry {
+		m := make(map[string]diffEntry, len(types))
+		for _, t := range types {
+			if !token.IsExported(t.Name) {
+				continue
+			}
+
+			decl := apiTypeDecl(t)
+			embeds := slices.Sorted(slices.Values(t.Embeds))
+			m[t.Name] = diffEntry{decl: decl, key: strings.Join(append([]string{decl}, embeds...), "\n")}
+		}
+
+		return m
+	}
+
+	oldEntries, newEntries := entries(oldTypes), entries(newTypes)
+	changes = diffEntries(changes, "type", oldEntries, newEntries, false)
+
+	newByName := make(map[string]TypeDoc, len(newTypes))
+	for _, t := range newTypes {
+		newByName[t.Name] = t
+	}
+
+	for _, o := range oldTypes {
+		n, ok := newByName[o.Name]
+		if !ok || o.Kind != n.Kind {
+			// Already reported as a removed or changed type.
+			continue
+		}
+
+		// Adding a method to an interface breaks its implementations.
+		changes = diffEntries(changes, "method", methodEntries(o), methodEntries(n), o.Kind == "interface")
+		changes = diffEntries(changes, "field", fieldEntries(o), fieldEntries(n), false)
+	}
+
+	return changes
+}
+
+// methodEntries returns the diff entries for the methods of t.
+func methodEntries(t TypeDoc) map[string]diffEntry {
+	m := make(map[string]diffEntry, len(t.Methods))
+	for _, meth := range t.Methods {
+		if !token.IsExported(meth.Name) {
+			continue
+		}
+
+		m[t.Name+"."+meth.Name] = diffEntry{
+			decl: methodSignature(meth),
+			key:  signatureKey(meth.RecvType, nil, meth.Args, meth.Returns),
+		}
+	}
+
+	return m
+}
+
+// fieldEntries returns the diff entries for the struct fields of t.
+func fieldEntries(t TypeDoc) map[string]diffEntry {
+	m := make(map[string]diffEntry, len(t.Fields))
+	for _, f := range t.Fields {
+		exported := token.IsExported(f.Name)
+		if f.Embedded {
+			exported = isExportedTypeName(f.Type)
+		}
+
+		if !exported {
+			continue
+		}
+
+		m[t.Name+"."+f.Name] = diffEntry{decl: f.Name + " " + f.Type, key: f.Type}
+	}
+
+	return m
+}
+
+// signatureKey returns a comparison key for a signature made of its receiver,
+// type parameters, and argument and result types, ignoring parameter names.
+func signatureKey(recvType string, typeParams, args, returns []ArgInfo) string {
+	var sb strings.Builder
+	sb.WriteString(recvType)
+	sb.WriteString(formatTypeParams(typeParams))
+	sb.WriteByte('(')
+	for i, arg := range args {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		sb.WriteString(arg.Type)
+	}
+	sb.WriteString(")(")
+	for i, ret := range returns {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		sb.WriteString(ret.Type)
+	}
+	sb.WriteByte(')')
+
+	return sb.String()
+}
+
+// APISignature returns a stable textual summary of the exported API of the
+// package, suitable for comparing versions with diff.
+//
+// The summary lists one declaration per line without doc comments: constants
+// with their values, variables, functions, and types, each sorted by name.
+// Struct fields and interface methods are indented under their type, which is
+// followed by its methods. Parameter names are omitted, so that two versions
+// with the same API produce the same summary.
+func (p PackageDoc) APISignature() string {
+	var lines []string
+
+	for _, kind := range []string{"const", "var"} {
+		values := p.Consts
+		if kind == "var" {
+			values = p.Vars
+		}
+
+		var decls []string
+		for _, v := range values {
+			for i, name := range v.Names {
+				if !token.IsExported(name) {
+					continue
+				}
+
+				// Variable initializers are not part of the API.
+				var val string
+				if kind == "const" && i < len(v.Values) {
+					val = v.Values[i]
+				}
+
+				decls = append(decls, valueDecl(kind, name, val))
+			}
+		}
+
+		sort.Strings(decls)
+		lines = append(lines, decls...)
+	}
+
+	var funcs []string
+	for _, f := range p.Funcs {
+		if token.IsExported(f.Name) {
+			f.Args, f.Returns = unnamedArgs(f.Args), unnamedArgs(f.Returns)
+			funcs = append(funcs, funcSignature(f))
+		}
+	}
+
+	sort.Strings(funcs)
+	lines = append(lines, funcs...)
+
+	types := make([]TypeDoc, 0, len(p.Types))
+	for _, t := range p.Types {
+		if token.IsExported(t.Name) {
+			types = append(types, t)
+		}
+	}
+
+	sort.Slice(types, func(i, j int) bool { return types[i].Name < types[j].Name })
+	for _, t := range types {
+		lines = append(lines, apiTypeLines(t)...)
+	}
+
+	if len(lines) == 0 {
+		return ""
+	}
+
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// apiTypeDecl returns the declaration of the type t in the API summary,
+// without the members of structs and interfaces.
+func apiTypeDecl(t TypeDoc) string {
+	name := t.Name + formatTypeParams(t.TypeParams)
+
+	switch t.Kind {
+	case "struct", "interface":
+		return "type " + name + " " + t.Kind
+	case "alias":
+		return "type " + name + " = " + t.AliasTarget
+	default:
+		return strings.Join(strings.Fields(t.Decl), " ")
+	}
+}
+
+// apiTypeLines returns the lines of the API summary for the type t.
+func apiTypeLines(t TypeDoc) []string {
+	lines := []string{apiTypeDecl(t)}
+
+	var members []string
+
+	for _, f := range t.Fields {
+		switch {
+		case f.Embedded && isExportedTypeName(f.Type):
+			members = append(members, "\t"+f.Type)
+		case !f.Embedded && token.IsExported(f.Name):
+			members = append(members, "\t"+f.Name+" "+f.Type)
+		}
+	}
+
+	for _, e := range t.Embeds {
+		members = append(members, "\t"+e)
+	}
+
+	var methods []string
+	for _, m := range t.Methods {
+		if !token.IsExported(m.Name) {
+			continue
+		}
+
+		m.RecvName = ""
+		m.Args, m.Returns = unnamedArgs(m.Args), unnamedArgs(m.Returns)
+		if t.Kind == "interface" {
+			members = append(members, "\t"+m.Name+"("+formatParams(m.Args)+")"+formatResults(m.Returns))
+		} else {
+			methods = append(methods, methodSignature(m))
+		}
+	}
+
+	sort.Strings(members)
+	sort.Strings(methods)
+
+	return append(append(lines, members...), methods...)
+}
+
+// isExportedTypeName reports whether the possibly qualified type name,
+// such as "*bytes.Buffer", denotes an exported type.
+func isExportedTypeName(name string) bool {
+	name = strings.TrimPrefix(name, "*")
+	if i := strings.LastIndexByte(name, '.'); i >= 0 {
+		name = name[i+1:]
+	}
+
+	if i := strings.IndexByte(name, '['); i >= 0 {
+		name = name[:i]
+	}
+
+	return token.IsExported(name)
+}
+
+// unnamedArgs returns a copy of args without their names.
+func unnamedArgs(args []ArgInfo) []ArgInfo {
+	out := make([]ArgInfo, len(args))
+	for i, arg := range args {
+		out[i] = ArgInfo{Type: arg.Type}
+	}
+
+	return out
+}
@@ -0,0 +1,194 @@
+package godoc
+
+import "testing"
+
+func TestDiffAPI(t *testing.T) {
+	oldPkg := PackageDoc{
+		Consts: []ValueDoc{{Names: []string{"A", "B"}, Values: []string{"0", "1"}}},
+		Vars:   []ValueDoc{{Names: []string{"ErrGone"}}},
+		Funcs: []FuncDoc{
+			{Name: "Keep", Args: []ArgInfo{{Name: "s", Type: "string"}}},
+			{Name: "Change", Args: []ArgInfo{{Name: "n", Type: "int"}}},
+			{Name: "Drop"},
+		},
+		Types: []TypeDoc{
+			{
+				Name:    "Config",
+				Kind:    "struct",
+				Fields:  []FieldDoc{{Name: "Name", Type: "string"}, {Name: "Port", Type: "int"}},
+				Methods: []MethodDoc{{Recv: "Config", RecvType: "*Config", Name: "Validate", Returns: []ArgInfo{{Type: "error"}}}},
+			},
+			{Name: "Reader", Kind: "interface", Methods: []MethodDoc{{Recv: "Reader", Name: "Read"}}},
+		},
+	}
+
+	newPkg := PackageDoc{
+		Consts: []ValueDoc{{Names: []string{"A", "B", "C"}, Values: []string{"0", "2", "3"}}},
+		Funcs: []FuncDoc{
+			{Name: "Keep", Args: []ArgInfo{{Name: "name", Type: "string"}}},
+			{Name: "Change", Args: []ArgInfo{{Name: "n", Type: "int64"}}},
+			{Name: "Add"},
+		},
+		Types: []TypeDoc{
+			{
+				Name:    "Config",
+				Kind:    "struct",
+				Fields:  []FieldDoc{{Name: "Name", Type: "string"}, {Name: "Timeout", Type: "time.Duration"}},
+				Methods: []MethodDoc{{Recv: "Config", RecvType: "*Config", Name: "Validate", Returns: []ArgInfo{{Name: "err", Type: "error"}}}},
+			},
+			{Name: "Reader", Kind: "interface", Methods: []MethodDoc{{Recv: "Reader", Name: "Read"}, {Recv: "Reader", Name: "Close"}}},
+		},
+	}
+
+	diff := DiffAPI(oldPkg, newPkg)
+
+	want := []struct {
+		symbol, change string
+		breaking       bool
+	}{
+		{"Add", "added", false},
+		{"B", "changed", true},
+		{"C", "added", false},
+		{"Change", "changed", true},
+		{"Config.Port", "removed", true},
+		{"Config.Timeout", "added", false},
+		{"Drop", "removed", true},
+		{"ErrGone", "removed", true},
+		{"Reader.Close", "added", true},
+	}
+
+	if len(diff.Changes) != len(want) {
+		t.Fatalf("expected %d changes, got %d: %+v", len(want), len(diff.Changes), diff.Changes)
+	}
+
+	for i, w := range want {
+		got := diff.Changes[i]
+		if got.Symbol != w.symbol || got.Change != w.change || got.Breaking != w.breaking {
+			t.Errorf("change %d: expected %s %s (breaking=%v), got %+v", i, w.symbol, w.change, w.breaking, got)
+		}
+	}
+
+	if diff.Breaking != 6 || diff.NonBreaking != 3 || !diff.HasBreaking() {
+		t.Fatalf("expected 6 breaking and 3 non-breaking changes, got %d and %d", diff.Breaking, diff.NonBreaking)
+	}
+
+	if same := DiffAPI(oldPkg, oldPkg); len(same.Changes) != 0 || same.HasBreaking() {
+		t.Fatalf("expected no changes when comparing a package with itself, got %+v", same.Changes)
+	}
+}
+
+func TestDiffAPITypes(t *testing.T) {
+	anyT := []ArgInfo{{Name: "T", Type: "any"}}
+	comparableT := []ArgInfo{{Name: "T", Type: "comparable"}}
+
+	oldPkg := PackageDoc{
+		Funcs: []FuncDoc{
+			{Name: "Map", TypeParams: anyT, Args: []ArgInfo{{Name: "v", Type: "T"}}},
+			{Name: "helper"},
+		},
+		Types: []TypeDoc{
+			{Name: "Mode", Kind: "other", Decl: "type Mode int"},
+			{Name: "Alias", Kind: "alias", AliasTarget: "int"},
+			{Name: "ReadCloser", Kind: "interface", Embeds: []string{"io.Reader"}},
+			{Name: "List", Kind: "struct", TypeParams: anyT},
+			{Name: "state", Kind: "struct"},
+		},
+	}
+
+	newPkg := PackageDoc{
+		Funcs: []FuncDoc{
+			{Name: "Map", TypeParams: comparableT, Args: []ArgInfo{{Name: "v", Type: "T"}}},
+		},
+		Types: []TypeDoc{
+			{Name: "Mode", Kind: "other", Decl: "type Mode string"},
+			{Name: "Alias", Kind: "alias", AliasTarget: "int64"},
+			{Name: "ReadCloser", Kind: "interface", Embeds: []string{"io.Closer", "io.Reader"}},
+			{Name: "List", Kind: "struct", TypeParams: comparableT},
+		},
+	}
+
+	diff := DiffAPI(oldPkg, newPkg)
+
+	want := []string{"Alias", "List", "Map", "Mode", "ReadCloser"}
+	if len(diff.Changes) != len(want) {
+		t.Fatalf("expected %d changes, got %d: %+v", len(want), len(diff.Changes), diff.Changes)
+	}
+
+	for i, name := range want {
+		got := diff.Changes[i]
+		if got.Symbol != name || got.Change != "changed" || !got.Breaking {
+			t.Errorf("change %d: expected %s changed (breaking), got %+v", i, name, got)
+		}
+	}
+
+	if got := diff.Changes[3]; got.Old != "type Mode int" || got.New != "type Mode string" {
+		t.Errorf("expected the declarations of Mode, got %q and %q", got.Old, got.New)
+	}
+}
+
+func TestAPISignature(t *testing.T) {
+	pkg := PackageDoc{
+		DocText: "Package p does things.",
+		Consts:  []ValueDoc{{Names: []string{"B", "A", "hidden"}, Values: []string{"1", "0", "2"}}},
+		Vars:    []ValueDoc{{Names: []string{"ErrGone"}, Values: []string{`errors.New("gone")`}}},
+		Funcs: []FuncDoc{
+			{Name: "New", Args: []ArgInfo{{Name: "name", Type: "string"}}, Returns: []ArgInfo{{Type: "*Config"}}, Doc: "New makes a Config."},
+			{Name: "Map", TypeParams: []ArgInfo{{Name: "T", Type: "any"}}, Args: []ArgInfo{{Name: "s", Type: "[]T"}}},
+			{Name: "helper"},
+		},
+		Types: []TypeDoc{
+			{Name: "Reader", Kind: "interface", Embeds: []string{"io.Closer"}, Methods: []MethodDoc{{Recv: "Reader", Name: "Read", Args: []ArgInfo{{Name: "p", Type: "[]byte"}}, Returns: []ArgInfo{{Name: "n", Type: "int"}, {Name: "err", Type: "error"}}}}},
+			{
+				Name: "Config",
+				Kind: "struct",
+				Doc:  "Config configures things.",
+				Fields: []FieldDoc{
+					{Name: "Port", Type: "int", Doc: "Port to listen on."},
+					{Name: "name", Type: "string"},
+					{Name: "Mutex", Type: "sync.Mutex", Embedded: true},
+					{Name: "inner", Type: "*inner", Embedded: true},
+				},
+				Methods: []MethodDoc{
+					{Recv: "Config", RecvName: "c", RecvType: "*Config", Name: "Validate", Returns: []ArgInfo{{Name: "err", Type: "error"}}},
+					{Recv: "Config", RecvType: "Config", Name: "reset"},
+				},
+			},
+			{Name: "Duration", Kind: "other", Decl: "type Duration int64"},
+			{Name: "Byte", Kind: "alias", AliasTarget: "uint8"},
+			{Name: "internal", Kind: "struct"},
+		},
+	}
+
+	want := `const A = 0
+const B = 1
+var ErrGone
+func Map[T any]([]T)
+func New(string) *Config
+type Byte = uint8
+type Config struct
+	Port int
+	sync.Mutex
+func (*Config) Validate() error
+type Duration int64
+type Reader interface
+	Read([]byte) (int, error)
+	io.Closer
+`
+	if got := pkg.APISignature(); got != want {
+		t.Fatalf("unexpected API signature:\n%s\nwant:\n%s", got, want)
+	}
+
+	// Documentation and parameter names are not part of the API.
+	other := pkg
+	other.DocText = ""
+	other.Funcs = append([]FuncDoc(nil), pkg.Funcs...)
+	other.Funcs[0].Args = []ArgInfo{{Name: "label", Type: "string"}}
+	other.Funcs[0].Doc = ""
+	if got := other.APISignature(); got != want {
+		t.Fatalf("expected identical API signature, got:\n%s", got)
+	}
+
+	if got := (PackageDoc{}).APISignature(); got != "" {
+		t.Fatalf("expected empty signature for an empty package, got %q", got)
+	}
+}
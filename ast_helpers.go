@@ -3,6 +3,7 @@ package godoc
 import (
 	"go/ast"
 	"go/token"
+	"go/types"
 	"strings"
 	"sync"
 
@@ -13,7 +14,24 @@ import (
 type packageAST struct {
 	fset        *token.FileSet
 	files       []*ast.File
-	commentMaps sync.Map // *ast.File -> ast.CommentMap
+	testFiles   []*ast.File // Parsed _test.go files
+	commentMaps sync.Map    // *ast.File -> ast.CommentMap
+
+	buildConstraints []string // "//go:build" expressions of files
+
+	// consts holds the constants evaluated by [evalConsts] when the
+	// package is loaded without type information.
+	consts *types.Info
+}
+
+// valuesInfo returns the type information to evaluate the values of the
+// package with: typesInfo if available, or the evaluated constants.
+func (a *packageAST) valuesInfo(typesInfo *types.Info) *types.Info {
+	if typesInfo != nil || a == nil {
+		return typesInfo
+	}
+
+	return a.consts
 }
 
 // buildPkgAST constructs a [packageAST] from the given [packages.Package] and its
@@ -123,3 +141,184 @@ func commentTextForNode(node ast.Node, astInfo *packageAST) string {
 
 	return b.String()
 }
+
+// declPosition returns the [Position] of pos, or nil if it is unknown.
+func declPosition(fset *token.FileSet, pos token.Pos) *Position {
+	if fset == nil || !pos.IsValid() {
+		return nil
+	}
+
+	p := fset.Position(pos)
+
+	return &Position{File: p.Filename, Line: p.Line, Column: p.Column}
+}
+
+// valueNamePos returns the position of the given name in a const or var
+// declaration.
+func valueNamePos(decl *ast.GenDecl, name string) token.Pos {
+	vs := valueSpec(decl, name)
+	if vs == nil {
+		return token.NoPos
+	}
+
+	for _, ident := range vs.Names {
+		if ident.Name == name {
+			return ident.Pos()
+		}
+	}
+
+	return token.NoPos
+}
+
+// valueSpec returns the spec declaring the named constant or variable in a
+// const or var declaration.
+func valueSpec(decl *ast.GenDecl, name string) *ast.ValueSpec {
+	if decl == nil {
+		return nil
+	}
+
+	for _, spec := range decl.Specs {
+		vs, ok := spec.(*ast.ValueSpec)
+		if !ok {
+			continue
+		}
+
+		for _, ident := range vs.Names {
+			if ident.Name == name {
+				return vs
+			}
+		}
+	}
+
+	return nil
+}
+
+// interfaceMethodPos returns the position of the named method in the
+// declaration of an interface type.
+func interfaceMethodPos(spec *ast.TypeSpec, name string) token.Pos {
+	if field := interfaceMethodField(spec, name); field != nil {
+		for _, ident := range field.Names {
+			if ident.Name == name {
+				return ident.Pos()
+			}
+		}
+	}
+
+	return token.NoPos
+}
+
+// interfaceMethodField returns the field declaring the named method in the
+// declaration of an interface type.
+func interfaceMethodField(spec *ast.TypeSpec, name string) *ast.Field {
+	if spec == nil {
+		return nil
+	}
+
+	iface, ok := spec.Type.(*ast.InterfaceType)
+	if !ok || iface.Methods == nil {
+		return nil
+	}
+
+	for _, field := range iface.Methods.List {
+		for _, ident := range field.Names {
+			if ident.Name == name {
+				return field
+			}
+		}
+	}
+
+	return nil
+}
+
+// typeRefs returns the names in local referenced by the declaration node,
+// in order of first appearance. Only the signatures of functions are
+// considered, and the names of declared fields, parameters, and values are
+// ignored.
+func typeRefs(node ast.Node, local map[string]bool) []string {
+	var refs []string
+	seen := make(map[string]bool)
+
+	var visit func(ast.Node) bool
+	visit = func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.Ident:
+			if local[n.Name] && !seen[n.Name] {
+				seen[n.Name] = true
+				refs = append(refs, n.Name)
+			}
+		case *ast.FuncDecl:
+			if n.Recv != nil {
+				ast.Inspect(n.Recv, visit)
+			}
+
+			ast.Inspect(n.Type, visit)
+
+			return false
+		case *ast.FuncLit:
+			ast.Inspect(n.Type, visit)
+
+			return false
+		case *ast.TypeSpec:
+			if n.TypeParams != nil {
+				ast.Inspect(n.TypeParams, visit)
+			}
+
+			ast.Inspect(n.Type, visit)
+
+			return false
+		case *ast.ValueSpec:
+			if n.Type != nil {
+				ast.Inspect(n.Type, visit)
+			}
+
+			for _, value := range n.Values {
+				ast.Inspect(value, visit)
+			}
+
+			return false
+		case *ast.Field:
+			ast.Inspect(n.Type, visit)
+
+			return false
+		case *ast.SelectorExpr:
+			ast.Inspect(n.X, visit)
+
+			return false
+		case *ast.KeyValueExpr:
+			ast.Inspect(n.Value, visit)
+
+			return false
+		}
+
+		return true
+	}
+
+	ast.Inspect(node, visit)
+
+	return refs
+}
+
+// rawCommentText returns the comments attached to the given field verbatim,
+// one comment per line and including comment markers and directives (such as
+// "//go:" pragmas or "//nolint"), which [ast.CommentGroup.Text] drops.
+func rawCommentText(field *ast.Field, astInfo *packageAST) string {
+	groups := []*ast.CommentGroup{field.Doc, field.Comment}
+	if field.Doc == nil && field.Comment == nil && astInfo != nil {
+		if comments := astInfo.commentMapFor(field); comments != nil {
+			groups = comments[field]
+		}
+	}
+
+	var lines []string
+	for _, group := range groups {
+		if group == nil {
+			continue
+		}
+
+		for _, c := range group.List {
+			lines = append(lines, c.Text)
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
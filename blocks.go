@@ -0,0 +1,117 @@
+package godoc
+
+import "go/doc/comment"
+
+// DocBlock is a block of a parsed doc comment, for rendering documentation
+// in a custom format.
+type DocBlock struct {
+	// Kind is "paragraph", "heading", "code", or "list".
+	Kind string `json:"kind" jsonschema:"block kind (paragraph, heading, code, or list)"`
+
+	// Text is the plain text of a paragraph or heading, or the code of a
+	// code block.
+	Text string `json:"text,omitempty" jsonschema:"text of a paragraph or heading, or code of a code block"`
+
+	// ID is the anchor of a heading, as assigned by
+	// [comment.Heading.DefaultID].
+	ID string `json:"id,omitempty" jsonschema:"anchor of a heading"`
+
+	// Links are the links of a paragraph or heading, in order of
+	// appearance.
+	Links []DocBlockLink `json:"links,omitempty" jsonschema:"links of a paragraph or heading"`
+
+	// Items are the blocks of each item of a list, which is numbered if
+	// Ordered is set.
+	Items   [][]DocBlock `json:"items,omitempty" jsonschema:"blocks of each list item"`
+	Ordered bool         `json:"ordered,omitempty" jsonschema:"whether the list is numbered"`
+}
+
+// DocBlockLink is a link of a doc comment: a URL, or a doc link to a
+// package or symbol, such as [io.Reader], which has its ImportPath and Name
+// set.
+type DocBlockLink struct {
+	Text       string `json:"text" jsonschema:"link text"`
+	URL        string `json:"url" jsonschema:"link URL; doc links point to pkg.go.dev or to an anchor of the package"`
+	ImportPath string `json:"import_path,omitempty" jsonschema:"import path of the package of a doc link"`
+	Name       string `json:"name,omitempty" jsonschema:"symbol of a doc link (e.g. Reader or Reader.Read)"`
+}
+
+// Blocks returns the blocks of the package comment, such as paragraphs,
+// headings, code blocks, and lists, to render it in a custom format.
+func (p PackageDoc) Blocks() []DocBlock {
+	parsed := p.docParsed
+	if parsed == nil && p.DocText != "" {
+		parsed = parseDocText(p.DocText, p.lookupSym)
+	}
+
+	return docBlocks(parsed)
+}
+
+// Blocks returns the blocks of the doc comment of the symbol, like
+// [PackageDoc.Blocks].
+func (s SymbolDoc) Blocks() []DocBlock {
+	parsed := s.docParsed
+	if parsed == nil && s.DocText != "" {
+		parsed = parseDocText(s.DocText, s.lookupSym)
+	}
+
+	return docBlocks(parsed)
+}
+
+// docBlocks converts the blocks of the given *[comment.Doc].
+func docBlocks(d *comment.Doc) []DocBlock {
+	if d == nil {
+		return nil
+	}
+
+	return convertBlocks(d.Content)
+}
+
+// convertBlocks converts the given [comment.Block] values.
+func convertBlocks(blocks []comment.Block) []DocBlock {
+	out := make([]DocBlock, 0, len(blocks))
+	for _, block := range blocks {
+		switch b := block.(type) {
+		case *comment.Paragraph:
+			out = append(out, DocBlock{Kind: "paragraph", Text: manText(b.Text), Links: textLinks(nil, b.Text)})
+		case *comment.Heading:
+			out = append(out, DocBlock{Kind: "heading", Text: manText(b.Text), ID: b.DefaultID(), Links: textLinks(nil, b.Text)})
+		case *comment.Code:
+			out = append(out, DocBlock{Kind: "code", Text: b.Text})
+		case *comment.List:
+			list := DocBlock{Kind: "list", Items: make([][]DocBlock, 0, len(b.Items))}
+			for _, item := range b.Items {
+				list.Items = append(list.Items, convertBlocks(item.Content))
+				list.Ordered = list.Ordered || item.Number != ""
+			}
+
+			out = append(out, list)
+		}
+	}
+
+	return out
+}
+
+// textLinks appends the links found in the given [comment.Text] spans.
+func textLinks(links []DocBlockLink, text []comment.Text) []DocBlockLink {
+	for _, t := range text {
+		switch t := t.(type) {
+		case *comment.Link:
+			links = append(links, DocBlockLink{Text: manText(t.Text), URL: t.URL})
+		case *comment.DocLink:
+			name := t.Name
+			if t.Recv != "" {
+				name = t.Recv + "." + name
+			}
+
+			links = append(links, DocBlockLink{
+				Text:       manText(t.Text),
+				URL:        t.DefaultURL(docLinkBaseURL),
+				ImportPath: t.ImportPath,
+				Name:       name,
+			})
+		}
+	}
+
+	return links
+}
@@ -0,0 +1,52 @@
+package godoc
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBlocks(t *testing.T) {
+	p := PackageDoc{
+		Name:    "foo",
+		DocText: "Package foo wraps [io.Reader] and [Client.Do], see https://example.com.\n\n# Usage\n\nSteps:\n  1. Call [New].\n  2. Close it.\n\nFor example:\n\n\tc := foo.New()\n",
+		Funcs:   []FuncDoc{{Name: "New"}},
+		Types:   []TypeDoc{{Name: "Client", Methods: []MethodDoc{{Name: "Do"}}}},
+	}
+
+	want := []DocBlock{
+		{
+			Kind: "paragraph",
+			Text: "Package foo wraps io.Reader and Client.Do, see https://example.com.",
+			Links: []DocBlockLink{
+				{Text: "io.Reader", URL: "https://pkg.go.dev/io#Reader", ImportPath: "io", Name: "Reader"},
+				{Text: "Client.Do", URL: "#Client.Do", Name: "Client.Do"},
+				{Text: "https://example.com", URL: "https://example.com"},
+			},
+		},
+		{Kind: "heading", Text: "Usage", ID: "hdr-Usage"},
+		{Kind: "paragraph", Text: "Steps:"},
+		{
+			Kind: "list",
+			Items: [][]DocBlock{
+				{{Kind: "paragraph", Text: "Call New.", Links: []DocBlockLink{{Text: "New", URL: "#New", Name: "New"}}}},
+				{{Kind: "paragraph", Text: "Close it."}},
+			},
+			Ordered: true,
+		},
+		{Kind: "paragraph", Text: "For example:"},
+		{Kind: "code", Text: "c := foo.New()\n"},
+	}
+
+	if got := p.Blocks(); !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected blocks:\n got %+v\nwant %+v", got, want)
+	}
+
+	s := SymbolDoc{DocText: "Do does it.\n"}
+	if got := s.Blocks(); len(got) != 1 || got[0].Kind != "paragraph" || got[0].Text != "Do does it." {
+		t.Errorf("unexpected symbol blocks %+v", got)
+	}
+
+	if got := (SymbolDoc{}).Blocks(); got != nil {
+		t.Errorf("expected no blocks without documentation, got %+v", got)
+	}
+}
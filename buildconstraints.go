@@ -0,0 +1,38 @@
+package godoc
+
+import (
+	"go/ast"
+	"go/build/constraint"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// collectBuildConstraints returns the distinct "//go:build" expressions of the
+// given files, such as "linux && amd64", sorted. Only the comments preceding
+// the package clause are considered, as for the go command.
+func collectBuildConstraints(files []*ast.File) []string {
+	var exprs []string
+	for _, f := range files {
+		for _, group := range f.Comments {
+			if group.Pos() >= f.Package {
+				break
+			}
+
+			for _, c := range group.List {
+				if !constraint.IsGoBuild(c.Text) {
+					continue
+				}
+
+				expr := strings.TrimSpace(strings.TrimPrefix(c.Text, "//go:build"))
+				if !slices.Contains(exprs, expr) {
+					exprs = append(exprs, expr)
+				}
+			}
+		}
+	}
+
+	sort.Strings(exprs)
+
+	return exprs
+}
@@ -0,0 +1,56 @@
+package godoc
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"slices"
+	"testing"
+)
+
+func TestCollectBuildConstraints(t *testing.T) {
+	srcs := map[string]string{
+		"a.go": "// Copyright notice.\n\n//go:build linux && amd64\n\n// Package p does things.\npackage p\n\n//go:build ignored\nvar x int\n",
+		"b.go": "//go:build unix\n// +build unix\n\npackage p\n",
+		"c.go": "//go:build linux && amd64\n\npackage p\n",
+		"d.go": "package p\n",
+	}
+
+	fset := token.NewFileSet()
+	var files []*ast.File
+	for name, src := range srcs {
+		f, err := parser.ParseFile(fset, name, src, parser.ParseComments)
+		if err != nil {
+			t.Fatalf("parse %s failed: %v", name, err)
+		}
+
+		files = append(files, f)
+	}
+
+	got := collectBuildConstraints(files)
+	if want := []string{"linux && amd64", "unix"}; !slices.Equal(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+
+	if got := collectBuildConstraints(nil); got != nil {
+		t.Fatalf("expected nil without files, got %v", got)
+	}
+}
+
+func TestBuildConstraintsForTarget(t *testing.T) {
+	g := New(WithGOOS("linux"), WithGOARCH("amd64"), WithCacheMode(CacheDisabled))
+
+	pkg, err := g.Load("syscall", "", "")
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	doc := pkg.(PackageDoc)
+	if !slices.Contains(doc.BuildConstraints, "unix") {
+		t.Fatalf("expected the unix constraint for linux/amd64, got %v", doc.BuildConstraints)
+	}
+
+	if slices.Contains(doc.BuildConstraints, "windows") {
+		t.Fatalf("expected files for windows to be excluded, got %v", doc.BuildConstraints)
+	}
+}
@@ -5,10 +5,15 @@ import (
 	"fmt"
 	"hash/fnv"
 	"io/fs"
+	"maps"
 	"os"
 	"path/filepath"
 	"runtime"
+	"slices"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"go.dw1.io/fastcache"
 	"golang.org/x/tools/go/packages"
@@ -16,23 +21,226 @@ import (
 
 const (
 	cacheMaxEntries = 10_000
+
+	// cacheFormatVersion is mixed into every cache key. Bump it whenever the
+	// shape of the cached documentation changes so that stale persisted
+	// entries are not served.
+	cacheFormatVersion = "29"
+
+	// symbolIndexSel is the selector keying the symbol index of a package,
+	// which no symbol selector can collide with.
+	symbolIndexSel = "*"
+)
+
+// CacheMode selects how a [Godoc] instance caches the documentation it
+// loads.
+type CacheMode int
+
+const (
+	// CachePersistent caches documentation in memory and persists it to a
+	// file in the user cache directory, so that it is reused across
+	// processes. It is the default.
+	CachePersistent CacheMode = iota
+
+	// CacheMemory caches documentation in memory only, for the lifetime of
+	// the process. The cache file is neither read nor written.
+	CacheMemory
+
+	// CacheDisabled disables caching: documentation is rebuilt on every
+	// load.
+	CacheDisabled
 )
 
 // cacheMetadata holds metadata about the cached entry.
 type cacheMetadata struct {
 	GoVersion     string
 	ModuleVersion string
+	BuiltAt       time.Time
+
+	// Typed reports whether the package was loaded with type information,
+	// which the symbol index needs for the symbols it derives from types.
+	Typed bool
 }
 
 // cacheEntry represents a cached documentation entry.
 type cacheEntry struct {
 	Package *PackageDoc
 	Symbol  *SymbolDoc
+	Symbols map[string]SymbolDoc // Symbol index of a package, by selector
 	cacheMetadata
 }
 
-// getCache initializes and returns the global cache instance.
-func getCache() (*fastcache.Cache[string, cacheEntry], error) {
+// importPath returns the import path of the package documented by the entry.
+func (e cacheEntry) importPath() string {
+	switch {
+	case e.Package != nil:
+		return e.Package.ImportPath
+	case e.Symbol != nil:
+		return e.Symbol.ImportPath
+	case len(e.Symbols) > 0:
+		for _, sym := range e.Symbols {
+			return sym.ImportPath
+		}
+
+		return ""
+	default:
+		return ""
+	}
+}
+
+// ClearCache drops all cached documentation, both in memory and on disk, so
+// that subsequent loads rebuild it. It is safe for concurrent use.
+func ClearCache() error {
+	cache, err := getCache(cacheMaxEntries)
+	if err != nil {
+		return err
+	}
+
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+
+	cache.Reset()
+	if memoryCache != nil {
+		memoryCache.Reset()
+	}
+	stdlibCache.DeleteFunc(func(cacheEntry) bool { return true })
+
+	var paths []string
+	if cachePersistent && cacheFilePath != "" {
+		paths = append(paths, cacheFilePath)
+	}
+
+	forEachDirCache(func(c *dirCache) {
+		c.cache.Reset()
+		if c.path != "" {
+			paths = append(paths, c.path)
+		}
+	})
+
+	for _, path := range paths {
+		if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("could not remove cache file: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// InvalidateImportPath drops the cached documentation of the package with the
+// given import path, for all versions and symbols. It is safe for concurrent
+// use.
+func InvalidateImportPath(importPath string) error {
+	cache, err := getCache(cacheMaxEntries)
+	if err != nil {
+		return err
+	}
+
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+
+	matches := func(entry cacheEntry) bool {
+		return entry.importPath() == importPath
+	}
+
+	stdlibCache.DeleteFunc(matches)
+
+	if memoryCache != nil {
+		deleteCacheEntries(memoryCache, matches)
+	}
+
+	var saveErr error
+	forEachDirCache(func(c *dirCache) {
+		if deleteCacheEntries(c.cache, matches) > 0 && c.path != "" && saveErr == nil {
+			saveErr = c.cache.SaveToFile(c.path)
+		}
+	})
+
+	if saveErr != nil {
+		return saveErr
+	}
+
+	if deleteCacheEntries(cache, matches) == 0 || !cachePersistent || cacheFilePath == "" {
+		return nil
+	}
+
+	return cache.SaveToFile(cacheFilePath)
+}
+
+// deleteCacheEntries deletes the entries of cache that match, returning the
+// number of deleted entries.
+func deleteCacheEntries(cache *fastcache.Cache[string, cacheEntry], matches func(cacheEntry) bool) int {
+	// Entries are collected first, as the cache is locked while iterating.
+	var keys []string
+	for key, entry := range cache.All() {
+		if matches(entry) {
+			keys = append(keys, key)
+		}
+	}
+
+	for _, key := range keys {
+		cache.Delete(key)
+	}
+
+	return len(keys)
+}
+
+// cache returns the cache of the instance according to its [CacheMode], or
+// nil if caching is disabled.
+func (d *Godoc) cache() (*fastcache.Cache[string, cacheEntry], error) {
+	if d.cacheDisabled() {
+		return nil, nil
+	}
+
+	maxEntries := d.maxCacheEntries()
+
+	switch d.cacheMode {
+	case CacheMemory:
+		return getMemoryCache(maxEntries), nil
+	}
+
+	if d.cacheDir != "" {
+		c, err := getDirCache(d.cacheDir, maxEntries)
+		if err != nil {
+			return nil, err
+		}
+
+		return c.cache, nil
+	}
+
+	return getCache(maxEntries)
+}
+
+// cacheDisabled reports whether documentation is rebuilt on every load,
+// either as set with [WithCacheMode] or because it is read from a source file
+// system set with [WithSource], which cache keys cannot tell apart.
+func (d *Godoc) cacheDisabled() bool {
+	return d.cacheMode == CacheDisabled || d.source != nil
+}
+
+// maxCacheEntries returns the maximum number of entries of the caches
+// created by the instance, as set with [WithMaxCacheSize].
+func (d *Godoc) maxCacheEntries() int {
+	if d.cacheSize > 0 {
+		return d.cacheSize
+	}
+
+	return cacheMaxEntries
+}
+
+// getMemoryCache initializes and returns the global in-memory cache used in
+// [CacheMemory] mode, which is never persisted. The cache holds at most
+// maxEntries entries if it is initialized by this call.
+func getMemoryCache(maxEntries int) *fastcache.Cache[string, cacheEntry] {
+	memoryCacheOnce.Do(func() {
+		memoryCache = fastcache.New[string, cacheEntry](maxEntries)
+	})
+
+	return memoryCache
+}
+
+// getCache initializes and returns the global cache instance. The cache
+// holds at most maxEntries entries if it is initialized by this call.
+func getCache(maxEntries int) (*fastcache.Cache[string, cacheEntry], error) {
 	var cacheInitErr error
 
 	cacheOnce.Do(func() {
@@ -43,25 +251,15 @@ func getCache() (*fastcache.Cache[string, cacheEntry], error) {
 			return
 		}
 
-		if err := os.MkdirAll(dir, 0o755); err != nil {
-			cacheInitErr = fmt.Errorf("could not create cache directory: %w", err)
-
-			return
-		}
-
-		cacheFilePath = filepath.Join(dir, "cache.gob")
-		cachePersistent = true
-
-		cache, err := loadCacheFromFile(cacheFilePath, cacheMaxEntries)
+		cache, path, err := openCache(dir, maxEntries)
 		if err != nil {
-			if !errors.Is(err, fs.ErrNotExist) {
-				cachePersistent = false
-				cacheFilePath = ""
-			}
+			cacheInitErr = err
 
-			cache = fastcache.New[string, cacheEntry](cacheMaxEntries)
+			return
 		}
 
+		cacheFilePath = path
+		cachePersistent = path != ""
 		globalCache = cache
 	})
 
@@ -72,6 +270,71 @@ func getCache() (*fastcache.Cache[string, cacheEntry], error) {
 	return globalCache, nil
 }
 
+// dirCache is the persistent cache of a directory set with [WithCacheDir].
+type dirCache struct {
+	once  sync.Once
+	cache *fastcache.Cache[string, cacheEntry]
+	path  string // cache file path, or empty if the cache is not persisted
+	err   error
+}
+
+// getDirCache initializes and returns the persistent cache of the given
+// directory, shared by all instances using it. The cache holds at most
+// maxEntries entries if it is initialized by this call.
+func getDirCache(dir string, maxEntries int) (*dirCache, error) {
+	if abs, err := filepath.Abs(dir); err == nil {
+		dir = abs
+	}
+
+	v, _ := dirCaches.LoadOrStore(dir, &dirCache{})
+	c := v.(*dirCache)
+
+	c.once.Do(func() {
+		c.cache, c.path, c.err = openCache(dir, maxEntries)
+	})
+
+	if c.err != nil {
+		return nil, c.err
+	}
+
+	return c, nil
+}
+
+// forEachDirCache calls fn with the cache of every directory set with
+// [WithCacheDir] so far, skipping those that could not be initialized.
+func forEachDirCache(fn func(*dirCache)) {
+	dirCaches.Range(func(dir, _ any) bool {
+		if c, err := getDirCache(dir.(string), cacheMaxEntries); err == nil {
+			fn(c)
+		}
+
+		return true
+	})
+}
+
+// openCache creates the given cache directory if needed and loads the cache
+// file in it, holding at most maxEntries entries. The returned path of the
+// cache file is empty if the file exists but cannot be loaded, in which case
+// the cache is not persisted.
+func openCache(dir string, maxEntries int) (*fastcache.Cache[string, cacheEntry], string, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, "", fmt.Errorf("could not create cache directory: %w", err)
+	}
+
+	path := filepath.Join(dir, "cache.gob")
+
+	cache, err := loadCacheFromFile(path, maxEntries)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			path = ""
+		}
+
+		cache = fastcache.New[string, cacheEntry](maxEntries)
+	}
+
+	return cache, path, nil
+}
+
 func getCacheDir() (string, error) {
 	dir, err := os.UserCacheDir()
 	if err != nil {
@@ -83,6 +346,8 @@ func getCacheDir() (string, error) {
 
 func getCacheKey(importPath, version, sel string) string {
 	hash := fnv.New64a()
+	hash.Write([]byte(cacheFormatVersion))
+	hash.Write([]byte{0})
 	hash.Write([]byte(importPath))
 	hash.Write([]byte{0})
 	hash.Write([]byte(version))
@@ -92,6 +357,117 @@ func getCacheKey(importPath, version, sel string) string {
 	return fmt.Sprintf("%x", hash.Sum64())
 }
 
+// cacheKey returns the cache key for the given import path, version, and
+// selector, accounting for options that change the built documentation.
+func (d *Godoc) cacheKey(importPath, version, sel string) string {
+	if variant := d.cacheVariant(); variant != "" {
+		version += "\x00" + variant
+	}
+
+	return getCacheKey(importPath, version, sel)
+}
+
+// cacheVariant describes the options that change the built documentation,
+// so that docs built with different options are cached separately. It
+// returns an empty string for the default options.
+func (d *Godoc) cacheVariant() string {
+	var opts []string
+	if d.implementers {
+		opts = append(opts, "implementers")
+	}
+
+	if d.rawComments {
+		opts = append(opts, "raw-comments")
+	}
+
+	if d.unexported {
+		opts = append(opts, "unexported")
+	}
+
+	if d.prettyTypes {
+		opts = append(opts, "pretty-types")
+	}
+
+	if d.testFiles {
+		opts = append(opts, "test-files")
+	}
+
+	if d.sortMode == SortSource {
+		opts = append(opts, "sort=source")
+	}
+
+	if d.implements {
+		opts = append(opts, "implements")
+	}
+
+	if d.inlineTypes > 0 {
+		opts = append(opts, "inline-types="+strconv.Itoa(d.inlineTypes))
+	}
+
+	if level := d.buildOptions().pkgHeadingLevel(); level != defaultHeadingLevel {
+		opts = append(opts, "heading-level="+strconv.Itoa(level))
+	}
+
+	if d.synopsisOnly {
+		opts = append(opts, "synopsis-only")
+	}
+
+	if d.stripLicense {
+		opts = append(opts, "strip-license")
+	}
+
+	if d.resolveDocLinks {
+		opts = append(opts, "doc-links")
+	}
+
+	if d.goBinary != "" {
+		opts = append(opts, "go-binary="+d.goBinary)
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(d.goEnv)) {
+		if buildEnvVars[key] {
+			opts = append(opts, "env="+key+"="+d.goEnv[key])
+		}
+	}
+
+	if d.goos != "" {
+		opts = append(opts, "goos="+d.goos)
+	}
+
+	if d.goarch != "" {
+		opts = append(opts, "goarch="+d.goarch)
+	}
+
+	if len(d.platforms) > 0 {
+		opts = append(opts, "platforms="+strings.Join(d.platforms, "+"))
+	}
+
+	if d.filePattern != "" {
+		opts = append(opts, "files="+d.filePattern)
+	}
+
+	if len(d.buildTags) > 0 {
+		opts = append(opts, "tags="+strings.Join(d.buildTags, "+"))
+	}
+
+	if mod := d.modMode.flag(); mod != "" {
+		opts = append(opts, "mod="+mod)
+	}
+
+	return strings.Join(opts, ",")
+}
+
+// expired reports whether an entry with the given metadata is older than
+// the TTL set with [WithCacheTTL]. Entries of unknown age are expired if
+// there is a TTL.
+func (d *Godoc) expired(meta cacheMetadata) bool {
+	if d.cacheTTL <= 0 {
+		return false
+	}
+
+	return meta.BuiltAt.IsZero() || time.Since(meta.BuiltAt) > d.cacheTTL
+}
+
 func getValidCacheEntry(cache *fastcache.Cache[string, cacheEntry], key string) (cacheEntry, bool) {
 	if cache == nil || key == "" {
 		return cacheEntry{}, false
@@ -102,22 +478,21 @@ func getValidCacheEntry(cache *fastcache.Cache[string, cacheEntry], key string)
 }
 
 func setCacheEntry(cache *fastcache.Cache[string, cacheEntry], entry cacheEntry, keys ...string) error {
-	for _, key := range keys {
-		if key == "" {
-			continue
-		}
-
-		cache.Set(key, entry)
-	}
+	putCacheEntry(cache, entry, keys...)
 
 	if !cachePersistent || cacheFilePath == "" {
 		return nil
 	}
 
+	return saveCache(cache, cacheFilePath)
+}
+
+// saveCache persists cache to the file at path.
+func saveCache(cache *fastcache.Cache[string, cacheEntry], path string) error {
 	cacheMu.Lock()
 	defer cacheMu.Unlock()
 
-	if err := cache.SaveToFile(cacheFilePath); err != nil {
+	if err := cache.SaveToFile(path); err != nil {
 		if errors.Is(err, fs.ErrPermission) || os.IsPermission(err) || strings.Contains(strings.ToLower(err.Error()), "permission denied") {
 			return fmt.Errorf("cache persistence permission error: %w", fs.ErrPermission)
 		}
@@ -127,6 +502,73 @@ func setCacheEntry(cache *fastcache.Cache[string, cacheEntry], entry cacheEntry,
 	return nil
 }
 
+// putCacheEntry stores entry in cache under the given keys, without
+// persisting the cache.
+func putCacheEntry(cache *fastcache.Cache[string, cacheEntry], entry cacheEntry, keys ...string) {
+	for _, key := range keys {
+		if key == "" {
+			continue
+		}
+
+		cache.Set(key, entry)
+	}
+}
+
+// storeCacheEntry stores entry under the given keys like [setCacheEntry],
+// reporting the entries evicted to make room to the eviction callback. The
+// cache is only persisted in [CachePersistent] mode, and nothing is stored
+// if the cache is nil, as when caching is disabled.
+//
+// The persistent cache does not report evictions, so once it is about to
+// fill up, its keys are compared before and after storing the entry.
+func (d *Godoc) storeCacheEntry(cache *fastcache.Cache[string, cacheEntry], entry cacheEntry, keys ...string) error {
+	if cache == nil {
+		return nil
+	}
+
+	var before []string
+	if d.onEvict != nil && cacheFull(cache, len(keys)) {
+		before = slices.Collect(cache.Keys())
+	}
+
+	var err error
+	switch {
+	case d.cacheMode == CacheMemory:
+		putCacheEntry(cache, entry, keys...)
+	case d.cacheDir != "":
+		putCacheEntry(cache, entry, keys...)
+		if c, dirErr := getDirCache(d.cacheDir, d.maxCacheEntries()); dirErr == nil && c.path != "" {
+			err = saveCache(cache, c.path)
+		}
+	default:
+		err = setCacheEntry(cache, entry, keys...)
+	}
+
+	for _, key := range before {
+		if !cache.Has(key) {
+			d.evicted(key)
+		}
+	}
+
+	return err
+}
+
+// cacheFull reports whether storing n more entries in cache may evict some.
+func cacheFull(cache *fastcache.Cache[string, cacheEntry], n int) bool {
+	var stats fastcache.Stats
+	cache.UpdateStats(&stats)
+
+	return stats.EntriesCount+uint64(n) > stats.MaxEntries
+}
+
+// evicted reports the eviction of the cache entry stored under key to the
+// eviction callback, if any.
+func (d *Godoc) evicted(key string) {
+	if d.onEvict != nil {
+		d.onEvict(key)
+	}
+}
+
 func uniqKeys(keys ...string) []string {
 	seen := make(map[string]struct{}, len(keys))
 	out := make([]string, 0, len(keys))
@@ -148,7 +590,7 @@ func uniqKeys(keys ...string) []string {
 }
 
 func deriveCacheMetadata(module *packages.Module, resolvedVersion string) cacheMetadata {
-	meta := cacheMetadata{}
+	meta := cacheMetadata{BuiltAt: time.Now()}
 	resolved := strings.TrimSpace(resolvedVersion)
 
 	if module == nil || module.Path == "" {
@@ -175,6 +617,10 @@ func deriveCacheMetadata(module *packages.Module, resolvedVersion string) cacheM
 	return meta
 }
 
+// loadCacheFromFile loads the cache file at path into a cache holding at most
+// maxEntries entries. The file keeps the size of the cache it was saved
+// from, so a cache of another size is rebuilt from the loaded entries, some
+// of which are dropped if they do not fit.
 func loadCacheFromFile(path string, maxEntries int) (_ *fastcache.Cache[string, cacheEntry], err error) {
 	defer func() {
 		if r := recover(); r != nil {
@@ -188,8 +634,20 @@ func loadCacheFromFile(path string, maxEntries int) (_ *fastcache.Cache[string,
 	}
 
 	if cache == nil {
-		cache = fastcache.New[string, cacheEntry](maxEntries)
+		return fastcache.New[string, cacheEntry](maxEntries), nil
+	}
+
+	var stats fastcache.Stats
+	cache.UpdateStats(&stats)
+	if stats.MaxEntries == uint64(maxEntries) {
+		return cache, nil
+	}
+
+	resized := fastcache.New[string, cacheEntry](maxEntries)
+	for key, entry := range cache.All() {
+		resized.Set(key, entry)
 	}
+	cache.Reset()
 
-	return cache, nil
+	return resized, nil
 }
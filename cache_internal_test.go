@@ -2,12 +2,17 @@ package godoc
 
 import (
 	"errors"
+	"fmt"
+	"go/doc"
+	"go/token"
+	"go/types"
 	"io/fs"
 	"os"
 	"path/filepath"
 	"reflect"
 	"runtime"
 	"testing"
+	"time"
 
 	"go.dw1.io/fastcache"
 	"golang.org/x/tools/go/packages"
@@ -21,7 +26,7 @@ func TestGetCacheCreatesDirectory(t *testing.T) {
 	cacheHome := filepath.Join(root, "cachehome")
 	t.Setenv("XDG_CACHE_HOME", cacheHome)
 
-	cache, err := getCache()
+	cache, err := getCache(cacheMaxEntries)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -50,7 +55,7 @@ func TestGetCacheFailsWhenCacheDirIsFile(t *testing.T) {
 	}
 	t.Setenv("XDG_CACHE_HOME", cacheHome)
 
-	cache, err := getCache()
+	cache, err := getCache(cacheMaxEntries)
 	if err == nil {
 		t.Fatalf("expected error, got cache %v", cache)
 	}
@@ -78,7 +83,7 @@ func TestGetCacheClearsPersistenceWhenLoadFails(t *testing.T) {
 
 	t.Setenv("XDG_CACHE_HOME", cacheHome)
 
-	cache, err := getCache()
+	cache, err := getCache(cacheMaxEntries)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -201,3 +206,400 @@ func TestDeriveCacheMetadata(t *testing.T) {
 		t.Fatalf("expected resolved version override, got %q", meta.ModuleVersion)
 	}
 }
+
+func TestLRUCacheEviction(t *testing.T) {
+	t.Helper()
+
+	c := newLRUCache(2)
+	c.Set("a", cacheEntry{cacheMetadata: cacheMetadata{GoVersion: "a"}})
+	c.Set("b", cacheEntry{cacheMetadata: cacheMetadata{GoVersion: "b"}})
+
+	if _, ok := c.Get("a"); !ok {
+		t.Fatalf("expected entry a")
+	}
+
+	if evicted := c.Set("c", cacheEntry{cacheMetadata: cacheMetadata{GoVersion: "c"}}); len(evicted) != 1 || evicted[0] != "b" {
+		t.Fatalf("expected eviction of b to be reported, got %v", evicted)
+	}
+
+	if _, ok := c.Get("b"); ok {
+		t.Fatalf("expected least recently used entry b to be evicted")
+	}
+
+	if _, ok := c.Get("a"); !ok {
+		t.Fatalf("expected recently used entry a to survive eviction")
+	}
+
+	if c.Len() != 2 {
+		t.Fatalf("expected 2 entries, got %d", c.Len())
+	}
+
+	var nilCache *lruCache
+	nilCache.Set("a", cacheEntry{})
+	if _, ok := nilCache.Get("a"); ok || nilCache.Len() != 0 {
+		t.Fatalf("expected nil cache to stay empty")
+	}
+}
+
+func TestStoreCacheEntryReportsEvictions(t *testing.T) {
+	prevPersistent, prevPath := cachePersistent, cacheFilePath
+	cachePersistent, cacheFilePath = false, ""
+	t.Cleanup(func() {
+		cachePersistent, cacheFilePath = prevPersistent, prevPath
+	})
+
+	var evicted []string
+	g := New(WithEvictionCallback(func(key string) {
+		evicted = append(evicted, key)
+	}))
+
+	const total = 2000
+	cache := fastcache.New[string, cacheEntry](16)
+	for i := range total {
+		if err := g.storeCacheEntry(cache, cacheEntry{}, fmt.Sprintf("key-%d", i)); err != nil {
+			t.Fatalf("unexpected store error: %v", err)
+		}
+	}
+
+	if len(evicted) == 0 {
+		t.Fatalf("expected evictions to be reported")
+	}
+
+	if len(evicted) != total-cache.Len() {
+		t.Fatalf("expected %d evictions, got %d", total-cache.Len(), len(evicted))
+	}
+
+	for _, key := range evicted {
+		if cache.Has(key) {
+			t.Fatalf("reported evicted key %q is still cached", key)
+		}
+	}
+}
+
+func TestStdlibFastPathSkipsSharedCache(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	resetCacheGlobals()
+	t.Cleanup(resetCacheGlobals)
+
+	g := New()
+	if _, _, err := g.getOrLoadPkg("fmt", ""); err != nil {
+		t.Fatalf("unexpected load error: %v", err)
+	}
+
+	if stdlibCache.Len() != 1 {
+		t.Fatalf("expected stdlib package to be added to the fast path, got %d entries", stdlibCache.Len())
+	}
+
+	// Break the shared cache; the fast path must not touch it.
+	globalCache.Reset()
+	g.loadPkg = func(string, string, bool) (*doc.Package, *token.FileSet, *types.Info, *packageAST, string, *packages.Module, string, error) {
+		t.Fatalf("expected stdlib fast path to avoid loading")
+		return nil, nil, nil, nil, "", nil, "", nil
+	}
+
+	pkgDoc, _, err := g.getOrLoadPkg("fmt", "")
+	if err != nil {
+		t.Fatalf("unexpected fast path error: %v", err)
+	}
+
+	if pkgDoc.Name != "fmt" {
+		t.Fatalf("expected fmt package from fast path, got %q", pkgDoc.Name)
+	}
+
+	other := New(WithGOOS("windows"))
+	if _, ok := stdlibCache.Get(other.stdlibCacheKey("fmt", "")); ok {
+		t.Fatalf("expected fast path key to depend on target platform")
+	}
+}
+
+func BenchmarkGetOrLoadPkgStdlib(b *testing.B) {
+	b.Setenv("XDG_CACHE_HOME", b.TempDir())
+	resetCacheGlobals()
+	b.Cleanup(resetCacheGlobals)
+
+	g := New()
+	if _, _, err := g.getOrLoadPkg("net/http", ""); err != nil {
+		b.Fatal(err)
+	}
+
+	b.Run("fast-path", func(b *testing.B) {
+		for b.Loop() {
+			if _, _, err := g.getOrLoadPkg("net/http", ""); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("shared-cache", func(b *testing.B) {
+		saved := stdlibCache
+		stdlibCache = nil
+		defer func() { stdlibCache = saved }()
+
+		for b.Loop() {
+			if _, _, err := g.getOrLoadPkg("net/http", ""); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func TestInvalidateImportPathAndClearCache(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	resetCacheGlobals()
+	t.Cleanup(resetCacheGlobals)
+
+	cache, err := getCache(cacheMaxEntries)
+	if err != nil {
+		t.Fatalf("unexpected cache error: %v", err)
+	}
+
+	fmtEntry := cacheEntry{Package: &PackageDoc{ImportPath: "fmt"}}
+	stringsEntry := cacheEntry{Symbol: &SymbolDoc{ImportPath: "strings", Name: "Cut"}}
+	if err := setCacheEntry(cache, fmtEntry, "fmt", "fmt@go1"); err != nil {
+		t.Fatalf("unexpected store error: %v", err)
+	}
+	if err := setCacheEntry(cache, stringsEntry, "strings.Cut"); err != nil {
+		t.Fatalf("unexpected store error: %v", err)
+	}
+	stdlibCache.Set("fmt", fmtEntry)
+
+	if err := InvalidateImportPath("fmt"); err != nil {
+		t.Fatalf("unexpected invalidate error: %v", err)
+	}
+
+	if cache.Has("fmt") || cache.Has("fmt@go1") || stdlibCache.Len() != 0 {
+		t.Fatalf("expected fmt entries to be invalidated")
+	}
+
+	if !cache.Has("strings.Cut") {
+		t.Fatalf("expected unrelated entries to be kept")
+	}
+
+	if _, err := os.Stat(cacheFilePath); err != nil {
+		t.Fatalf("expected cache file to be kept: %v", err)
+	}
+
+	if err := ClearCache(); err != nil {
+		t.Fatalf("unexpected clear error: %v", err)
+	}
+
+	if cache.Len() != 0 {
+		t.Fatalf("expected empty cache, got %d entries", cache.Len())
+	}
+
+	if _, err := os.Stat(cacheFilePath); !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("expected cache file to be removed, got %v", err)
+	}
+
+	if err := ClearCache(); err != nil {
+		t.Fatalf("expected clearing an empty cache to succeed, got %v", err)
+	}
+}
+
+func TestCacheMode(t *testing.T) {
+	cacheHome := t.TempDir()
+	t.Setenv("XDG_CACHE_HOME", cacheHome)
+	resetCacheGlobals()
+	t.Cleanup(resetCacheGlobals)
+
+	cacheFile := filepath.Join(cacheHome, "godoc", "cache.gob")
+	key := getCacheKey("errors", getPkgVersion("errors", ""), "")
+
+	g := New(WithCacheMode(CacheDisabled))
+	if _, err := g.Load("errors", "", ""); err != nil {
+		t.Fatalf("load with cache disabled failed: %v", err)
+	}
+
+	if globalCache != nil || memoryCache != nil || stdlibCache.Len() != 0 {
+		t.Fatalf("expected nothing cached with cache disabled")
+	}
+
+	g = New(WithCacheMode(CacheMemory))
+	if _, err := g.Load("errors", "", ""); err != nil {
+		t.Fatalf("load with memory cache failed: %v", err)
+	}
+
+	if globalCache != nil {
+		t.Fatalf("expected persistent cache not to be initialized in memory mode")
+	}
+
+	if entry, ok := getValidCacheEntry(memoryCache, key); !ok || entry.Package == nil {
+		t.Fatalf("expected package cached in memory")
+	}
+
+	if _, err := os.Stat(cacheFile); !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("expected no cache file in memory mode, got %v", err)
+	}
+
+	if err := InvalidateImportPath("errors"); err != nil {
+		t.Fatalf("invalidate failed: %v", err)
+	}
+
+	if _, ok := getValidCacheEntry(memoryCache, key); ok {
+		t.Fatalf("expected memory cache entry invalidated")
+	}
+
+	if got := g.Config().CacheMode; got != CacheMemory {
+		t.Fatalf("expected CacheMemory in config, got %v", got)
+	}
+}
+
+func TestCacheDir(t *testing.T) {
+	cacheHome := t.TempDir()
+	t.Setenv("XDG_CACHE_HOME", cacheHome)
+	resetCacheGlobals()
+	t.Cleanup(resetCacheGlobals)
+
+	dir := filepath.Join(t.TempDir(), "godoc-cache")
+	g := New(WithCacheDir(dir))
+	if _, err := g.Load("errors", "", ""); err != nil {
+		t.Fatalf("load with cache dir failed: %v", err)
+	}
+
+	cacheFile := filepath.Join(dir, "cache.gob")
+	if _, err := os.Stat(cacheFile); err != nil {
+		t.Fatalf("expected cache file in cache dir: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(cacheHome, "godoc", "cache.gob")); !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("expected no cache file in default cache dir, got %v", err)
+	}
+
+	c, err := getDirCache(dir, cacheMaxEntries)
+	if err != nil {
+		t.Fatalf("unexpected cache dir error: %v", err)
+	}
+
+	key := getCacheKey("errors", getPkgVersion("errors", ""), "")
+	if entry, ok := getValidCacheEntry(c.cache, key); !ok || entry.Package == nil {
+		t.Fatalf("expected package cached in cache dir")
+	}
+
+	if got := g.Config().CacheDir; got != dir {
+		t.Fatalf("expected cache dir %q in config, got %q", dir, got)
+	}
+
+	if err := ClearCache(); err != nil {
+		t.Fatalf("clear cache failed: %v", err)
+	}
+
+	if _, err := os.Stat(cacheFile); !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("expected cache file removed, got %v", err)
+	}
+}
+
+func TestCacheTTL(t *testing.T) {
+	resetCacheGlobals()
+	t.Cleanup(resetCacheGlobals)
+
+	g := New(WithCacheMode(CacheMemory))
+	if _, err := g.Load("errors", "", ""); err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+
+	// Age the cached entries and mark them to tell them from rebuilt ones.
+	age := func(key string, entry cacheEntry, ok bool) {
+		if !ok {
+			t.Fatalf("expected errors to be cached under %q", key)
+		}
+
+		pkg := *entry.Package
+		pkg.Synopsis = "stale"
+		entry.Package = &pkg
+		entry.BuiltAt = time.Now().Add(-2 * time.Hour)
+
+		getMemoryCache(cacheMaxEntries).Set(key, entry)
+		stdlibCache.Set(g.stdlibCacheKey("errors", ""), entry)
+	}
+
+	key := g.cacheKey("errors", getPkgVersion("errors", ""), "")
+	entry, ok := getMemoryCache(cacheMaxEntries).Get(key)
+	age(key, entry, ok)
+
+	res, err := g.Load("errors", "", "")
+	if err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+
+	if syn := res.(PackageDoc).Synopsis; syn != "stale" {
+		t.Fatalf("expected cached docs without a TTL, got synopsis %q", syn)
+	}
+
+	g.SetOptions(WithCacheTTL(time.Hour))
+	if got := g.Config().CacheTTL; got != time.Hour {
+		t.Fatalf("expected cache TTL in config, got %v", got)
+	}
+
+	res, err = g.Load("errors", "", "")
+	if err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+
+	if syn := res.(PackageDoc).Synopsis; syn == "stale" {
+		t.Fatal("expected expired docs to be rebuilt")
+	}
+
+	if entry, _ := getMemoryCache(cacheMaxEntries).Get(key); time.Since(entry.BuiltAt) > time.Minute {
+		t.Fatalf("expected the rebuilt entry to be cached, built at %v", entry.BuiltAt)
+	}
+}
+
+func TestMaxCacheSize(t *testing.T) {
+	resetCacheGlobals()
+	t.Cleanup(resetCacheGlobals)
+
+	maxEntries := func(g Godoc) uint64 {
+		t.Helper()
+
+		cache, err := g.cache()
+		if err != nil {
+			t.Fatalf("cache failed: %v", err)
+		}
+
+		var stats fastcache.Stats
+		cache.UpdateStats(&stats)
+
+		return stats.MaxEntries
+	}
+
+	g := New(WithCacheMode(CacheMemory), WithMaxCacheSize(64))
+	if got := g.Config().MaxCacheSize; got != 64 {
+		t.Fatalf("expected max cache size in config, got %d", got)
+	}
+
+	if got := maxEntries(g); got != 64 {
+		t.Fatalf("expected memory cache of 64 entries, got %d", got)
+	}
+
+	// The memory cache is shared and already sized.
+	if got := maxEntries(New(WithCacheMode(CacheMemory), WithMaxCacheSize(128))); got != 64 {
+		t.Fatalf("expected the existing memory cache to keep its size, got %d", got)
+	}
+
+	g = New(WithCacheDir(t.TempDir()), WithMaxCacheSize(-1))
+	if got := g.Config().MaxCacheSize; got != cacheMaxEntries {
+		t.Fatalf("expected default max cache size in config, got %d", got)
+	}
+
+	if got := maxEntries(g); got != cacheMaxEntries {
+		t.Fatalf("expected dir cache of %d entries, got %d", cacheMaxEntries, got)
+	}
+
+	// A cache file saved with another size is resized when loaded.
+	dir := t.TempDir()
+	saved := fastcache.New[string, cacheEntry](16)
+	saved.Set("key", cacheEntry{Package: &PackageDoc{Name: "foo"}})
+	if err := saved.SaveToFile(filepath.Join(dir, "cache.gob")); err != nil {
+		t.Fatalf("save failed: %v", err)
+	}
+
+	g = New(WithCacheDir(dir), WithMaxCacheSize(32))
+	if got := maxEntries(g); got != 32 {
+		t.Fatalf("expected the loaded cache to be resized to 32 entries, got %d", got)
+	}
+
+	cache, _ := g.cache()
+	if entry, ok := cache.Get("key"); !ok || entry.Package == nil || entry.Package.Name != "foo" {
+		t.Fatalf("expected the loaded entry to be kept, got %+v", entry)
+	}
+}
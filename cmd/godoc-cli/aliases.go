@@ -0,0 +1,142 @@
+package main
+
+import (
+	"fmt"
+	"go/build"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// aliasesEnv names the environment variable holding additional package
+// aliases, in the same name=path[,name=path...] form as -alias.
+const aliasesEnv = "GODOC_CLI_ALIASES"
+
+// defaultPackageAliases resolves the short names shared by several standard
+// library packages.
+var defaultPackageAliases = map[string]string{
+	"pprof":    "runtime/pprof",
+	"rand":     "math/rand",
+	"scanner":  "text/scanner",
+	"template": "text/template",
+}
+
+// packageAliases maps short package names to import paths.
+type packageAliases map[string]string
+
+// String implements [flag.Value].
+func (a packageAliases) String() string {
+	pairs := make([]string, 0, len(a))
+	for name, importPath := range a {
+		pairs = append(pairs, name+"="+importPath)
+	}
+
+	return strings.Join(pairs, ",")
+}
+
+// Set implements [flag.Value] by adding comma-separated name=path pairs.
+func (a packageAliases) Set(s string) error {
+	for _, pair := range strings.Split(s, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		name, importPath, ok := strings.Cut(pair, "=")
+		name, importPath = strings.TrimSpace(name), strings.TrimSpace(importPath)
+		if !ok || name == "" || importPath == "" {
+			return fmt.Errorf("invalid alias %q; want name=importpath", pair)
+		}
+
+		a[name] = importPath
+	}
+
+	return nil
+}
+
+// newPackageAliases returns the default aliases extended by those from the
+// environment.
+func newPackageAliases() (packageAliases, error) {
+	aliases := make(packageAliases, len(defaultPackageAliases))
+	for name, importPath := range defaultPackageAliases {
+		aliases[name] = importPath
+	}
+
+	if env := os.Getenv(aliasesEnv); env != "" {
+		if err := aliases.Set(env); err != nil {
+			return nil, fmt.Errorf("%s: %w", aliasesEnv, err)
+		}
+	}
+
+	return aliases, nil
+}
+
+// resolvePackageAlias maps a short package name such as "url" or "json" to
+// its import path. Explicit aliases take precedence; otherwise the standard
+// library package with that last path element is used if there is exactly
+// one. Import paths, relative paths, and top-level standard library packages
+// are returned unchanged.
+func resolvePackageAlias(importPath string, aliases packageAliases) string {
+	if importPath == "" || strings.ContainsAny(importPath, "./\\") {
+		return importPath
+	}
+
+	if resolved, ok := aliases[importPath]; ok {
+		return resolved
+	}
+
+	if isStdPackage(importPath) {
+		return importPath
+	}
+
+	var match string
+	for _, p := range stdPackages() {
+		if path.Base(p) != importPath {
+			continue
+		}
+
+		if match != "" {
+			return importPath
+		}
+
+		match = p
+	}
+
+	if match == "" {
+		return importPath
+	}
+
+	return match
+}
+
+// isStdPackage reports whether importPath is a standard library package.
+func isStdPackage(importPath string) bool {
+	if build.Default.GOROOT == "" {
+		return false
+	}
+
+	fi, err := os.Stat(filepath.Join(build.Default.GOROOT, "src", filepath.FromSlash(importPath)))
+
+	return err == nil && fi.IsDir()
+}
+
+// stdPackages lists the importable standard library packages.
+func stdPackages() []string {
+	out, err := exec.Command("go", "list", "std").Output()
+	if err != nil {
+		return nil
+	}
+
+	var pkgs []string
+	for _, p := range strings.Fields(string(out)) {
+		if strings.Contains(p, "internal") || strings.HasPrefix(p, "vendor/") {
+			continue
+		}
+
+		pkgs = append(pkgs, p)
+	}
+
+	return pkgs
+}
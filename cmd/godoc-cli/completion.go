@@ -0,0 +1,249 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strings"
+	"text/template"
+
+	"go.dw1.io/godoc"
+)
+
+// completionShells lists the shells supported by -completion.
+var completionShells = []string{"bash", "zsh", "fish"}
+
+// completionFlag describes a command-line flag for completion scripts.
+type completionFlag struct {
+	Name   string
+	Usage  string
+	Value  bool     // Whether the flag takes a value
+	Values []string // Known values, if any
+	Dirs   bool     // Whether the value is a directory
+}
+
+// completionData is the input of the completion script templates.
+type completionData struct {
+	Flags []completionFlag
+}
+
+// ValueFlags returns the names of the flags that take a value.
+func (d completionData) ValueFlags() []string {
+	var names []string
+	for _, f := range d.Flags {
+		if f.Value {
+			names = append(names, "-"+f.Name)
+		}
+	}
+
+	return names
+}
+
+// completionFlags returns the registered command-line flags.
+func completionFlags() []completionFlag {
+	values := map[string][]string{
+		"completion": completionShells,
+		"format":     outputFormats,
+		"kinds":      symbolKinds,
+		"match":      {"exact", "case", "prefix"},
+		"style":      {"dark", "light", "notty", "auto"},
+	}
+
+	var flags []completionFlag
+	flag.VisitAll(func(f *flag.Flag) {
+		isBool := false
+		if bf, ok := f.Value.(interface{ IsBoolFlag() bool }); ok {
+			isBool = bf.IsBoolFlag()
+		}
+
+		flags = append(flags, completionFlag{
+			Name:   f.Name,
+			Usage:  f.Usage,
+			Value:  !isBool,
+			Values: values[f.Name],
+			Dirs:   f.Name == "workdir",
+		})
+	})
+
+	return flags
+}
+
+// outputCompletion prints the completion script for the given shell.
+func outputCompletion(w io.Writer, shell string) error {
+	tmpl, ok := completionTemplates[shell]
+	if !ok {
+		return fmt.Errorf("unsupported shell %q for -completion; supported shells are: %s", shell, strings.Join(completionShells, ", "))
+	}
+
+	t, err := template.New(shell).Funcs(template.FuncMap{"join": strings.Join}).Parse(tmpl)
+	if err != nil {
+		return err
+	}
+
+	return t.Execute(w, completionData{Flags: completionFlags()})
+}
+
+// outputList prints the names of the documented symbols, one per line.
+// Methods are listed as <type>.<method>.
+func outputList(w io.Writer, result godoc.Result, kinds map[string]bool) error {
+	var names []string
+
+	withMethods := kinds == nil || kinds["method"]
+	addType := func(t godoc.TypeDoc) {
+		names = append(names, t.Name)
+		if !withMethods {
+			return
+		}
+
+		for _, m := range t.Methods {
+			names = append(names, t.Name+"."+m.Name)
+		}
+	}
+
+	switch v := result.(type) {
+	case godoc.PackageDoc:
+		for _, values := range [][]godoc.ValueDoc{v.Consts, v.Vars} {
+			for _, value := range values {
+				names = append(names, value.Names...)
+			}
+		}
+
+		for _, f := range v.Funcs {
+			names = append(names, f.Name)
+		}
+
+		for _, t := range v.Types {
+			addType(t)
+		}
+	case godoc.SymbolDoc:
+		if v.TypeDoc == nil {
+			return fmt.Errorf("%s %s has no members to list", v.Kind, v.Name)
+		}
+
+		addType(*v.TypeDoc)
+	default:
+		return fmt.Errorf("listing is not supported for %T", result)
+	}
+
+	sort.Strings(names)
+	names = slices.Compact(names)
+
+	for _, name := range names {
+		if _, err := fmt.Fprintln(w, name); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Shell commands listing the importable packages.
+const (
+	completionPackages     = `{ go list -e std; go list -e all; } 2>/dev/null | sort -u`
+	completionPackagesFish = `begin; go list -e std; go list -e all; end 2>/dev/null | sort -u`
+)
+
+var completionTemplates = map[string]string{
+	"bash": `# bash completion for godoc-cli
+# Load with: source <(godoc-cli -completion bash)
+
+_godoc_cli() {
+    local cur prev
+    cur="${COMP_WORDS[COMP_CWORD]}"
+    prev="${COMP_WORDS[COMP_CWORD-1]}"
+
+    case "$prev" in
+{{- range .Flags}}{{if .Value}}
+        -{{.Name}})
+            {{if .Dirs}}COMPREPLY=($(compgen -d -- "$cur")){{else if .Values}}COMPREPLY=($(compgen -W "{{join .Values " "}}" -- "$cur")){{else}}COMPREPLY=(){{end}}
+            return
+            ;;
+{{- end}}{{end}}
+    esac
+
+    if [[ "$cur" == -* ]]; then
+        COMPREPLY=($(compgen -W "{{range $i, $f := .Flags}}{{if $i}} {{end}}-{{$f.Name}}{{end}}" -- "$cur"))
+        return
+    fi
+
+    local i pkg="" npos=0
+    for ((i = 1; i < COMP_CWORD; i++)); do
+        case "${COMP_WORDS[i]}" in
+            {{join .ValueFlags "|"}}) ((i++)) ;;
+            -*) ;;
+            *) pkg="${COMP_WORDS[i]}"; ((npos++)) ;;
+        esac
+    done
+
+    if ((npos == 0)); then
+        COMPREPLY=($(compgen -W "$(` + completionPackages + `)" -- "$cur"))
+    elif ((npos == 1)); then
+        COMPREPLY=($(compgen -W "$(godoc-cli -list "$pkg" 2>/dev/null)" -- "$cur"))
+    fi
+}
+
+complete -F _godoc_cli godoc-cli
+`,
+	"zsh": `#compdef godoc-cli
+# zsh completion for godoc-cli
+# Load with: source <(godoc-cli -completion zsh)
+
+_godoc_cli() {
+    local state line
+    typeset -A opt_args
+
+    _arguments -s \
+{{- range .Flags}}
+        '-{{.Name}}[{{.Usage}}]{{if .Value}}:{{.Name}}:{{if .Dirs}}_files -/{{else if .Values}}({{join .Values " "}}){{end}}{{end}}' \
+{{- end}}
+        '1:package:->packages' \
+        '2:symbol:->symbols'
+
+    case $state in
+        packages)
+            compadd -- ${(f)"$(` + completionPackages + `)"}
+            ;;
+        symbols)
+            compadd -- ${(f)"$(godoc-cli -list ${line[1]} 2>/dev/null)"}
+            ;;
+    esac
+}
+
+if [ "$funcstack[1]" = "_godoc_cli" ]; then
+    _godoc_cli "$@"
+else
+    compdef _godoc_cli godoc-cli
+fi
+`,
+	"fish": `# fish completion for godoc-cli
+# Load with: godoc-cli -completion fish | source
+
+function __godoc_cli_args
+    set -l tokens (commandline -opc)
+    set -e tokens[1]
+    set -l skip 0
+    for token in $tokens
+        if test $skip -eq 1
+            set skip 0
+            continue
+        end
+        switch $token
+            case {{join .ValueFlags " "}}
+                set skip 1
+            case '-*'
+            case '*'
+                echo $token
+        end
+    end
+end
+
+complete -c godoc-cli -f
+{{- range .Flags}}
+complete -c godoc-cli -o {{.Name}} -d '{{.Usage}}'{{if .Value}} -r{{if .Dirs}} -a '(__fish_complete_directories)'{{else if .Values}} -x -a '{{join .Values " "}}'{{end}}{{end}}
+{{- end}}
+complete -c godoc-cli -n 'test (count (__godoc_cli_args)) -eq 0' -a '(` + completionPackagesFish + `)'
+complete -c godoc-cli -n 'test (count (__godoc_cli_args)) -eq 1' -a '(godoc-cli -list (__godoc_cli_args) 2>/dev/null)'
+`,
+}
@@ -1,14 +1,19 @@
 package main
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"flag"
 	"fmt"
 	"go/token"
+	"io"
 	"os"
-	"regexp"
+	"path/filepath"
+	"slices"
 	"strings"
+	"text/tabwriter"
+	"time"
 
 	"github.com/charmbracelet/glamour"
 	"go.dw1.io/godoc"
@@ -26,15 +31,56 @@ Usage:
    godoc-cli [options] [<pkg>.]<sym>[.<methodOrField>]
    godoc-cli [options] [<pkg>.][<sym>.]<methodOrField>
    godoc-cli [options] <pkg> <sym>[.<methodOrField>]
+   godoc-cli -versions <module>
+   godoc-cli -search <query> [<pkg>]
+   godoc-cli -completion <shell>
+   godoc-cli -V
 
 Options:
    -goos string     Target operating system (e.g., linux, darwin, windows)
    -goarch string   Target architecture (e.g., amd64, arm64)
    -workdir string  Working directory for package resolution (default: current directory)
    -version string  Module version (e.g., v1.2.3, latest)
-   -style string    Glamour style (dark, light, notty, auto) (default: auto)
+   -timeout duration
+                    Maximum time to load the documentation (e.g., 30s);
+                    0 means no timeout (default: 0)
+   -kinds string    Comma-separated symbol kinds to show (const, var, func, type, method)
+   -match string    How symbols are matched when no name matches exactly
+                    (exact, case, prefix) (default: exact)
+   -style string    Glamour style (dark, light, notty, auto) (default: auto,
+                    or notty with -o)
    -pager           View output in an interactive pager
-   -json            Output raw JSON instead of rendered markdown
+   -no-pager        Print output directly, even if it does not fit the
+                    terminal (by default, long output on a terminal is
+                    viewed in the pager)
+   -format string   Output format (default: markdown):
+                      markdown  rendered markdown
+                      text      plain text with basic ANSI colors
+                                (uncolored if $NO_COLOR is set or stdout
+                                is not a terminal)
+                      json      raw JSON
+                      html      HTML (a complete document for packages)
+                      man       man page (troff)
+   -o string        Write the output to a file instead of stdout, creating
+                    its directory if needed (the pager is not used); the
+                    file is only replaced once the output is complete
+   -json            Same as -format json (deprecated)
+   -man             Same as -format man
+   -text            Same as -format text
+   -json-schema     Print the JSON Schema of the JSON output and exit
+   -list            List symbol names, one per line (methods as <type>.<method>)
+   -search string   List the symbols of a package whose name or documentation
+                    contains the query, ignoring case, with a snippet; a
+                    query enclosed in slashes (/re/) is a regular expression
+                    (as a JSON array with -format json)
+   -versions        List the available versions of a module, one per line
+                    (as a JSON array with -format json)
+   -alias string    Comma-separated short package name aliases (name=importpath),
+                    added to those in $GODOC_CLI_ALIASES; may be repeated.
+                    Standard library short names (e.g., url) resolve by default
+   -completion string
+                    Print a shell completion script (bash, zsh, fish) and exit
+   -V               Print the version of godoc-cli and exit
    -help            Show this help message
 
 Examples:
@@ -47,16 +93,63 @@ Examples:
    # View a specific function
    godoc-cli fmt.Println
 
+   # Use the short name of a standard library package
+   godoc-cli url.URL
+
+   # Alias a short name to an import path
+   godoc-cli -alias yaml=gopkg.in/yaml.v3 yaml.Marshal
+
    # View documentation for a specific version
    godoc-cli -version v1.2.3 github.com/user/repo
 
+   # Match symbol names case-insensitively
+   godoc-cli -match case fmt.println
+
+   # Show only functions and types
+   godoc-cli -kinds func,type net/http
+
    # Output raw JSON
-   godoc-cli -json fmt
+   godoc-cli -format json fmt
+
+   # Write a self-contained HTML page
+   godoc-cli -format html -o docs/http.html net/http
+
+   # Read documentation with man(1)
+   godoc-cli -format man fmt | man -l -
+
+   # Output colored plain text without rendering markdown
+   godoc-cli -format text fmt
+
+   # Print the JSON Schema of the JSON output
+   godoc-cli -json-schema
+
+   # List the symbols of a package
+   godoc-cli -list net/http
+
+   # Search the symbols of a package by name and documentation
+   godoc-cli -search Marshal encoding/json
+
+   # List the available versions of a module
+   godoc-cli -versions github.com/user/repo
+
+   # Enable shell completion (bash)
+   source <(godoc-cli -completion bash)
 `
 )
 
 var (
-	docLinksRe           = regexp.MustCompile(`\\?\[([A-Z][A-Za-z0-9_.]*)\\?\]`)
+	symbolKinds = []string{"const", "var", "func", "type", "method"}
+
+	// outputFormats are the values of -format.
+	outputFormats = []string{"markdown", "text", "json", "html", "man"}
+
+	// symbolMatches maps the values of -match to symbol match modes.
+	symbolMatches = map[string]godoc.SymbolMatch{
+		"exact":  godoc.MatchExact,
+		"case":   godoc.MatchCaseInsensitive,
+		"prefix": godoc.MatchPrefix,
+	}
+
 	defaultWordWrapWidth = 80
 )
 
@@ -65,27 +158,150 @@ type config struct {
 	goarch     string
 	workdir    string
 	version    string
+	kinds      string
+	kindSet    map[string]bool
+	match      string
+	timeout    time.Duration
 	style      string
+	format     string
+	output     string
 	jsonOutput bool
+	manOutput  bool
+	textOutput bool
+	jsonSchema bool
+	list       bool
+	search     string
+	versions   bool
+	completion string
+	printVer   bool
 	pager      bool
+	noPager    bool
+	aliases    packageAliases
 }
 
 func main() {
-	cfg := config{}
+	aliases, err := newPackageAliases()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	cfg := config{aliases: aliases}
 
 	flag.StringVar(&cfg.goos, "goos", "", "target operating system")
 	flag.StringVar(&cfg.goarch, "goarch", "", "target architecture")
 	flag.StringVar(&cfg.workdir, "workdir", "", "working directory for package resolution")
 	flag.StringVar(&cfg.version, "version", "", "module version")
+	flag.StringVar(&cfg.kinds, "kinds", "", "comma-separated symbol kinds to show")
+	flag.StringVar(&cfg.match, "match", "exact", "how symbols are matched (exact, case, prefix)")
+	flag.DurationVar(&cfg.timeout, "timeout", 0, "maximum time to load the documentation")
 	flag.StringVar(&cfg.style, "style", "auto", "glamour style (dark, light, notty, auto)")
 	flag.BoolVar(&cfg.pager, "pager", false, "view output in an interactive pager")
-	flag.BoolVar(&cfg.jsonOutput, "json", false, "output raw JSON")
+	flag.BoolVar(&cfg.noPager, "no-pager", false, "print output directly, without the pager")
+	flag.StringVar(&cfg.format, "format", "", "output format (markdown, text, json, html, man)")
+	flag.StringVar(&cfg.output, "o", "", "write the output to a file")
+	flag.BoolVar(&cfg.jsonOutput, "json", false, "same as -format json (deprecated)")
+	flag.BoolVar(&cfg.manOutput, "man", false, "same as -format man")
+	flag.BoolVar(&cfg.textOutput, "text", false, "same as -format text")
+	flag.BoolVar(&cfg.jsonSchema, "json-schema", false, "print the JSON Schema of the JSON output")
+	flag.BoolVar(&cfg.list, "list", false, "list symbol names")
+	flag.StringVar(&cfg.search, "search", "", "list the symbols whose name or documentation matches the query")
+	flag.BoolVar(&cfg.versions, "versions", false, "list the available versions of a module")
+	flag.StringVar(&cfg.completion, "completion", "", "print a shell completion script (bash, zsh, fish)")
+	flag.BoolVar(&cfg.printVer, "V", false, "print the version of godoc-cli and exit")
+	flag.Var(cfg.aliases, "alias", "short package name aliases (name=importpath, comma-separated)")
 	flag.Usage = func() {
 		fmt.Fprint(os.Stderr, usage)
 	}
 
 	flag.Parse()
 
+	if cfg.pager && cfg.noPager {
+		fmt.Fprintln(os.Stderr, "-pager and -no-pager are mutually exclusive")
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	if err := resolveFormat(&cfg); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	// With -o, the output is buffered and only written to the file once it
+	// is complete, so that a failure leaves an existing file untouched.
+	var (
+		buf bytes.Buffer
+		w   io.Writer = os.Stdout
+	)
+
+	if cfg.output != "" {
+		w = &buf
+
+		styleSet := false
+		flag.Visit(func(f *flag.Flag) {
+			styleSet = styleSet || f.Name == "style"
+		})
+
+		if !styleSet {
+			cfg.style = "notty"
+		}
+	}
+
+	finish := func() {
+		if cfg.output == "" {
+			return
+		}
+
+		if err := writeOutput(cfg.output, buf.Bytes()); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	if cfg.printVer {
+		fmt.Fprintln(w, "godoc-cli", godoc.Version())
+		finish()
+
+		return
+	}
+
+	if cfg.completion != "" {
+		if err := outputCompletion(w, cfg.completion); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		finish()
+
+		return
+	}
+
+	if cfg.jsonSchema {
+		if err := outputJSONSchema(w); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		finish()
+
+		return
+	}
+
+	if cfg.versions {
+		if flag.NArg() != 1 {
+			fmt.Fprintln(os.Stderr, "-versions requires exactly one module path")
+			flag.Usage()
+			os.Exit(1)
+		}
+
+		if err := outputVersions(w, cfg, flag.Arg(0)); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		finish()
+
+		return
+	}
+
 	importPath, sel, err := parseCLIArgs(flag.Args())
 	if err != nil {
 		fmt.Fprintln(os.Stderr, err)
@@ -93,15 +309,46 @@ func main() {
 		os.Exit(1)
 	}
 
-	if err := run(cfg, importPath, sel); err != nil {
+	importPath = resolvePackageAlias(importPath, cfg.aliases)
+
+	cfg.kindSet, err = parseKinds(cfg.kinds)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	if _, ok := symbolMatches[cfg.match]; !ok {
+		fmt.Fprintf(os.Stderr, "unknown mode %q in -match; valid modes are: exact, case, prefix\n", cfg.match)
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	if cfg.search != "" {
+		if sel != "" {
+			fmt.Fprintln(os.Stderr, "-search takes a package, not a symbol")
+			flag.Usage()
+			os.Exit(1)
+		}
+
+		if err := outputSearch(w, cfg, importPath); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		finish()
+
+		return
+	}
+
+	if err := run(w, cfg, importPath, sel); err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
+	finish()
 }
 
-func run(cfg config, importPath, sel string) error {
-	ctx := context.Background()
-
+// newGodoc returns a [godoc.Godoc] configured by the command-line flags.
+func newGodoc(cfg config) godoc.Godoc {
 	var opts []godoc.Option
 	if cfg.goos != "" {
 		opts = append(opts, godoc.WithGOOS(cfg.goos))
@@ -112,17 +359,51 @@ func run(cfg config, importPath, sel string) error {
 	if cfg.workdir != "" {
 		opts = append(opts, godoc.WithWorkdir(cfg.workdir))
 	}
-	opts = append(opts, godoc.WithContext(ctx))
+	if mode := symbolMatches[cfg.match]; mode != godoc.MatchExact {
+		opts = append(opts, godoc.WithSymbolMatch(mode))
+	}
+	if cfg.timeout > 0 {
+		opts = append(opts, godoc.WithTimeout(cfg.timeout))
+	}
+	opts = append(opts, godoc.WithContext(context.Background()))
+
+	return godoc.New(opts...)
+}
 
-	g := godoc.New(opts...)
+func run(w io.Writer, cfg config, importPath, sel string) error {
+	g := newGodoc(cfg)
 
 	result, err := g.Load(importPath, sel, cfg.version)
 	if err != nil {
 		return fmt.Errorf("failed to load documentation: %w", err)
 	}
 
-	if cfg.jsonOutput {
-		return outputJSON(result)
+	result, err = filterKinds(result, cfg.kindSet)
+	if err != nil {
+		return err
+	}
+
+	if cfg.list {
+		return outputList(w, result, cfg.kindSet)
+	}
+
+	switch cfg.format {
+	case "json":
+		return outputJSON(w, result)
+	case "man":
+		return outputManPage(w, result)
+	case "html":
+		return outputHTML(w, result)
+	case "text":
+		// Colors only make sense on the terminal.
+		text := godoc.ANSIText(result)
+		if cfg.output != "" {
+			text = godoc.PlainText(result)
+		}
+
+		_, err := fmt.Fprint(w, text)
+
+		return err
 	}
 
 	rendered, raw, actualImportPath, err := renderMarkdown(result, cfg)
@@ -132,13 +413,9 @@ func run(cfg config, importPath, sel string) error {
 
 	label := buildPagerLabel(result, actualImportPath, sel)
 	doc := pager.Document{Content: rendered, Raw: raw, Label: label}
+	doc.Import, doc.Segments = pagerSegments(result)
 
-	if cfg.pager {
-		if !term.IsTerminal(int(os.Stdout.Fd())) {
-			fmt.Print(doc.Content)
-			return nil
-		}
-
+	if usePager(cfg, doc.Content) {
 		if err := pager.Run(doc); err != nil {
 			return fmt.Errorf("failed to launch pager: %w", err)
 		}
@@ -146,276 +423,296 @@ func run(cfg config, importPath, sel string) error {
 		return nil
 	}
 
-	fmt.Print(doc.Content)
+	_, err = fmt.Fprint(w, doc.Content)
 
-	return nil
+	return err
 }
 
-func outputJSON(result godoc.Result) error {
-	data, err := json.MarshalIndent(result, "", "  ")
-	if err != nil {
-		return fmt.Errorf("failed to marshal JSON: %w", err)
+// resolveFormat sets the output format from -format and its aliases, -json,
+// -man, and -text, defaulting to markdown.
+func resolveFormat(cfg *config) error {
+	aliases := []struct {
+		set    bool
+		name   string
+		format string
+	}{
+		{cfg.jsonOutput, "-json", "json"},
+		{cfg.manOutput, "-man", "man"},
+		{cfg.textOutput, "-text", "text"},
+	}
+
+	from := "-format " + cfg.format
+	for _, alias := range aliases {
+		if !alias.set {
+			continue
+		}
+
+		if cfg.format != "" && cfg.format != alias.format {
+			return fmt.Errorf("%s conflicts with %s", alias.name, from)
+		}
+
+		cfg.format, from = alias.format, alias.name
+	}
+
+	if cfg.format == "" {
+		cfg.format = "markdown"
 	}
 
-	fmt.Println(string(data))
+	if !slices.Contains(outputFormats, cfg.format) {
+		return fmt.Errorf("unknown format %q in -format; valid formats are: %s", cfg.format, strings.Join(outputFormats, ", "))
+	}
 
 	return nil
 }
 
-func buildPkgMarkdown(pkgDoc godoc.PackageDoc) string {
-	var sb strings.Builder
+// writeOutput writes data to the file named by -o, creating its directory.
+// The data is written to a temporary file in the same directory, which then
+// replaces the file, so that the file is never left partially written.
+func writeOutput(name string, data []byte) error {
+	dir := filepath.Dir(name)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
 
-	// Package header
-	sb.WriteString(fmt.Sprintf("# package %s\n\n", pkgDoc.Name))
-	sb.WriteString(fmt.Sprintf("```\nimport %q\n```\n\n", pkgDoc.ImportPath))
+	f, err := os.CreateTemp(dir, "."+filepath.Base(name)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create output file: %w", err)
+	}
+
+	tmp := f.Name()
+	defer os.Remove(tmp) // no-op once renamed
 
-	// Package documentation
-	if pkgDoc.DocText != "" {
-		sb.WriteString(pkgDoc.DocText)
-		sb.WriteString("\n\n")
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+
+		return fmt.Errorf("failed to write output file: %w", err)
 	}
 
-	// Constants
-	if len(pkgDoc.Consts) > 0 {
-		sb.WriteString("# CONSTANTS\n\n")
-		for _, c := range pkgDoc.Consts {
-			for _, name := range c.Names {
-				sb.WriteString(fmt.Sprintf("## %s\n\n", name))
-			}
-			if c.Doc != "" {
-				sb.WriteString(c.Doc)
-				sb.WriteString("\n\n")
-			}
-		}
+	if err := f.Chmod(0o644); err != nil {
+		f.Close()
+
+		return fmt.Errorf("failed to write output file: %w", err)
 	}
 
-	// Variables
-	if len(pkgDoc.Vars) > 0 {
-		sb.WriteString("# VARIABLES\n\n")
-		for _, v := range pkgDoc.Vars {
-			for _, name := range v.Names {
-				sb.WriteString(fmt.Sprintf("## %s\n\n", name))
-			}
-			if v.Doc != "" {
-				sb.WriteString(v.Doc)
-				sb.WriteString("\n\n")
-			}
-		}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write output file: %w", err)
 	}
 
-	// Functions
-	if len(pkgDoc.Funcs) > 0 {
-		sb.WriteString("# FUNCTIONS\n\n")
-		for _, f := range pkgDoc.Funcs {
-			sig := formatFuncSignature(f)
-			if sig != "" {
-				sb.WriteString("```go\n")
-				sb.WriteString(sig)
-				sb.WriteString("\n```\n\n")
-			}
-			if f.Doc != "" {
-				sb.WriteString(f.Doc)
-				sb.WriteString("\n\n")
-			}
-		}
+	if err := os.Rename(tmp, name); err != nil {
+		return fmt.Errorf("failed to write output file: %w", err)
 	}
 
-	// Types
-	if len(pkgDoc.Types) > 0 {
-		sb.WriteString("# TYPES\n\n")
-		for _, t := range pkgDoc.Types {
-			if decl := t.Decl; decl != "" {
-				sb.WriteString("```go\n")
-				sb.WriteString(decl)
-				sb.WriteString("\n```\n\n")
-			}
+	return nil
+}
 
-			if t.Doc != "" {
-				sb.WriteString(t.Doc)
-				sb.WriteString("\n\n")
-			}
+// usePager reports whether the rendered content is viewed in the pager:
+// always with -pager, never with -no-pager, and otherwise if it is taller
+// than the terminal. The pager is only used if stdout is a terminal, and,
+// unless forced with -pager, if stdin is one too.
+func usePager(cfg config, content string) bool {
+	fd := int(os.Stdout.Fd())
+	if cfg.noPager || cfg.output != "" || !term.IsTerminal(fd) {
+		return false
+	}
 
-			// Methods (skip for interfaces; included in decl)
-			if !strings.EqualFold(t.Kind, "interface") && len(t.Methods) > 0 {
-				for _, m := range t.Methods {
-					sig := formatMethodSignature(m)
-					if sig != "" {
-						sb.WriteString("```go\n")
-						sb.WriteString(sig)
-						sb.WriteString("\n```\n\n")
-					}
-					if m.Doc != "" {
-						sb.WriteString(m.Doc)
-						sb.WriteString("\n\n")
-					}
-				}
-			}
-		}
+	if cfg.pager {
+		return true
 	}
 
-	return sb.String()
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return false
+	}
+
+	_, height, err := term.GetSize(fd)
+	if err != nil || height <= 0 {
+		return false
+	}
+
+	return strings.Count(content, "\n") >= height
 }
 
-func formatParamList(args []godoc.ArgInfo) string {
-	if len(args) == 0 {
-		return ""
+// parseKinds parses a comma-separated list of symbol kinds. An empty list
+// yields a nil set, meaning every kind is shown.
+func parseKinds(s string) (map[string]bool, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, nil
 	}
 
-	parts := make([]string, 0, len(args))
-	for _, arg := range args {
-		name := arg.Name
-		typ := arg.Type
-		switch {
-		case name != "" && typ != "":
-			parts = append(parts, fmt.Sprintf("%s %s", name, typ))
-		case typ != "":
-			parts = append(parts, typ)
-		case name != "":
-			parts = append(parts, name)
-		default:
-			parts = append(parts, "_")
+	kinds := make(map[string]bool)
+	for _, kind := range strings.Split(s, ",") {
+		kind = strings.ToLower(strings.TrimSpace(kind))
+		if kind == "" {
+			continue
+		}
+
+		if !slices.Contains(symbolKinds, kind) {
+			return nil, fmt.Errorf("unknown kind %q in -kinds; valid kinds are: %s", kind, strings.Join(symbolKinds, ", "))
 		}
+
+		kinds[kind] = true
 	}
 
-	return strings.Join(parts, ", ")
+	if len(kinds) == 0 {
+		return nil, fmt.Errorf("-kinds must name at least one kind")
+	}
+
+	return kinds, nil
 }
 
-func formatReturnClause(returns []godoc.ArgInfo) string {
-	if len(returns) == 0 {
-		return ""
+// filterKinds drops the declarations whose kind is not in kinds. Methods are
+// listed with their type, so types are kept when methods are shown, and their
+// methods are dropped unless shown.
+func filterKinds(result godoc.Result, kinds map[string]bool) (godoc.Result, error) {
+	if kinds == nil {
+		return result, nil
 	}
 
-	parts := make([]string, 0, len(returns))
-	singleUnnamed := len(returns) == 1 && returns[0].Name == ""
-
-	for _, ret := range returns {
-		name := ret.Name
-		typ := ret.Type
+	switch v := result.(type) {
+	case godoc.PackageDoc:
+		if !kinds["const"] {
+			v.Consts = nil
+		}
+		if !kinds["var"] {
+			v.Vars = nil
+		}
+		if !kinds["func"] {
+			v.Funcs = nil
+		}
 		switch {
-		case name != "" && typ != "":
-			parts = append(parts, fmt.Sprintf("%s %s", name, typ))
-		case typ != "":
-			parts = append(parts, typ)
-		case name != "":
-			parts = append(parts, name)
-		default:
-			parts = append(parts, "_")
+		case !kinds["type"] && !kinds["method"]:
+			v.Types = nil
+		case !kinds["method"] || !kinds["func"]:
+			// The types may be shared with the cache.
+			v.Types = slices.Clone(v.Types)
+			for i := range v.Types {
+				if !kinds["method"] {
+					v.Types[i].Methods = nil
+				}
+				if !kinds["func"] {
+					v.Types[i].Constructors = nil
+				}
+			}
+		}
+
+		return v, nil
+	case godoc.SymbolDoc:
+		if !kinds[v.Kind] {
+			return nil, fmt.Errorf("%s %s is excluded by -kinds", v.Kind, v.Name)
 		}
-	}
 
-	if singleUnnamed {
-		return " " + parts[0]
+		return v, nil
 	}
 
-	return " (" + strings.Join(parts, ", ") + ")"
+	return result, nil
 }
 
-func formatFuncSignature(f godoc.FuncDoc) string {
-	params := formatParamList(f.Args)
-	returns := formatReturnClause(f.Returns)
+func outputJSON(w io.Writer, result godoc.Result) error {
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+
+	_, err = fmt.Fprintln(w, string(data))
 
-	return fmt.Sprintf("func %s(%s)%s", f.Name, params, returns)
+	return err
 }
 
-func formatReceiverClause(m godoc.MethodDoc) string {
-	recvType := m.RecvType
-	if recvType == "" {
-		recvType = m.Recv
+// outputVersions prints the available versions of the given module, one per
+// line, or as a JSON array if -json is set.
+func outputVersions(w io.Writer, cfg config, modulePath string) error {
+	g := newGodoc(cfg)
+
+	versions, err := g.ListVersions(modulePath)
+	if err != nil {
+		return fmt.Errorf("failed to list versions: %w", err)
 	}
-	if recvType == "" {
-		return ""
+
+	if cfg.format == "json" {
+		data, err := json.MarshalIndent(slices.Concat([]string{}, versions), "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+
+		_, err = fmt.Fprintln(w, string(data))
+
+		return err
 	}
 
-	recvName := m.RecvName
-	if recvName == "" {
-		return fmt.Sprintf("(%s)", recvType)
+	for _, version := range versions {
+		if _, err := fmt.Fprintln(w, version); err != nil {
+			return err
+		}
 	}
 
-	return fmt.Sprintf("(%s %s)", recvName, recvType)
+	return nil
 }
 
-func formatMethodSignature(m godoc.MethodDoc) string {
-	params := formatParamList(m.Args)
-	returns := formatReturnClause(m.Returns)
-	recv := formatReceiverClause(m)
-	if recv != "" {
-		return fmt.Sprintf("func %s %s(%s)%s", recv, m.Name, params, returns)
-	}
+func outputSearch(w io.Writer, cfg config, importPath string) error {
+	g := newGodoc(cfg)
 
-	return fmt.Sprintf("func %s(%s)%s", m.Name, params, returns)
-}
+	refs, err := g.Search(importPath, cfg.search, cfg.version)
+	if err != nil {
+		return fmt.Errorf("failed to search documentation: %w", err)
+	}
 
-func formatSymbolSignature(sym godoc.SymbolDoc) string {
-	if sym.FuncDoc == nil {
-		return ""
+	if cfg.kindSet != nil {
+		refs = slices.DeleteFunc(refs, func(ref godoc.SymbolRef) bool {
+			return !cfg.kindSet[ref.Kind]
+		})
 	}
 
-	switch sym.Kind {
-	case "method":
-		m := godoc.MethodDoc{
-			Recv:     sym.Receiver,
-			RecvName: sym.ReceiverName,
-			RecvType: sym.ReceiverType,
-			Name:     sym.Name,
-			Args:     sym.Args,
-			Returns:  sym.Returns,
+	if cfg.format == "json" {
+		data, err := json.MarshalIndent(slices.Concat([]godoc.SymbolRef{}, refs), "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
 		}
-		return formatMethodSignature(m)
-	case "func":
-		return formatFuncSignature(*sym.FuncDoc)
-	default:
-		return ""
+
+		_, err = fmt.Fprintln(w, string(data))
+
+		return err
 	}
-}
 
-func buildSymbolMarkdown(sym godoc.SymbolDoc) string {
-	var sb strings.Builder
-	appendDoc := true
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, ref := range refs {
+		fmt.Fprintf(tw, "%s\t%s\n", ref.Name, ref.Snippet)
+	}
 
-	// Package header
-	sb.WriteString(fmt.Sprintf("```\n// import %q\n```\n\n", sym.ImportPath))
+	return tw.Flush()
+}
 
-	if strings.EqualFold(sym.Kind, "type") && sym.TypeDoc != nil {
-		if decl := sym.Decl; decl != "" {
-			sb.WriteString("```go\n")
-			sb.WriteString(decl)
-			sb.WriteString("\n```\n\n")
-		}
+func outputJSONSchema(w io.Writer) error {
+	schema, err := godoc.JSONSchema()
+	if err != nil {
+		return fmt.Errorf("failed to generate JSON Schema: %w", err)
+	}
 
-		if doc := sym.DocText; doc != "" {
-			sb.WriteString(doc)
-			sb.WriteString("\n\n")
-			appendDoc = false
-		}
+	_, err = fmt.Fprintln(w, string(schema))
 
-		if sym.TypeDoc.Kind != "interface" {
-			for _, m := range sym.Methods {
-				sig := formatMethodSignature(m)
-				if sig != "" {
-					sb.WriteString("```go\n")
-					sb.WriteString(sig)
-					sb.WriteString("\n```\n\n")
-				}
-				if doc := m.Doc; doc != "" {
-					sb.WriteString(doc)
-					sb.WriteString("\n\n")
-				}
-			}
-		}
-	} else if sig := formatSymbolSignature(sym); sig != "" {
-		sb.WriteString("```go\n")
-		sb.WriteString(sig)
-		sb.WriteString("\n```\n\n")
+	return err
+}
+
+// outputHTML prints the HTML documentation of the result: a complete
+// document for packages, and the rendered doc comment for symbols.
+func outputHTML(w io.Writer, result godoc.Result) error {
+	html := result.HTML()
+	if page, ok := result.(interface{ FullHTML() string }); ok {
+		html = page.FullHTML()
 	}
 
-	if appendDoc {
-		if doc := sym.DocText; doc != "" {
-			sb.WriteString(doc)
-			sb.WriteString("\n")
-		}
+	_, err := fmt.Fprint(w, html)
+
+	return err
+}
+
+func outputManPage(w io.Writer, result godoc.Result) error {
+	page, ok := result.(interface{ ManPage() string })
+	if !ok {
+		return fmt.Errorf("man page output is not supported for %T", result)
 	}
 
-	return sb.String()
+	_, err := fmt.Fprint(w, page.ManPage())
+
+	return err
 }
 
 func parseCLIArgs(args []string) (string, string, error) {
@@ -518,50 +815,12 @@ func isExportedIdent(name string) bool {
 	return token.IsExported(name)
 }
 
-// convertDocLinks converts Go doc comment link syntax.
-//
-// It handles [Name], [pkg.Name], and [Type.Method].
-func convertDocLinks(text, importPath string) string {
-	return docLinksRe.ReplaceAllStringFunc(text, func(match string) string {
-		name := docLinksRe.FindStringSubmatch(match)[1]
-
-		return fmt.Sprintf(`[%s](https://pkg.go.dev/%s#%s)`, name, importPath, name)
-	})
-}
-
-// addLangIdentifier adds 'go' language identifier to markdown code blocks
-// that don't already have a language specified.
-func addLangIdentifier(markdown string) string {
-	lines := strings.Split(markdown, "\n")
-	inCodeBlock := false
-
-	for i, line := range lines {
-		trimmed := strings.TrimLeft(line, " \t")
-		if !strings.HasPrefix(trimmed, "```") {
-			continue
-		}
-
-		if !inCodeBlock {
-			fence := trimmed
-			if fence == "```" {
-				prefixLen := len(line) - len(trimmed)
-				prefix := ""
-				if prefixLen > 0 {
-					prefix = line[:prefixLen]
-				}
-
-				lines[i] = prefix + "```go"
-			}
-			inCodeBlock = true
-		} else {
-			inCodeBlock = false
-		}
+func getWordWrapWidth(cfg config) int {
+	// Output written with -o is not wrapped, as when redirected.
+	if cfg.output != "" {
+		return 0
 	}
 
-	return strings.Join(lines, "\n")
-}
-
-func getWordWrapWidth() int {
 	width, _, err := term.GetSize(int(os.Stdout.Fd()))
 	if err == nil && width > 0 {
 		if width <= defaultWordWrapWidth {
@@ -575,31 +834,19 @@ func getWordWrapWidth() int {
 }
 
 func renderMarkdown(result godoc.Result, cfg config) (string, string, string, error) {
-	var (
-		importPath string
-		markdown   = result.Text()
-	)
-
+	var importPath string
 	switch v := result.(type) {
 	case godoc.PackageDoc:
 		importPath = v.ImportPath
-		markdown = buildPkgMarkdown(v)
 	case godoc.SymbolDoc:
 		importPath = v.ImportPath
-		markdown = buildSymbolMarkdown(v)
-		if markdown == "" {
-			markdown = result.Text()
-		}
 	}
 
-	if importPath != "" {
-		markdown = convertDocLinks(markdown, importPath)
-	}
-	markdown = addLangIdentifier(markdown)
+	markdown := result.Markdown()
 	raw := markdown
 
 	renderOpts := []glamour.TermRendererOption{}
-	if width := getWordWrapWidth(); width > 0 {
+	if width := getWordWrapWidth(cfg); width > 0 {
 		renderOpts = append(renderOpts, glamour.WithWordWrap(width))
 	}
 
@@ -623,6 +870,65 @@ func renderMarkdown(result godoc.Result, cfg config) (string, string, string, er
 	return rendered, raw, importPath, nil
 }
 
+// pagerSegments returns the import line of the package of the result and the
+// declarations of the functions, types, and methods its documentation shows,
+// in order, for copying from the pager.
+func pagerSegments(result godoc.Result) (string, []pager.Segment) {
+	var segments []pager.Segment
+	add := func(decl string) {
+		if decl != "" {
+			segments = append(segments, pager.Segment{Text: decl})
+		}
+	}
+
+	// Interface methods are part of the declaration of their type.
+	addMethods := func(kind string, methods []godoc.MethodDoc) {
+		if kind == "interface" {
+			return
+		}
+
+		for _, m := range methods {
+			add(m.Signature())
+		}
+	}
+
+	switch v := result.(type) {
+	case godoc.PackageDoc:
+		// Constructors are listed under their type.
+		constructors := make(map[string]bool)
+		for _, t := range v.Types {
+			for _, f := range t.Constructors {
+				constructors[f.Name] = true
+			}
+		}
+
+		for _, f := range v.Funcs {
+			if !constructors[f.Name] {
+				add(f.Decl)
+			}
+		}
+
+		for _, t := range v.Types {
+			add(t.Decl)
+			for _, f := range t.Constructors {
+				add(f.Decl)
+			}
+			addMethods(t.Kind, t.Methods)
+		}
+
+		return fmt.Sprintf("import %q", v.ImportPath), segments
+	case godoc.SymbolDoc:
+		add(v.Decl)
+		if v.Kind == "type" && v.TypeDoc != nil {
+			addMethods(v.TypeDoc.Kind, v.Methods)
+		}
+
+		return fmt.Sprintf("import %q", v.ImportPath), segments
+	}
+
+	return "", nil
+}
+
 func buildPagerLabel(result godoc.Result, importPath, sel string) string {
 	label := strings.TrimSpace(importPath)
 
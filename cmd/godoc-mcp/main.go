@@ -16,39 +16,75 @@ type loadArgs struct {
 	ImportPath string `json:"import_path" jsonschema:"package import path (e.g., fmt, net/http, github.com/user/repo)"`
 	Selector   string `json:"selector,omitempty" jsonschema:"selector for symbol (function, type, method, const, or var) - empty for entire package"`
 	Version    string `json:"version,omitempty" jsonschema:"module version (e.g., v1.2.3, latest) - empty for default"`
+
+	InlineTypes int `json:"inline_types,omitempty" jsonschema:"depth up to which the declarations of package types referenced by a symbol are inlined - 0 to disable"`
 }
 
-func loadHandler(ctx context.Context, req *mcp.CallToolRequest, args loadArgs) (*mcp.CallToolResult, any, error) {
+type listArgs struct {
+	GOOS       string `json:"goos,omitempty" jsonschema:"target operating system (e.g., linux, darwin, windows)"`
+	GOARCH     string `json:"goarch,omitempty" jsonschema:"target architecture (e.g., amd64, arm64)"`
+	Workdir    string `json:"workdir,omitempty" jsonschema:"working directory for package resolution"`
+	ImportPath string `json:"import_path" jsonschema:"package import path (e.g., fmt, net/http, github.com/user/repo)"`
+	Version    string `json:"version,omitempty" jsonschema:"module version (e.g., v1.2.3, latest) - empty for default"`
+}
+
+type searchArgs struct {
+	GOOS       string `json:"goos,omitempty" jsonschema:"target operating system (e.g., linux, darwin, windows)"`
+	GOARCH     string `json:"goarch,omitempty" jsonschema:"target architecture (e.g., amd64, arm64)"`
+	Workdir    string `json:"workdir,omitempty" jsonschema:"working directory for package resolution"`
+	ImportPath string `json:"import_path" jsonschema:"package import path (e.g., fmt, net/http, github.com/user/repo)"`
+	Query      string `json:"query" jsonschema:"case-insensitive text matched against symbol names and documentation, or a regular expression enclosed in slashes (e.g., /^Marshal/)"`
+	Version    string `json:"version,omitempty" jsonschema:"module version (e.g., v1.2.3, latest) - empty for default"`
+}
+
+// symbolsResult is the structured result of the list and search tools.
+type symbolsResult struct {
+	Symbols []godoc.SymbolRef `json:"symbols" jsonschema:"matching symbols, whose names can be passed as the selector of the load tool"`
+}
+
+// newGodoc returns a [godoc.Godoc] bound to ctx for the given target
+// platform and working directory, with the extra options applied.
+func newGodoc(ctx context.Context, goos, goarch, workdir string, extra ...godoc.Option) godoc.Godoc {
 	var opts []godoc.Option
 
 	g := godoc.New(godoc.WithContext(context.Background()))
 
-	if args.GOOS != "" {
-		opts = append(opts, godoc.WithGOOS(args.GOOS))
+	if goos != "" {
+		opts = append(opts, godoc.WithGOOS(goos))
 	}
-	if args.GOARCH != "" {
-		opts = append(opts, godoc.WithGOARCH(args.GOARCH))
+	if goarch != "" {
+		opts = append(opts, godoc.WithGOARCH(goarch))
 	}
-	if args.Workdir != "" {
-		opts = append(opts, godoc.WithWorkdir(args.Workdir))
+	if workdir != "" {
+		opts = append(opts, godoc.WithWorkdir(workdir))
 	}
 
+	opts = append(opts, extra...)
 	opts = append(opts, godoc.WithContext(ctx))
-	if len(opts) > 0 {
-		g.SetOptions(opts...)
+	g.SetOptions(opts...)
+
+	return g
+}
+
+func loadHandler(ctx context.Context, req *mcp.CallToolRequest, args loadArgs) (*mcp.CallToolResult, any, error) {
+	var opts []godoc.Option
+	if args.InlineTypes > 0 {
+		opts = append(opts, godoc.WithInlineTypes(args.InlineTypes))
 	}
 
+	g := newGodoc(ctx, args.GOOS, args.GOARCH, args.Workdir, opts...)
+
 	result, err := g.Load(args.ImportPath, args.Selector, args.Version)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to load documentation: %w", err)
 	}
 
+	// Clients that only display the content still get the documentation,
+	// rendered as Markdown, alongside the structured result.
 	return &mcp.CallToolResult{
-		// Content: []mcp.Content{
-		// 	&mcp.TextContent{
-		// 		Text: fmt.Sprintf("Documentation loaded successfully for %s", args.ImportPath),
-		// 	},
-		// },
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: result.Markdown()},
+		},
 		Meta: map[string]any{
 			"import_path": args.ImportPath,
 			"selector":    args.Selector,
@@ -57,10 +93,43 @@ func loadHandler(ctx context.Context, req *mcp.CallToolRequest, args loadArgs) (
 	}, result, nil
 }
 
+func listHandler(ctx context.Context, req *mcp.CallToolRequest, args listArgs) (*mcp.CallToolResult, any, error) {
+	g := newGodoc(ctx, args.GOOS, args.GOARCH, args.Workdir)
+
+	symbols, err := g.ListSymbols(args.ImportPath, args.Version)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list symbols: %w", err)
+	}
+
+	return &mcp.CallToolResult{
+		Meta: map[string]any{
+			"import_path": args.ImportPath,
+			"version":     args.Version,
+		},
+	}, symbolsResult{Symbols: symbols}, nil
+}
+
+func searchHandler(ctx context.Context, req *mcp.CallToolRequest, args searchArgs) (*mcp.CallToolResult, any, error) {
+	g := newGodoc(ctx, args.GOOS, args.GOARCH, args.Workdir)
+
+	symbols, err := g.Search(args.ImportPath, args.Query, args.Version)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to search symbols: %w", err)
+	}
+
+	return &mcp.CallToolResult{
+		Meta: map[string]any{
+			"import_path": args.ImportPath,
+			"query":       args.Query,
+			"version":     args.Version,
+		},
+	}, symbolsResult{Symbols: symbols}, nil
+}
+
 func main() {
 	server := mcp.NewServer(&mcp.Implementation{
 		Name:    "godoc-mcp",
-		Version: "0.3.0",
+		Version: godoc.Version(),
 	}, nil)
 
 	mcp.AddTool(server, &mcp.Tool{
@@ -68,6 +137,16 @@ func main() {
 		Description: "Load Go package documentation for a package or specific selector.",
 	}, loadHandler)
 
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "list",
+		Description: "List the documented symbols of a Go package, to discover the selectors accepted by the load tool.",
+	}, listHandler)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "search",
+		Description: "Search the symbols of a Go package by name or documentation.",
+	}, searchHandler)
+
 	if err := server.Run(context.Background(), &mcp.StdioTransport{}); err != nil {
 		log.Fatalf("Server failed: %v", err)
 	}
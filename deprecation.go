@@ -0,0 +1,49 @@
+package godoc
+
+import (
+	"go/doc/comment"
+	"strings"
+)
+
+const deprecatedPrefix = "Deprecated:"
+
+// isDeprecated reports whether the given doc text marks its declaration as
+// deprecated, following the Go convention of a paragraph whose first word is
+// exactly "Deprecated:".
+func isDeprecated(text string) bool {
+	deprecated, _ := deprecation(text)
+
+	return deprecated
+}
+
+// deprecation reports whether the given doc text marks its declaration as
+// deprecated, as [isDeprecated] does, along with the text of the deprecation
+// paragraph following "Deprecated:", joined into a single line.
+func deprecation(text string) (bool, string) {
+	if !strings.Contains(text, deprecatedPrefix) {
+		return false, ""
+	}
+
+	parsed := new(comment.Parser).Parse(text)
+	for _, block := range parsed.Content {
+		para, ok := block.(*comment.Paragraph)
+		if !ok || len(para.Text) == 0 {
+			continue
+		}
+
+		plain, ok := para.Text[0].(comment.Plain)
+		if !ok {
+			continue
+		}
+
+		fields := strings.Fields(string(plain))
+		if len(fields) > 0 && fields[0] == deprecatedPrefix {
+			note := strings.TrimSpace(manText(para.Text))
+			note = strings.TrimPrefix(note, deprecatedPrefix)
+
+			return true, strings.Join(strings.Fields(note), " ")
+		}
+	}
+
+	return false, ""
+}
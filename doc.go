@@ -9,8 +9,8 @@
 //
 // # Output Formats
 //
-// All results implement the [Result] interface with Text(), HTML(), and
-// MarshalJSON() methods.
+// All results implement the [Result] interface with Text(), HTML(),
+// Markdown(), and MarshalJSON() methods.
 //
 // Get JSON output documentation:
 //
@@ -24,6 +24,10 @@
 //
 //	html := result.HTML()
 //
+// Get Markdown-formatted documentation, as rendered by godoc-cli:
+//
+//	md := result.Markdown()
+//
 // # Result Types
 //
 // Depending on the request, [Godoc.Load] returns either a [PackageDoc] or
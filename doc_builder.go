@@ -1,28 +1,35 @@
 package godoc
 
 import (
+	"cmp"
+	"go/ast"
 	"go/doc"
 	"go/doc/comment"
 	"go/token"
 	"go/types"
-	"sort"
+	"reflect"
+	"slices"
 	"strings"
 )
 
 // buildSymbolIndex builds a symbol index for the given package documentation.
-func buildSymbolIndex(p *doc.Package, fset *token.FileSet, typesInfo *types.Info, astInfo *packageAST, importPath string) map[string]SymbolDoc {
+func buildSymbolIndex(p *doc.Package, fset *token.FileSet, typesInfo *types.Info, astInfo *packageAST, importPath string, opts buildOptions) map[string]SymbolDoc {
 	if p == nil {
 		return nil
 	}
 
 	result := make(map[string]SymbolDoc)
+	valuesInfo := astInfo.valuesInfo(typesInfo)
 	parser := p.Parser()
 	htmlPrinter := p.Printer()
 	if htmlPrinter != nil {
-		htmlPrinter.HeadingLevel = 3
+		htmlPrinter.HeadingLevel = opts.pkgHeadingLevel() + 1
 	}
 
-	add := func(key string, doc SymbolDoc) {
+	// nodes holds the declaration of each symbol, to find the types it
+	// references.
+	nodes := make(map[string]ast.Node)
+	add := func(key string, doc SymbolDoc, node ast.Node) {
 		if key == "" {
 			return
 		}
@@ -32,12 +39,27 @@ func buildSymbolIndex(p *doc.Package, fset *token.FileSet, typesInfo *types.Info
 		}
 
 		result[key] = doc
+		if node != nil && !reflect.ValueOf(node).IsNil() {
+			nodes[key] = node
+		}
 	}
 
 	for _, t := range p.Types {
-		td := toTypeDoc(t, fset, typesInfo, astInfo)
+		td := toTypeDoc(t, fset, typesInfo, astInfo, opts)
+		parseFieldDocs(td.Fields, parser, htmlPrinter)
 		tdCopy := td
-		add(t.Name, makeSymbolDoc(importPath, p, parser, htmlPrinter, "type", t.Name, "", "", t.Doc, nil, nil, &tdCopy))
+		typeSym := makeSymbolDoc(importPath, p, parser, htmlPrinter, "type", t.Name, "", "", opts.docText(t.Doc), td.Decl, td.TypeParams, nil, nil, &tdCopy)
+		typeSym.Examples = toExampleDocs(t.Examples, fset)
+		spec := typeSpecForDocType(t)
+		if spec != nil {
+			typeSym.Pos = declPosition(fset, spec.Name.Pos())
+		}
+		add(t.Name, typeSym, spec)
+
+		docMethods := make(map[string]*doc.Func, len(t.Methods))
+		for _, m := range t.Methods {
+			docMethods[m.Name] = m
+		}
 
 		for _, m := range td.Methods {
 			recvType := m.Recv
@@ -45,246 +67,534 @@ func buildSymbolIndex(p *doc.Package, fset *token.FileSet, typesInfo *types.Info
 				recvType = m.RecvType
 			}
 			recvName := m.RecvName
-			add(t.Name+"."+m.Name, makeSymbolDoc(importPath, p, parser, htmlPrinter, "method", m.Name, recvName, recvType, m.Doc, m.Args, m.Returns, nil))
+			// Interface methods have no declaration of their own.
+			decl := methodSignature(m)
+			pos := interfaceMethodPos(spec, m.Name)
+			var examples []*doc.Example
+			var node ast.Node = interfaceMethodField(spec, m.Name)
+			if dm, ok := docMethods[m.Name]; ok {
+				decl = funcDecl(dm.Decl, fset)
+				pos = dm.Decl.Name.Pos()
+				examples = dm.Examples
+				node = dm.Decl
+			}
+
+			sym := makeSymbolDoc(importPath, p, parser, htmlPrinter, "method", m.Name, recvName, recvType, opts.docText(m.Doc), decl, nil, m.Args, m.Returns, nil)
+			sym.RecvPointer = m.RecvPointer
+			sym.Variadic = m.Variadic
+			sym.Examples = toExampleDocs(examples, fset)
+			sym.Pos = declPosition(fset, pos)
+			add(t.Name+"."+m.Name, sym, node)
 		}
 
 		for _, f := range t.Funcs {
-			args := extractArgs(f.Decl, fset, typesInfo)
-			results := extractResults(f.Decl, fset, typesInfo)
-			add(t.Name+"."+f.Name, makeSymbolDoc(importPath, p, parser, htmlPrinter, "func", f.Name, "", "", f.Doc, args, results, nil))
-			add(f.Name, makeSymbolDoc(importPath, p, parser, htmlPrinter, "func", f.Name, "", "", f.Doc, args, results, nil))
+			fd := toFuncDoc(f, fset, typesInfo, opts)
+			sym := makeSymbolDoc(importPath, p, parser, htmlPrinter, "func", f.Name, "", "", opts.docText(f.Doc), fd.Decl, fd.TypeParams, fd.Args, fd.Returns, nil)
+			sym.Variadic = fd.Variadic
+			sym.Examples = toExampleDocs(f.Examples, fset)
+			sym.Pos = declPosition(fset, f.Decl.Name.Pos())
+			add(t.Name+"."+f.Name, sym, f.Decl)
+			add(f.Name, sym, f.Decl)
 		}
 
 		for _, c := range t.Consts {
-			for _, name := range c.Names {
-				add(name, makeSymbolDoc(importPath, p, parser, htmlPrinter, "const", name, "", "", c.Doc, nil, nil, nil))
+			values := declValues(c, fset, valuesInfo)
+			for i, name := range c.Names {
+				sym := makeSymbolDoc(importPath, p, parser, htmlPrinter, "const", name, "", "", opts.docText(c.Doc), "", nil, nil, nil, nil)
+				sym.Pos = declPosition(fset, valueNamePos(c.Decl, name))
+				if values != nil {
+					sym.Value = values[i]
+				}
+				add(name, sym, valueSpec(c.Decl, name))
 			}
 		}
 
 		for _, v := range t.Vars {
-			for _, name := range v.Names {
-				add(name, makeSymbolDoc(importPath, p, parser, htmlPrinter, "var", name, "", "", v.Doc, nil, nil, nil))
+			values := declValues(v, fset, valuesInfo)
+			for i, name := range v.Names {
+				sym := makeSymbolDoc(importPath, p, parser, htmlPrinter, "var", name, "", "", opts.docText(v.Doc), "", nil, nil, nil, nil)
+				sym.Pos = declPosition(fset, valueNamePos(v.Decl, name))
+				if values != nil {
+					sym.Value = values[i]
+				}
+				add(name, sym, valueSpec(v.Decl, name))
 			}
 		}
 	}
 
 	for _, f := range p.Funcs {
-		add(f.Name, makeSymbolDoc(importPath, p, parser, htmlPrinter, "func", f.Name, "", "", f.Doc, extractArgs(f.Decl, fset, typesInfo), extractResults(f.Decl, fset, typesInfo), nil))
+		fd := toFuncDoc(f, fset, typesInfo, opts)
+		sym := makeSymbolDoc(importPath, p, parser, htmlPrinter, "func", f.Name, "", "", opts.docText(f.Doc), fd.Decl, fd.TypeParams, fd.Args, fd.Returns, nil)
+		sym.Variadic = fd.Variadic
+		sym.Examples = toExampleDocs(f.Examples, fset)
+		sym.Pos = declPosition(fset, f.Decl.Name.Pos())
+		add(f.Name, sym, f.Decl)
 	}
 
 	for _, c := range p.Consts {
-		for _, name := range c.Names {
-			add(name, makeSymbolDoc(importPath, p, parser, htmlPrinter, "const", name, "", "", c.Doc, nil, nil, nil))
+		values := declValues(c, fset, valuesInfo)
+		for i, name := range c.Names {
+			sym := makeSymbolDoc(importPath, p, parser, htmlPrinter, "const", name, "", "", opts.docText(c.Doc), "", nil, nil, nil, nil)
+			sym.Pos = declPosition(fset, valueNamePos(c.Decl, name))
+			if values != nil {
+				sym.Value = values[i]
+			}
+			add(name, sym, valueSpec(c.Decl, name))
 		}
 	}
 
 	for _, v := range p.Vars {
-		for _, name := range v.Names {
-			add(name, makeSymbolDoc(importPath, p, parser, htmlPrinter, "var", name, "", "", v.Doc, nil, nil, nil))
+		values := declValues(v, fset, valuesInfo)
+		for i, name := range v.Names {
+			sym := makeSymbolDoc(importPath, p, parser, htmlPrinter, "var", name, "", "", opts.docText(v.Doc), "", nil, nil, nil, nil)
+			sym.Pos = declPosition(fset, valueNamePos(v.Decl, name))
+			if values != nil {
+				sym.Value = values[i]
+			}
+			add(name, sym, valueSpec(v.Decl, name))
+		}
+	}
+
+	if opts.inlineDepth > 0 {
+		local := make(map[string]bool, len(p.Types))
+		for _, t := range p.Types {
+			local[t.Name] = true
 		}
+
+		inlineReferencedTypes(result, nodes, local, opts.inlineDepth)
+	}
+
+	if opts.resolveDocLinks {
+		newDocLinker(parser, importPath).symbols(result)
 	}
 
 	return result
 }
 
+// inlineReferencedTypes sets the InlineTypes of the symbols in index to the
+// declarations of the local types they reference, and in turn those
+// referenced by the inlined types, up to the given depth.
+func inlineReferencedTypes(index map[string]SymbolDoc, nodes map[string]ast.Node, local map[string]bool, depth int) {
+	refs := make(map[string][]string, len(nodes))
+	refsOf := func(key string) []string {
+		r, ok := refs[key]
+		if node, hasNode := nodes[key]; !ok && hasNode {
+			r = typeRefs(node, local)
+			refs[key] = r
+		}
+
+		return r
+	}
+
+	for key, sym := range index {
+		seen := make(map[string]bool)
+		if sym.Kind == "type" {
+			seen[sym.Name] = true
+		}
+
+		var inlined []InlineType
+		level := refsOf(key)
+		for d := 0; d < depth && len(level) > 0; d++ {
+			var next []string
+			for _, name := range level {
+				typ, ok := index[name]
+				if seen[name] || !ok || typ.Kind != "type" {
+					continue
+				}
+
+				seen[name] = true
+				inlined = append(inlined, InlineType{Name: name, Decl: typ.Decl})
+				next = append(next, refsOf(name)...)
+			}
+
+			level = next
+		}
+
+		sym.InlineTypes = inlined
+		index[key] = sym
+	}
+}
+
+// toFuncDoc converts a *[doc.Func] to a [FuncDoc].
+func toFuncDoc(f *doc.Func, fset *token.FileSet, typesInfo *types.Info, opts buildOptions) FuncDoc {
+	deprecated, note := deprecation(f.Doc)
+
+	return FuncDoc{
+		Name:       f.Name,
+		Decl:       funcDecl(f.Decl, fset),
+		TypeParams: extractTypeParams(f.Decl, fset, typesInfo, opts),
+		Args:       extractArgs(f.Decl, fset, typesInfo, opts),
+		Returns:    extractResults(f.Decl, fset, typesInfo, opts),
+		Variadic:   isVariadicDecl(f.Decl, typesInfo),
+		Doc:        opts.docText(f.Doc),
+
+		Deprecated:     deprecated,
+		DeprecatedNote: note,
+	}
+}
+
 // toTypeDoc converts a *[doc.Type] to a [TypeDoc], extracting fields and methods.
-func toTypeDoc(t *doc.Type, fset *token.FileSet, typesInfo *types.Info, astInfo *packageAST) TypeDoc {
+func toTypeDoc(t *doc.Type, fset *token.FileSet, typesInfo *types.Info, astInfo *packageAST, opts buildOptions) TypeDoc {
 	if t == nil {
 		return TypeDoc{}
 	}
 
 	kind, decl := typeDecl(t, fset, astInfo)
 
-	methods := make([]MethodDoc, 0, len(t.Methods))
-	seen := make(map[string]struct{}, len(t.Methods))
+	promoted := promotedMethods(t, typesInfo, opts)
+
+	var (
+		methods   = make([]MethodDoc, 0, len(t.Methods))
+		methodPos = make([]token.Pos, 0, len(t.Methods))
+		seen      = make(map[string]struct{}, len(t.Methods))
+		spec      = typeSpecForDocType(t)
+	)
 	for _, m := range t.Methods {
-		recvName, recvType := methodReceiverInfo(m.Decl, fset, typesInfo)
+		recvName, recvType, recvPointer := methodReceiverInfo(m.Decl, fset, typesInfo, opts)
 		if recvType == "" {
 			recvType = t.Name
 		}
 
+		deprecated, note := deprecation(m.Doc)
 		methods = append(methods, MethodDoc{
-			Recv:     t.Name,
-			RecvName: recvName,
-			RecvType: recvType,
-			Name:     m.Name,
-			Args:     extractArgs(m.Decl, fset, typesInfo),
-			Returns:  extractResults(m.Decl, fset, typesInfo),
-			Doc:      m.Doc,
+			Recv:        t.Name,
+			RecvName:    recvName,
+			RecvType:    recvType,
+			RecvPointer: recvPointer,
+			Name:        m.Name,
+			Args:        extractArgs(m.Decl, fset, typesInfo, opts),
+			Returns:     extractResults(m.Decl, fset, typesInfo, opts),
+			Variadic:    isVariadicDecl(m.Decl, typesInfo),
+			Doc:         opts.docText(m.Doc),
+
+			Deprecated:     deprecated,
+			DeprecatedNote: note,
 		})
+		methodPos = append(methodPos, m.Decl.Pos())
 		seen[m.Name] = struct{}{}
+
+		// go/doc lists the methods promoted from unexported embedded
+		// fields as the type's own.
+		if p, ok := promoted[m.Name]; ok && m.Level > 0 {
+			p.promote(&methods[len(methods)-1], t.Name)
+		}
+	}
+
+	for name, p := range promoted {
+		if _, ok := seen[name]; ok {
+			continue
+		}
+
+		m := MethodDoc{
+			Recv:    t.Name,
+			Name:    name,
+			Args:    argsFromSignature(p.sig, nil, opts),
+			Returns: resultsFromSignature(p.sig, nil, opts),
+
+			Variadic: p.sig != nil && p.sig.Variadic(),
+		}
+		p.promote(&m, t.Name)
+
+		methods = append(methods, m)
+		methodPos = append(methodPos, token.NoPos)
+		seen[name] = struct{}{}
 	}
 
-	if extra := interfaceMethodDocs(t, typesInfo); len(extra) > 0 {
+	if extra := interfaceMethodDocs(t, fset, typesInfo, opts); len(extra) > 0 {
 		for _, m := range extra {
 			if _, ok := seen[m.Name]; ok {
 				continue
 			}
 
 			methods = append(methods, m)
+			methodPos = append(methodPos, interfaceMethodPos(spec, m.Name))
 			seen[m.Name] = struct{}{}
 		}
 	}
 
-	sort.Slice(methods, func(i, j int) bool {
-		return methods[i].Name < methods[j].Name
+	sortDocs(methods, methodPos, fset, opts.sortMode, func(a, b MethodDoc) int {
+		return strings.Compare(a.Name, b.Name)
 	})
 
+	var constructors []FuncDoc
+	for _, f := range t.Funcs {
+		constructors = append(constructors, toFuncDoc(f, fset, typesInfo, opts))
+	}
+
+	var implements, implementedBy []string
+	if opts.implements && spec != nil && typesInfo != nil {
+		if obj, ok := typesInfo.Defs[spec.Name].(*types.TypeName); ok {
+			implements, implementedBy = typeRelations(obj, opts.unexported)
+		}
+	}
+
+	var aliasTarget string
+	if kind == "alias" {
+		aliasTarget = exprString(spec.Type, fset)
+	}
+
+	deprecated, note := deprecation(t.Doc)
+
 	return TypeDoc{
-		Name:    t.Name,
-		Doc:     t.Doc,
-		Decl:    decl,
-		Kind:    kind,
-		Fields:  structFieldDocs(t, fset, typesInfo, astInfo),
-		Methods: methods,
+		Name:       t.Name,
+		TypeParams: typeSpecTypeParams(spec, fset, typesInfo, opts),
+		Doc:        opts.docText(t.Doc),
+		Decl:       decl,
+		Kind:       kind,
+		Fields:     structFieldDocs(t, fset, typesInfo, astInfo, opts),
+		Embeds:     interfaceEmbeds(spec, fset),
+		Methods:    methods,
+
+		AliasTarget:  aliasTarget,
+		Constructors: constructors,
+
+		Implements:    implements,
+		ImplementedBy: implementedBy,
+
+		Deprecated:     deprecated,
+		DeprecatedNote: note,
 	}
 }
 
 // toPkgDoc converts a *[doc.Package] to a [PackageDoc], extracting constants,
 // variables, functions, and types.
-func toPkgDoc(p *doc.Package, fset *token.FileSet, typesInfo *types.Info, astInfo *packageAST, importPath string) PackageDoc {
-	syn := p.Synopsis(p.Doc)
+func toPkgDoc(p *doc.Package, fset *token.FileSet, typesInfo *types.Info, astInfo *packageAST, importPath string, opts buildOptions) PackageDoc {
+	pkgText := p.Doc
+	if opts.stripLicense {
+		pkgText = stripLicenseHeader(pkgText)
+	}
+
+	syn := p.Synopsis(pkgText)
 	parser := p.Parser()
 	htmlPrinter := p.Printer()
 	if htmlPrinter != nil {
-		htmlPrinter.HeadingLevel = 2
+		htmlPrinter.HeadingLevel = opts.pkgHeadingLevel()
 	}
 
+	valuesInfo := astInfo.valuesInfo(typesInfo)
+
 	var (
 		consts = make([]ValueDoc, 0, len(p.Consts))
 		vars   = make([]ValueDoc, 0, len(p.Vars))
 		funcs  = make([]FuncDoc, 0, len(p.Funcs)+len(p.Types))
 		types  = make([]TypeDoc, 0, len(p.Types))
+
+		// Declaration positions, parallel to the docs, for SortSource.
+		constPos, varPos, funcPos, typePos []token.Pos
 	)
 
 	for _, c := range p.Consts {
 		consts = append(consts, ValueDoc{
-			Names: c.Names,
-			Doc:   c.Doc,
+			Names:  c.Names,
+			Values: declValues(c, fset, valuesInfo),
+			Doc:    opts.docText(c.Doc),
+			Decl:   valueGroupDecl(c.Decl, fset),
 		})
+		constPos = append(constPos, c.Decl.Pos())
 	}
 
 	for _, v := range p.Vars {
 		vars = append(vars, ValueDoc{
-			Names: v.Names,
-			Doc:   v.Doc,
+			Names:  v.Names,
+			Values: declValues(v, fset, valuesInfo),
+			Doc:    opts.docText(v.Doc),
+			Decl:   valueGroupDecl(v.Decl, fset),
 		})
+		varPos = append(varPos, v.Decl.Pos())
 	}
 
 	for _, f := range p.Funcs {
-		funcs = append(funcs, FuncDoc{
-			Name:    f.Name,
-			Args:    extractArgs(f.Decl, fset, typesInfo),
-			Returns: extractResults(f.Decl, fset, typesInfo),
-			Doc:     f.Doc,
-		})
+		funcs = append(funcs, toFuncDoc(f, fset, typesInfo, opts))
+		funcPos = append(funcPos, f.Decl.Pos())
 	}
 
 	for _, t := range p.Types {
 		for _, c := range t.Consts {
 			consts = append(consts, ValueDoc{
-				Names: c.Names,
-				Doc:   c.Doc,
+				Names:  c.Names,
+				Values: declValues(c, fset, valuesInfo),
+				Doc:    opts.docText(c.Doc),
+				Decl:   valueGroupDecl(c.Decl, fset),
 			})
+			constPos = append(constPos, c.Decl.Pos())
 		}
 
 		for _, v := range t.Vars {
 			vars = append(vars, ValueDoc{
-				Names: v.Names,
-				Doc:   v.Doc,
+				Names:  v.Names,
+				Values: declValues(v, fset, valuesInfo),
+				Doc:    opts.docText(v.Doc),
+				Decl:   valueGroupDecl(v.Decl, fset),
 			})
+			varPos = append(varPos, v.Decl.Pos())
 		}
 
 		for _, f := range t.Funcs {
-			funcs = append(funcs, FuncDoc{
-				Name:    f.Name,
-				Args:    extractArgs(f.Decl, fset, typesInfo),
-				Returns: extractResults(f.Decl, fset, typesInfo),
-				Doc:     f.Doc,
-			})
+			funcs = append(funcs, toFuncDoc(f, fset, typesInfo, opts))
+			funcPos = append(funcPos, f.Decl.Pos())
 		}
 
-		typeDoc := toTypeDoc(t, fset, typesInfo, astInfo)
+		typeDoc := toTypeDoc(t, fset, typesInfo, astInfo, opts)
+		parseFieldDocs(typeDoc.Fields, parser, htmlPrinter)
 		types = append(types, typeDoc)
+		if spec := typeSpecForDocType(t); spec != nil {
+			typePos = append(typePos, spec.Pos())
+		} else {
+			typePos = append(typePos, t.Decl.Pos())
+		}
 	}
 
-	var html string
-	if p.Doc != "" {
-		var docParsed *comment.Doc
+	var (
+		html      string
+		docParsed *comment.Doc
+	)
+	if pkgText != "" && !opts.synopsisOnly {
 		if parser != nil {
-			docParsed = parser.Parse(p.Doc)
+			docParsed = parser.Parse(pkgText)
 		} else {
-			docParsed = new(comment.Parser).Parse(p.Doc)
+			docParsed = new(comment.Parser).Parse(pkgText)
 		}
 
 		if htmlPrinter != nil {
 			html = string(htmlPrinter.HTML(docParsed))
 		} else {
-			r := comment.Printer{HeadingLevel: 2}
+			r := comment.Printer{HeadingLevel: opts.pkgHeadingLevel()}
 			html = string(r.HTML(docParsed))
 		}
 	}
 
-	sort.Slice(consts, func(i, j int) bool {
-		return strings.Join(consts[i].Names, ",") < strings.Join(consts[j].Names, ",")
-	})
-	sort.Slice(vars, func(i, j int) bool {
-		return strings.Join(vars[i].Names, ",") < strings.Join(vars[j].Names, ",")
-	})
-	sort.Slice(funcs, func(i, j int) bool {
-		return funcs[i].Name < funcs[j].Name
+	sortDocs(consts, constPos, fset, opts.sortMode, compareValueDocs)
+	sortDocs(vars, varPos, fset, opts.sortMode, compareValueDocs)
+	sortDocs(funcs, funcPos, fset, opts.sortMode, func(a, b FuncDoc) int {
+		return strings.Compare(a.Name, b.Name)
 	})
-	sort.Slice(types, func(i, j int) bool {
-		return types[i].Name < types[j].Name
+	sortDocs(types, typePos, fset, opts.sortMode, func(a, b TypeDoc) int {
+		return strings.Compare(a.Name, b.Name)
 	})
 
-	return PackageDoc{
+	var (
+		tests       testFuncs
+		constraints []string
+		notes       map[string][]NoteDoc
+	)
+	if astInfo != nil {
+		tests = collectTestFuncs(astInfo.testFiles)
+		constraints = astInfo.buildConstraints
+	}
+
+	if !opts.synopsisOnly {
+		notes = packageNotes(p.Notes)
+	}
+
+	pkgDoc := PackageDoc{
 		ImportPath: importPath,
 		Name:       p.Name,
 		Synopsis:   syn,
-		DocText:    p.Doc,
+		DocText:    opts.docText(pkgText),
 		DocHTML:    html,
 		Consts:     consts,
 		Vars:       vars,
 		Funcs:      funcs,
 		Types:      types,
+		Examples:   packageExamples(p, fset),
+		Tests:      tests.tests,
+		Benchmarks: tests.benchmarks,
+		Fuzzes:     tests.fuzzes,
+		Notes:      notes,
+
+		BuildConstraints: constraints,
+		docParsed:        docParsed,
 	}
+
+	if opts.resolveDocLinks {
+		newDocLinker(parser, importPath).pkgDoc(&pkgDoc)
+	}
+
+	return pkgDoc
 }
 
-// makeSymbolDoc creates a SymbolDoc with the provided information, generating
-// HTML documentation if a parser and printer are provided.
-func makeSymbolDoc(importPath string, p *doc.Package, parser *comment.Parser, printer *comment.Printer, kind, name, recvName, recvType, text string, args, returns []ArgInfo, typeDoc *TypeDoc) SymbolDoc {
-	var (
-		html      string
-		docParsed *comment.Doc
+// packageNotes converts the marked comments collected by go/doc, keyed by
+// marker, or returns nil if there are none.
+func packageNotes(notes map[string][]*doc.Note) map[string][]NoteDoc {
+	if len(notes) == 0 {
+		return nil
+	}
+
+	out := make(map[string][]NoteDoc, len(notes))
+	for marker, list := range notes {
+		docs := make([]NoteDoc, 0, len(list))
+		for _, n := range list {
+			docs = append(docs, NoteDoc{UID: n.UID, Body: n.Body})
+		}
+
+		out[marker] = docs
+	}
+
+	return out
+}
+
+// compareValueDocs orders constant or variable groups by their joined names,
+// breaking ties by values and then documentation so that the order is total.
+func compareValueDocs(a, b ValueDoc) int {
+	return cmp.Or(
+		strings.Compare(strings.Join(a.Names, ","), strings.Join(b.Names, ",")),
+		slices.Compare(a.Values, b.Values),
+		strings.Compare(a.Doc, b.Doc),
 	)
+}
+
+// parseFieldDocs parses the documentation of struct fields with parser, if
+// provided, so that their HTML is rendered with printer on first use.
+func parseFieldDocs(fields []FieldDoc, parser *comment.Parser, printer *comment.Printer) {
+	if parser == nil {
+		parser = new(comment.Parser)
+	}
+
+	for i := range fields {
+		if fields[i].Doc == "" {
+			continue
+		}
 
+		fields[i].docParsed = parser.Parse(fields[i].Doc)
+		fields[i].html = &lazyHTML{printer: printer}
+	}
+}
+
+// makeSymbolDoc creates a SymbolDoc with the provided information. Its HTML
+// documentation is rendered with printer, if provided, on first use.
+func makeSymbolDoc(importPath string, p *doc.Package, parser *comment.Parser, printer *comment.Printer, kind, name, recvName, recvType, text, decl string, typeParams, args, returns []ArgInfo, typeDoc *TypeDoc) SymbolDoc {
+	var docParsed *comment.Doc
 	if text != "" {
 		if parser != nil {
 			docParsed = parser.Parse(text)
 		} else {
 			docParsed = new(comment.Parser).Parse(text)
 		}
-
-		if printer != nil {
-			html = string(printer.HTML(docParsed))
-		} else {
-			r := comment.Printer{HeadingLevel: 3}
-			html = string(r.HTML(docParsed))
-		}
 	}
 
+	deprecated, note := deprecation(text)
+
 	var funcDoc *FuncDoc
 	if kind == "func" || kind == "method" {
 		fd := FuncDoc{
-			Name:    name,
-			Args:    args,
-			Returns: returns,
-			Doc:     text,
+			Name:       name,
+			Decl:       decl,
+			TypeParams: typeParams,
+			Args:       args,
+			Returns:    returns,
+			Doc:        text,
+
+			Deprecated:     deprecated,
+			DeprecatedNote: note,
 		}
 		funcDoc = &fd
 	}
 
+	var aliasTarget string
+	if typeDoc != nil {
+		aliasTarget = typeDoc.AliasTarget
+	}
+
 	return SymbolDoc{
 		ImportPath:   importPath,
 		Package:      p.Name,
@@ -293,10 +603,18 @@ func makeSymbolDoc(importPath string, p *doc.Package, parser *comment.Parser, pr
 		Receiver:     receiverDisplayName(recvType),
 		ReceiverName: recvName,
 		ReceiverType: recvType,
+		Decl:         decl,
+		TypeParams:   typeParams,
 		FuncDoc:      funcDoc,
 		TypeDoc:      typeDoc,
 		DocText:      text,
-		DocHTML:      html,
 		docParsed:    docParsed,
+		html:         &lazyHTML{printer: printer},
+
+		Deprecated:     deprecated,
+		DeprecatedNote: note,
+
+		IsAlias:     typeDoc != nil && typeDoc.Kind == "alias",
+		AliasTarget: aliasTarget,
 	}
 }
@@ -1,9 +1,77 @@
 package godoc
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 var (
-	ErrEmptyImportPath   = fmt.Errorf("import path cannot be empty")
-	ErrInvalidImportPath = fmt.Errorf("invalid import path")
-	ErrInvalidSelector   = fmt.Errorf("invalid selector format")
+	ErrEmptyImportPath    = fmt.Errorf("import path cannot be empty")
+	ErrInvalidImportPath  = fmt.Errorf("invalid import path")
+	ErrInvalidSelector    = fmt.Errorf("invalid selector format")
+	ErrInvalidGoBinary    = fmt.Errorf("invalid go binary")
+	ErrInvalidPlatform    = fmt.Errorf("invalid platform")
+	ErrInvalidFilePattern = fmt.Errorf("invalid file pattern")
+	ErrInvalidSearch      = fmt.Errorf("invalid search query")
+
+	// ErrSymbolNotFound is matched by the [SymbolNotFoundError] returned when
+	// a package loads but does not declare the selected symbol.
+	ErrSymbolNotFound = fmt.Errorf("symbol not found")
+
+	// ErrAmbiguousSymbol is matched by the [AmbiguousSymbolError] returned
+	// when a selector matches several symbols, as with [MatchPrefix].
+	ErrAmbiguousSymbol = fmt.Errorf("ambiguous symbol")
 )
+
+// SymbolNotFoundError reports that a package was loaded, but has no symbol
+// matching the selector. It matches [ErrSymbolNotFound] with [errors.Is].
+type SymbolNotFoundError struct {
+	ImportPath string // Import path of the loaded package
+	Symbol     string // Selector of the missing symbol
+
+	// Suggestions are up to five symbols of the package with similar
+	// names, most similar first, e.g. "Printf" for "printf".
+	Suggestions []string
+}
+
+// Error implements the error interface. It includes the suggestions, if
+// any, as a "did you mean" hint.
+func (e *SymbolNotFoundError) Error() string {
+	msg := fmt.Sprintf("selector %q not found in %q", e.Symbol, e.ImportPath)
+
+	switch n := len(e.Suggestions); n {
+	case 0:
+		return msg
+	case 1:
+		return fmt.Sprintf("%s; did you mean %s?", msg, e.Suggestions[0])
+	case 2:
+		return fmt.Sprintf("%s; did you mean %s or %s?", msg, e.Suggestions[0], e.Suggestions[1])
+	default:
+		return fmt.Sprintf("%s; did you mean %s, or %s?", msg, strings.Join(e.Suggestions[:n-1], ", "), e.Suggestions[n-1])
+	}
+}
+
+// Unwrap returns [ErrSymbolNotFound].
+func (e *SymbolNotFoundError) Unwrap() error {
+	return ErrSymbolNotFound
+}
+
+// AmbiguousSymbolError reports that a selector matches several symbols of a
+// package with the [SymbolMatch] mode in use, or that several types declare
+// the method named by an unqualified selector. It matches
+// [ErrAmbiguousSymbol] with [errors.Is].
+type AmbiguousSymbolError struct {
+	ImportPath string   // Import path of the loaded package
+	Symbol     string   // Selector matching several symbols
+	Candidates []string // Names of the matched symbols, sorted
+}
+
+// Error implements the error interface.
+func (e *AmbiguousSymbolError) Error() string {
+	return fmt.Sprintf("selector %q is ambiguous in %q: %s", e.Symbol, e.ImportPath, strings.Join(e.Candidates, ", "))
+}
+
+// Unwrap returns [ErrAmbiguousSymbol].
+func (e *AmbiguousSymbolError) Unwrap() error {
+	return ErrAmbiguousSymbol
+}
@@ -0,0 +1,170 @@
+package godoc
+
+import (
+	"bytes"
+	"go/ast"
+	"go/build"
+	"go/doc"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// testFiles parses the _test.go files of the package in dir that match the
+// build constraints of goos/goarch and the given build tags, for their
+// examples and test functions. Files are added to fset; those that cannot be
+// read or parsed are skipped.
+func testFiles(fset *token.FileSet, dir, goos, goarch string, tags []string) []*ast.File {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+
+	ctxt := build.Default
+	ctxt.GOOS, ctxt.GOARCH = goos, goarch
+	ctxt.BuildTags = tags
+
+	var files []*ast.File
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+
+		if ok, err := ctxt.MatchFile(dir, name); err != nil || !ok {
+			continue
+		}
+
+		f, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, parser.ParseComments)
+		if err != nil {
+			continue
+		}
+
+		files = append(files, f)
+	}
+
+	return files
+}
+
+// copyExamples attaches the examples of src to the package, functions,
+// types, and methods of dst with the same names.
+func copyExamples(dst, src *doc.Package) {
+	funcs := make(map[string][]*doc.Example)
+	types := make(map[string]*doc.Type, len(src.Types))
+	for _, f := range src.Funcs {
+		funcs[f.Name] = f.Examples
+	}
+
+	for _, t := range src.Types {
+		types[t.Name] = t
+		for _, f := range t.Funcs {
+			funcs[f.Name] = f.Examples
+		}
+	}
+
+	dst.Examples = src.Examples
+	for _, f := range dst.Funcs {
+		f.Examples = funcs[f.Name]
+	}
+
+	for _, t := range dst.Types {
+		for _, f := range t.Funcs {
+			f.Examples = funcs[f.Name]
+		}
+
+		st, ok := types[t.Name]
+		if !ok {
+			continue
+		}
+
+		t.Examples = st.Examples
+
+		methods := make(map[string][]*doc.Example, len(st.Methods))
+		for _, m := range st.Methods {
+			methods[m.Name] = m.Examples
+		}
+
+		for _, m := range t.Methods {
+			m.Examples = methods[m.Name]
+		}
+	}
+}
+
+// toExampleDocs converts the given examples to [ExampleDoc]s.
+func toExampleDocs(examples []*doc.Example, fset *token.FileSet) []ExampleDoc {
+	if len(examples) == 0 {
+		return nil
+	}
+
+	docs := make([]ExampleDoc, 0, len(examples))
+	for _, ex := range examples {
+		docs = append(docs, ExampleDoc{
+			Name:   ex.Name,
+			Code:   exampleCode(ex, fset),
+			Output: ex.Output,
+			Doc:    ex.Doc,
+		})
+	}
+
+	return docs
+}
+
+// packageExamples returns the examples of the package and all of its
+// symbols, ordered by name.
+func packageExamples(p *doc.Package, fset *token.FileSet) []ExampleDoc {
+	examples := append([]*doc.Example(nil), p.Examples...)
+	for _, f := range p.Funcs {
+		examples = append(examples, f.Examples...)
+	}
+
+	for _, t := range p.Types {
+		examples = append(examples, t.Examples...)
+		for _, f := range t.Funcs {
+			examples = append(examples, f.Examples...)
+		}
+
+		for _, m := range t.Methods {
+			examples = append(examples, m.Examples...)
+		}
+	}
+
+	docs := toExampleDocs(examples, fset)
+	sort.SliceStable(docs, func(i, j int) bool {
+		return docs[i].Name < docs[j].Name
+	})
+
+	return docs
+}
+
+// exampleCode formats the code of the given example. The body of a function
+// example is unindented and stripped of its braces and trailing output
+// comment; whole-file examples are printed as is.
+func exampleCode(ex *doc.Example, fset *token.FileSet) string {
+	var buf bytes.Buffer
+	node := &printer.CommentedNode{Node: ex.Code, Comments: ex.Comments}
+	if err := gofmtPrinter.Fprint(&buf, fset, node); err != nil {
+		return ""
+	}
+
+	code := buf.String()
+	if _, ok := ex.Code.(*ast.BlockStmt); !ok {
+		return strings.TrimSpace(code)
+	}
+
+	code = strings.TrimSpace(code)
+	code = strings.TrimPrefix(code, "{")
+	code = strings.TrimSuffix(code, "}")
+	code = strings.ReplaceAll(code, "\n\t", "\n")
+
+	if ex.Output != "" || ex.EmptyOutput {
+		if loc := exampleOutputRe.FindAllStringIndex(code, -1); len(loc) > 0 {
+			code = code[:loc[len(loc)-1][0]]
+		}
+	}
+
+	return strings.TrimSpace(code)
+}
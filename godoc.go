@@ -7,16 +7,24 @@ import (
 	"go/doc"
 	"go/token"
 	"go/types"
+	"io/fs"
+	"maps"
 	"os"
 	"path/filepath"
 	"runtime"
+	"slices"
 	"strings"
 	"sync"
+	"time"
 
-	"golang.org/x/mod/modfile"
+	"go.dw1.io/fastcache"
 	"golang.org/x/tools/go/packages"
 )
 
+// DefaultMaxConcurrentFetches is the default number of remote module fetches
+// that may run concurrently across all [Godoc] instances.
+const DefaultMaxConcurrentFetches = 4
+
 // Godoc handles the extraction of Go package documentation.
 type Godoc struct {
 	goos     string
@@ -26,6 +34,44 @@ type Godoc struct {
 	loadPkg  func(string, string, bool) (*doc.Package, *token.FileSet, *types.Info, *packageAST, string, *packages.Module, string, error)
 	checkDep func(string, string) (string, func(), error)
 	depCache *sync.Map
+	fetchSem chan struct{}
+	loadSem  chan struct{}
+	sumDB    string
+	goProxy  string
+	goBinary string
+	goEnv    map[string]string
+	timeout  time.Duration
+	onEvict  func(key string)
+
+	filePattern string
+
+	source     fs.FS
+	sourceRoot string
+
+	platforms []string
+	buildTags []string
+	modMode   ModMode
+
+	implementers bool
+	rawComments  bool
+	unexported   bool
+	prettyTypes  bool
+	inlineTypes  int
+	testFiles    bool
+	sortMode     SortMode
+	implements   bool
+	headingLevel int
+	synopsisOnly bool
+	stripLicense bool
+
+	symbolMatch     SymbolMatch
+	includeInternal bool
+	resolveDocLinks bool
+
+	cacheMode CacheMode
+	cacheDir  string
+	cacheTTL  time.Duration
+	cacheSize int
 }
 
 // New creates a new [Godoc] with the specified configuration.
@@ -34,6 +80,8 @@ func New(opts ...Option) Godoc {
 		workdir:  ".", // Default
 		ctx:      context.Background(),
 		depCache: &sync.Map{},
+		fetchSem: fetchSem,
+		loadSem:  make(chan struct{}, runtime.NumCPU()),
 	}
 
 	g.SetOptions(opts...)
@@ -42,9 +90,6 @@ func New(opts ...Option) Godoc {
 		g.ctx = context.Background()
 	}
 
-	g.loadPkg = g.loadDocPkg
-	g.checkDep = g.checkModuleDep
-
 	return g
 }
 
@@ -57,21 +102,58 @@ func (d *Godoc) context() context.Context {
 	return d.ctx
 }
 
+// withTimeout returns a copy of d whose context expires after the timeout
+// set with [WithTimeout], along with the function releasing the context.
+// Without a timeout, it returns d itself.
+func (d *Godoc) withTimeout() (*Godoc, context.CancelFunc) {
+	if d == nil || d.timeout <= 0 {
+		return d, func() {}
+	}
+
+	call := *d
+	ctx, cancel := context.WithTimeout(d.context(), d.timeout)
+	call.ctx = ctx
+
+	return &call, cancel
+}
+
 // Load loads documentation for a Go package or a specific selector within it.
 //
 // If sel is empty, it loads the entire package documentation.
 // Otherwise, it loads documentation for the specified selector (type, method,
-// function, const, or var).
+// function, const, or var). Methods are selected as "Type.Method"; the
+// path-safe forms "Type/Method" and "Type#Method" are accepted as well. A
+// method name alone, such as "Do", selects the method if no other symbol
+// has that name and only one type declares it; if several do, Load fails
+// with an [AmbiguousSymbolError] listing them as "Type.Method".
 //
 // For remote packages, it may add them to the current module to fetch the
 // documentation.
 //
 // Version specifies the module version to use; if empty, uses the latest.
+// Besides exact versions (e.g. "v1.2.3") and pseudo-versions, the following
+// module queries are supported, with or without a leading "@":
+//
+//   - "latest": the latest release (or pseudo-version if there is none).
+//   - "upgrade": like "latest", but never lower than the version currently
+//     required by the working directory's go.mod.
+//   - "patch": the latest patch release of the major and minor version
+//     currently required by the working directory's go.mod; like "latest"
+//     if the module is not required there.
+//
+// Queries are resolved to a concrete version, which is reported as the
+// module version in the cached metadata of the result.
 func (d *Godoc) Load(importPath, sel, version string) (Result, error) {
+	d, cancel := d.withTimeout()
+	defer cancel()
+
+	sel = normalizeSelector(sel)
 	if err := validateInputs(importPath, sel); err != nil {
 		return nil, err
 	}
 
+	version = normalizeVersion(version)
+
 	if sel == "" {
 		pkgDoc, _, err := d.getOrLoadPkg(importPath, version)
 		if err != nil {
@@ -95,17 +177,162 @@ func (d *Godoc) Load(importPath, sel, version string) (Result, error) {
 	return symDoc, nil
 }
 
+// LoadRequest identifies the documentation to load in a
+// [Godoc.LoadMultiple] batch. Its fields are the arguments of [Godoc.Load].
+type LoadRequest struct {
+	ImportPath string
+	Sel        string
+	Version    string
+}
+
+// LoadMultiple loads the documentation for several requests concurrently,
+// using a pool of workers that share the cache, as many as the package
+// loads allowed by [WithConcurrency].
+//
+// The results and errors are indexed like requests: a failing request only
+// sets its own error, leaving the rest of the batch unaffected. Requests not
+// yet started when the context of d is done fail with the context's error.
+// Remote module fetches remain limited as configured by
+// [WithMaxConcurrentFetches].
+func (d *Godoc) LoadMultiple(requests []LoadRequest) ([]Result, []error) {
+	results := make([]Result, len(requests))
+	errs := make([]error, len(requests))
+
+	ctx := d.context()
+	jobs := make(chan int)
+
+	var wg sync.WaitGroup
+	for range min(d.concurrency(), len(requests)) {
+		wg.Go(func() {
+			for i := range jobs {
+				if err := ctx.Err(); err != nil {
+					errs[i] = err
+					continue
+				}
+
+				req := requests[i]
+				results[i], errs[i] = d.Load(req.ImportPath, req.Sel, req.Version)
+			}
+		})
+	}
+
+	for i := range requests {
+		jobs <- i
+	}
+	close(jobs)
+
+	wg.Wait()
+
+	return results, errs
+}
+
+// ListSymbols returns the documented symbols of the package with the given
+// import path, ordered by name. Names are the selectors accepted by
+// [Godoc.Load]: methods are listed as "Type.Method", and functions returning
+// a type are listed both on their own and as "Type.Func".
+//
+// Version is interpreted as in [Godoc.Load].
+func (d *Godoc) ListSymbols(importPath, version string) ([]SymbolRef, error) {
+	d, cancel := d.withTimeout()
+	defer cancel()
+
+	if err := validateInputs(importPath, ""); err != nil {
+		return nil, err
+	}
+
+	symbols, err := d.symbolIndex(importPath, normalizeVersion(version))
+	if err != nil {
+		return nil, err
+	}
+
+	refs := make([]SymbolRef, 0, len(symbols))
+	for key, sym := range symbols {
+		refs = append(refs, SymbolRef{Name: key, Kind: sym.Kind, Receiver: sym.Receiver})
+	}
+
+	slices.SortFunc(refs, func(a, b SymbolRef) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return refs, nil
+}
+
+// PackageName returns the name declared in the package clause of the
+// package with the given import path, which can differ from the last element
+// of the path (e.g. "yaml" for "gopkg.in/yaml.v3").
+//
+// It only loads the package metadata, which is cheaper than a full [Load].
+// Version is interpreted as in [Godoc.Load].
+func (d *Godoc) PackageName(importPath, version string) (string, error) {
+	d, cancel := d.withTimeout()
+	defer cancel()
+
+	if err := validateInputs(importPath, ""); err != nil {
+		return "", err
+	}
+
+	version = normalizeVersion(version)
+
+	name, module, err := d.loadPkgName(importPath, "")
+	if err == nil && !moduleVersionMatches(module, version) {
+		err = fmt.Errorf("module %s@%s does not satisfy requested version %q", module.Path, module.Version, version)
+	}
+
+	if err == nil {
+		return name, nil
+	}
+
+	// Packages of a source file system are never fetched.
+	if d.source != nil {
+		return "", err
+	}
+
+	checkDep := d.checkDep
+	if checkDep == nil {
+		checkDep = d.checkModuleDep
+	}
+
+	// Fetching the module is pointless once canceled.
+	if ctxErr := d.context().Err(); ctxErr != nil {
+		return "", ctxErr
+	}
+
+	modDir, cleanup, err2 := checkDep(importPath, version)
+	if err2 != nil {
+		return "", fmt.Errorf("local load failed (%w) and module dependency setup failed (%w)", err, err2)
+	}
+
+	if cleanup != nil && modDir != d.workdir {
+		defer cleanup()
+	}
+
+	name, _, err = d.loadPkgName(importPath, modDir)
+	if err != nil {
+		return "", fmt.Errorf("load with module dependency failed: %w", err)
+	}
+
+	return name, nil
+}
+
 // getOrLoadPkg gets package doc from cache (or loads it if not cached).
 func (d *Godoc) getOrLoadPkg(importPath, version string) (PackageDoc, string, error) {
-	cache, err := getCache()
+	var stdKey string
+	if mayBeStdlib(importPath) && !d.cacheDisabled() {
+		stdKey = d.stdlibCacheKey(importPath, "")
+		if entry, ok := stdlibCache.Get(stdKey); ok && entry.Package != nil && !d.expired(entry.cacheMetadata) {
+			return *entry.Package, entry.Package.ImportPath, nil
+		}
+	}
+
+	cache, err := d.cache()
 	if err != nil {
 		return PackageDoc{}, "", err
 	}
 
 	expected := getPkgVersion(importPath, version)
-	key := getCacheKey(importPath, expected, "")
+	key := d.cacheKey(importPath, expected, "")
 
-	if entry, ok := getValidCacheEntry(cache, key); ok {
+	if entry, ok := getValidCacheEntry(cache, key); ok && !d.expired(entry.cacheMetadata) {
 		if entry.Package != nil {
 			if isRemoteImportPath(importPath) {
 				return *entry.Package, entry.Package.ImportPath, nil
@@ -116,6 +343,9 @@ func (d *Godoc) getOrLoadPkg(importPath, version string) (PackageDoc, string, er
 					entry.GoVersion = runtime.Version()
 					cache.Set(key, entry)
 				}
+				if stdKey != "" && isStdlibEntry(entry.cacheMetadata) {
+					d.setStdlibEntry(stdKey, entry)
+				}
 				return *entry.Package, entry.Package.ImportPath, nil
 			}
 		}
@@ -123,39 +353,52 @@ func (d *Godoc) getOrLoadPkg(importPath, version string) (PackageDoc, string, er
 		// stale entry or missing package payload; fall through to rebuild
 	}
 
-	pkgDoc, _, pkgPath, actualVersion, meta, err := d.buildDoc(importPath, version, false)
+	pkgDoc, symbols, pkgPath, actualVersion, meta, err := d.buildDoc(importPath, version, false)
 	if err != nil {
 		return PackageDoc{}, "", err
 	}
 
 	entry := cacheEntry{
 		Package:       &pkgDoc,
+		Symbols:       symbols,
 		cacheMetadata: meta,
 	}
 
-	keys := uniqKeys(key, getCacheKey(importPath, "", ""))
+	keys := uniqKeys(key, d.cacheKey(importPath, "", ""))
 	if actualVersion != "" {
-		keys = append(keys, getCacheKey(importPath, actualVersion, ""))
+		keys = append(keys, d.cacheKey(importPath, actualVersion, ""))
 	}
 
-	if err := setCacheEntry(cache, entry, keys...); err != nil {
+	if err := d.storeCacheEntry(cache, entry, keys...); err != nil {
 		return PackageDoc{}, "", err
 	}
 
+	if stdKey != "" && isStdlibEntry(meta) {
+		d.setStdlibEntry(stdKey, entry)
+	}
+
 	return pkgDoc, pkgPath, nil
 }
 
 // getOrLoadSymbol gets symbol doc from cache (or loads it if not cached).
 func (d *Godoc) getOrLoadSymbol(importPath, sel, version string) (SymbolDoc, string, error) {
-	cache, err := getCache()
+	var stdKey string
+	if mayBeStdlib(importPath) && !d.cacheDisabled() {
+		stdKey = d.stdlibCacheKey(importPath, sel)
+		if entry, ok := stdlibCache.Get(stdKey); ok && entry.Symbol != nil && !d.expired(entry.cacheMetadata) {
+			return *entry.Symbol, entry.Symbol.ImportPath, nil
+		}
+	}
+
+	cache, err := d.cache()
 	if err != nil {
 		return SymbolDoc{}, "", err
 	}
 
 	expected := getPkgVersion(importPath, version)
-	key := getCacheKey(importPath, expected, sel)
+	key := d.cacheKey(importPath, expected, sel)
 
-	if entry, ok := getValidCacheEntry(cache, key); ok {
+	if entry, ok := getValidCacheEntry(cache, key); ok && !d.expired(entry.cacheMetadata) {
 		if entry.Symbol != nil {
 			if isRemoteImportPath(importPath) {
 				return *entry.Symbol, entry.Symbol.ImportPath, nil
@@ -166,6 +409,9 @@ func (d *Godoc) getOrLoadSymbol(importPath, sel, version string) (SymbolDoc, str
 					entry.GoVersion = runtime.Version()
 					cache.Set(key, entry)
 				}
+				if stdKey != "" && isStdlibEntry(entry.cacheMetadata) {
+					d.setStdlibEntry(stdKey, entry)
+				}
 				return *entry.Symbol, entry.Symbol.ImportPath, nil
 			}
 		}
@@ -173,14 +419,53 @@ func (d *Godoc) getOrLoadSymbol(importPath, sel, version string) (SymbolDoc, str
 		// stale entry or missing symbol payload; fall through to rebuild
 	}
 
-	_, symbols, pkgPath, actualVersion, meta, err := d.buildDoc(importPath, version, true)
+	symbols, pkgPath, actualVersion, meta, err := d.getOrLoadSymbolIndex(cache, importPath, version, expected)
 	if err != nil {
 		return SymbolDoc{}, "", err
 	}
 
 	symDoc, ok := symbols[sel]
 	if !ok {
-		return SymbolDoc{}, pkgPath, fmt.Errorf("selector %q not found in %q", sel, pkgPath)
+		names := slices.Collect(maps.Keys(symbols))
+		match, candidates := matchMethod(sel, symbols)
+		if match == "" && len(candidates) == 0 {
+			match, candidates = matchSymbol(sel, names, d.symbolMatch)
+		}
+
+		switch {
+		case len(candidates) > 0:
+			return SymbolDoc{}, pkgPath, &AmbiguousSymbolError{ImportPath: pkgPath, Symbol: sel, Candidates: candidates}
+		case match == "":
+			return SymbolDoc{}, pkgPath, &SymbolNotFoundError{
+				ImportPath:  pkgPath,
+				Symbol:      sel,
+				Suggestions: suggestSymbols(sel, names),
+			}
+		}
+
+		// The matched symbol is cached under its own name.
+		sel, symDoc = match, symbols[match]
+		key = d.cacheKey(importPath, expected, sel)
+		if stdKey != "" {
+			stdKey = d.stdlibCacheKey(importPath, sel)
+		}
+	}
+
+	// Only the HTML of the requested symbol is rendered, and the cache keeps
+	// exported fields only. The symbols of an index restored from disk have
+	// lost their parsed doc, so theirs is rendered from the text.
+	symDoc.DocHTML = symDoc.HTML()
+	if symDoc.DocHTML == "" && symDoc.DocText != "" {
+		symDoc.DocHTML = renderDocHTML(nil, parseDocText(symDoc.DocText, nil))
+	}
+	if symDoc.TypeDoc != nil && len(symDoc.TypeDoc.Fields) > 0 {
+		td := *symDoc.TypeDoc
+		td.Fields = slices.Clone(td.Fields)
+		for i := range td.Fields {
+			td.Fields[i].DocHTML = td.Fields[i].HTML()
+		}
+
+		symDoc.TypeDoc = &td
 	}
 
 	entry := cacheEntry{
@@ -188,37 +473,135 @@ func (d *Godoc) getOrLoadSymbol(importPath, sel, version string) (SymbolDoc, str
 		cacheMetadata: meta,
 	}
 
-	keys := uniqKeys(key, getCacheKey(importPath, "", sel))
+	keys := uniqKeys(key, d.cacheKey(importPath, "", sel))
 	if actualVersion != "" {
-		keys = append(keys, getCacheKey(importPath, actualVersion, sel))
+		keys = append(keys, d.cacheKey(importPath, actualVersion, sel))
 	}
 
-	if err := setCacheEntry(cache, entry, keys...); err != nil {
+	if err := d.storeCacheEntry(cache, entry, keys...); err != nil {
 		return SymbolDoc{}, "", err
 	}
 
+	if stdKey != "" && isStdlibEntry(meta) {
+		d.setStdlibEntry(stdKey, entry)
+	}
+
 	return symDoc, pkgPath, nil
 }
 
+// symbolIndex returns the symbol index of the package with the given import
+// path, served from the [stdlibCache] and the cache like [Godoc.Load].
+func (d *Godoc) symbolIndex(importPath, version string) (map[string]SymbolDoc, error) {
+	var stdKey string
+	if mayBeStdlib(importPath) && !d.cacheDisabled() {
+		stdKey = d.stdlibCacheKey(importPath, symbolIndexSel)
+		if entry, ok := stdlibCache.Get(stdKey); ok && entry.Symbols != nil && !d.expired(entry.cacheMetadata) {
+			return entry.Symbols, nil
+		}
+	}
+
+	cache, err := d.cache()
+	if err != nil {
+		return nil, err
+	}
+
+	symbols, _, _, meta, err := d.getOrLoadSymbolIndex(cache, importPath, version, getPkgVersion(importPath, version))
+	if err != nil {
+		return nil, err
+	}
+
+	if stdKey != "" && isStdlibEntry(meta) {
+		d.setStdlibEntry(stdKey, cacheEntry{Symbols: symbols, cacheMetadata: meta})
+	}
+
+	return symbols, nil
+}
+
+// getOrLoadSymbolIndex gets the symbol index of the package from the cache,
+// or builds and caches it, so that loading several symbols of a package
+// builds its documentation once. The index is also found in the cached
+// documentation of the package, if built with type information. It returns
+// the index along with the import path, the resolved version, and the
+// metadata of the package.
+func (d *Godoc) getOrLoadSymbolIndex(cache *fastcache.Cache[string, cacheEntry], importPath, version, expected string) (map[string]SymbolDoc, string, string, cacheMetadata, error) {
+	key := d.cacheKey(importPath, expected, symbolIndexSel)
+
+	pkgKey := d.cacheKey(importPath, expected, "")
+	for _, k := range []string{key, pkgKey} {
+		entry, ok := getValidCacheEntry(cache, k)
+		if !ok || entry.Symbols == nil || d.expired(entry.cacheMetadata) {
+			continue
+		}
+
+		// Without type information, the index of a package load misses the
+		// symbols derived from types, such as promoted methods.
+		if k == pkgKey && !entry.Typed {
+			continue
+		}
+
+		if isRemoteImportPath(importPath) || entry.GoVersion == runtime.Version() || (entry.GoVersion == "" && expected == runtime.Version()) {
+			return entry.Symbols, entry.importPath(), "", entry.cacheMetadata, nil
+		}
+
+		// stale entry; fall through to rebuild
+	}
+
+	_, symbols, pkgPath, actualVersion, meta, err := d.buildDoc(importPath, version, true)
+	if err != nil {
+		return nil, "", "", cacheMetadata{}, err
+	}
+
+	entry := cacheEntry{
+		Symbols:       symbols,
+		cacheMetadata: meta,
+	}
+
+	keys := uniqKeys(key, d.cacheKey(importPath, "", symbolIndexSel))
+	if actualVersion != "" {
+		keys = append(keys, d.cacheKey(importPath, actualVersion, symbolIndexSel))
+	}
+
+	if err := d.storeCacheEntry(cache, entry, keys...); err != nil {
+		return nil, "", "", cacheMetadata{}, err
+	}
+
+	return symbols, pkgPath, actualVersion, meta, nil
+}
+
 // buildDoc loads and builds documentation for the specified import path and
-// version.
+// version, along with the symbol index of the package. With needSymbols, the
+// package is loaded with type information, for the richer documentation of
+// symbols.
 func (d *Godoc) buildDoc(importPath, version string, needSymbols bool) (PackageDoc, map[string]SymbolDoc, string, string, cacheMetadata, error) {
-	version = strings.TrimSpace(version)
-	if d.loadPkg == nil {
-		d.loadPkg = d.loadDocPkg
+	version = normalizeVersion(version)
+
+	// Resolve the loaders on each call rather than storing method values, so
+	// that they always observe the current options of d.
+	loadPkg := d.loadPkg
+	if loadPkg == nil {
+		loadPkg = d.loadDocPkg
 	}
 
-	if d.checkDep == nil {
-		d.checkDep = d.checkModuleDep
+	checkDep := d.checkDep
+	if checkDep == nil {
+		checkDep = d.checkModuleDep
 	}
 
 	var symbols, symbols2 map[string]SymbolDoc
 
-	needTypes := needSymbols
-	dpkg, fset, typesInfo, astInfo, pkgPath, module, _, err := d.loadPkg(importPath, "", needTypes)
+	opts := d.buildOptions()
+
+	// Implementers are part of the symbol index, which is built along with
+	// the package documentation.
+	needTypes := needSymbols || d.implements || d.implementers
+	dpkg, fset, typesInfo, astInfo, pkgPath, module, _, err := loadPkg(importPath, "", needTypes)
+	if err == nil && !moduleVersionMatches(module, version) {
+		err = fmt.Errorf("module %s@%s does not satisfy requested version %q", module.Path, module.Version, version)
+	}
+
 	if err == nil {
 		if !needTypes && pkgRequiresTypesInfo(dpkg) {
-			dpkgTyped, fsetTyped, typesInfoTyped, astInfoTyped, pkgPathTyped, moduleTyped, _, typedErr := d.loadPkg(importPath, "", true)
+			dpkgTyped, fsetTyped, typesInfoTyped, astInfoTyped, pkgPathTyped, moduleTyped, _, typedErr := loadPkg(importPath, "", true)
 			if typedErr == nil {
 				dpkg = dpkgTyped
 				fset = fsetTyped
@@ -232,11 +615,19 @@ func (d *Godoc) buildDoc(importPath, version string, needSymbols bool) (PackageD
 			}
 		}
 
-		pkgDoc := toPkgDoc(dpkg, fset, typesInfo, astInfo, pkgPath)
-		if needSymbols {
-			symbols = buildSymbolIndex(dpkg, fset, typesInfo, astInfo, pkgPath)
+		// The symbol index is cached with the package, so that later symbol
+		// loads are served without loading the package again.
+		pkgDoc := toPkgDoc(dpkg, fset, typesInfo, astInfo, pkgPath, opts)
+		symbols = buildSymbolIndex(dpkg, fset, typesInfo, astInfo, pkgPath, opts)
+		if d.implementers {
+			addImplementers(symbols, typesInfo, pkgPath)
+		}
+
+		if err := d.mergePlatformFields(importPath, "", typesInfo != nil, &pkgDoc, symbols); err != nil {
+			return PackageDoc{}, nil, "", "", cacheMetadata{}, err
 		}
 		meta := deriveCacheMetadata(module, version)
+		meta.Typed = typesInfo != nil
 
 		if isRemoteImportPath(importPath) {
 			if meta.ModuleVersion == "" && version != "" {
@@ -248,10 +639,22 @@ func (d *Godoc) buildDoc(importPath, version string, needSymbols bool) (PackageD
 			}
 		}
 
+		setModuleVersion(&pkgDoc, symbols, meta.ModuleVersion)
+
 		return pkgDoc, symbols, pkgPath, version, meta, nil
 	}
 
-	modDir, cleanup, err2 := d.checkDep(importPath, version)
+	// Packages of a source file system are never fetched.
+	if d.source != nil {
+		return PackageDoc{}, nil, "", "", cacheMetadata{}, err
+	}
+
+	// Fetching the module is pointless once canceled.
+	if ctxErr := d.context().Err(); ctxErr != nil {
+		return PackageDoc{}, nil, "", "", cacheMetadata{}, ctxErr
+	}
+
+	modDir, cleanup, err2 := checkDep(importPath, version)
 	if err2 != nil {
 		return PackageDoc{}, nil, "", "", cacheMetadata{}, fmt.Errorf("local load failed (%w) and module dependency setup failed (%w)", err, err2)
 	}
@@ -260,22 +663,40 @@ func (d *Godoc) buildDoc(importPath, version string, needSymbols bool) (PackageD
 		defer cleanup()
 	}
 
-	dpkg2, fset2, typesInfo2, astInfo2, pkgPath2, module2, _, err3 := d.loadPkg(importPath, modDir, true)
+	if ctxErr := d.context().Err(); ctxErr != nil {
+		return PackageDoc{}, nil, "", "", cacheMetadata{}, ctxErr
+	}
+
+	dpkg2, fset2, typesInfo2, astInfo2, pkgPath2, module2, _, err3 := loadPkg(importPath, modDir, true)
 	if err3 != nil {
 		return PackageDoc{}, nil, "", "", cacheMetadata{}, fmt.Errorf("load with module dependency failed: %w", err3)
 	}
 
-	pkgDoc := toPkgDoc(dpkg2, fset2, typesInfo2, astInfo2, pkgPath2)
-	if needSymbols {
-		symbols2 = buildSymbolIndex(dpkg2, fset2, typesInfo2, astInfo2, pkgPath2)
+	pkgDoc := toPkgDoc(dpkg2, fset2, typesInfo2, astInfo2, pkgPath2, opts)
+	symbols2 = buildSymbolIndex(dpkg2, fset2, typesInfo2, astInfo2, pkgPath2, opts)
+	if d.implementers {
+		addImplementers(symbols2, typesInfo2, pkgPath2)
+	}
+
+	if err := d.mergePlatformFields(importPath, modDir, typesInfo2 != nil, &pkgDoc, symbols2); err != nil {
+		return PackageDoc{}, nil, "", "", cacheMetadata{}, err
+	}
+
+	var actualVersion string
+	if module2 != nil && !module2.Main {
+		actualVersion = module2.Version
+	}
+
+	if actualVersion == "" {
+		actualVersion = getVersionFromMod(modDir, importPath)
 	}
 
-	actualVersion := getVersionFromMod(modDir, importPath)
 	if actualVersion == "" {
 		actualVersion = version
 	}
 
 	meta := deriveCacheMetadata(module2, actualVersion)
+	meta.Typed = typesInfo2 != nil
 	if isRemoteImportPath(importPath) {
 		if meta.ModuleVersion == "" && actualVersion != "" {
 			meta.ModuleVersion = actualVersion
@@ -286,45 +707,148 @@ func (d *Godoc) buildDoc(importPath, version string, needSymbols bool) (PackageD
 		}
 	}
 
+	setModuleVersion(&pkgDoc, symbols2, meta.ModuleVersion)
+
 	return pkgDoc, symbols2, pkgPath2, actualVersion, meta, nil
 }
 
+// setModuleVersion records the resolved version of the module providing a
+// package in its documentation and in that of its symbols.
+func setModuleVersion(pkgDoc *PackageDoc, symbols map[string]SymbolDoc, version string) {
+	pkgDoc.ModuleVersion = version
+	for key, sym := range symbols {
+		sym.ModuleVersion = version
+		symbols[key] = sym
+	}
+}
+
 // loadDocPkg loads documentation for a Go package.
 func (d *Godoc) loadDocPkg(importPath, dir string, needTypes bool) (*doc.Package, *token.FileSet, *types.Info, *packageAST, string, *packages.Module, string, error) {
-	ctx := d.context()
-	cfg := &packages.Config{
-		Mode: packages.NeedName |
-			packages.NeedFiles |
-			packages.NeedSyntax |
-			packages.NeedCompiledGoFiles |
-			packages.NeedModule,
-		Env:     append(os.Environ(), "GOWORK=off"),
-		Dir:     dir, // empty = current working directory/module
-		Context: ctx,
+	release, err := d.acquireLoadSlot()
+	if err != nil {
+		return nil, nil, nil, nil, "", nil, "", err
 	}
+	defer release()
 
-	if needTypes {
-		cfg.Mode |= packages.NeedTypes | packages.NeedTypesInfo
+	// Test files only contribute examples and test function names, which
+	// are left out of synopses.
+	wantTests := !d.synopsisOnly || d.testFiles
+
+	var (
+		p     *packages.Package
+		tests []*ast.File
+	)
+
+	if d.source != nil {
+		p, tests, err = d.loadSourcePkg(importPath, needTypes, wantTests)
+		if err != nil {
+			return nil, nil, nil, nil, "", nil, "", err
+		}
+	} else {
+		p, dir, err = d.loadPackage(importPath, dir, needTypes)
+		if err != nil {
+			return nil, nil, nil, nil, "", nil, "", err
+		}
+
+		if len(p.GoFiles) > 0 && wantTests {
+			cfg := d.Config()
+			tests = testFiles(p.Fset, filepath.Dir(p.GoFiles[0]), cfg.GOOS, cfg.GOARCH, cfg.BuildTags)
+		}
 	}
 
-	// load from GOROOT
-	if dir == "." && importPath != "." && !strings.Contains(importPath, "/") {
-		cfg.Dir = ""
+	var files []*ast.File
+	for i, f := range p.Syntax {
+		fn := p.GoFiles[i]
+		if strings.HasSuffix(fn, "_test.go") {
+			continue
+		}
+
+		files = append(files, f)
 	}
 
-	if d.goos != "" {
-		cfg.Env = append(cfg.Env, "GOOS="+d.goos)
+	// go/doc consumes the comments of the files, so collect the build
+	// constraints first.
+	constraints := collectBuildConstraints(files)
+
+	if d.testFiles {
+		// Only tests of the package itself, not of an external _test
+		// package, declare helpers of the package.
+		for _, f := range tests {
+			if f.Name.Name == p.Name {
+				files = append(files, testHelperFile(f))
+			}
+		}
 	}
 
-	if d.goarch != "" {
-		cfg.Env = append(cfg.Env, "GOARCH="+d.goarch)
+	if d.filePattern != "" {
+		matched := files[:0]
+		for _, f := range files {
+			ok, err := matchFilePattern(p.Fset.File(f.Pos()).Name(), d.filePattern)
+			if err != nil {
+				return nil, nil, nil, nil, "", nil, "", err
+			}
+
+			if ok {
+				matched = append(matched, f)
+			}
+		}
+
+		if len(matched) == 0 {
+			return nil, nil, nil, nil, "", nil, "", fmt.Errorf("no files matching %q found for %q", d.filePattern, importPath)
+		}
+
+		files = matched
 	}
 
-	pkgs, err := packages.Load(cfg, importPath)
+	var docMode doc.Mode
+	if d.unexported {
+		docMode |= doc.AllDecls
+	}
+
+	var consts *types.Info
+	if p.TypesInfo == nil {
+		consts = evalConsts(p.Fset, files, p.PkgPath)
+	}
+
+	dpkg, err := newDocPackage(p.Fset, files, tests, p.PkgPath, p.Name, docMode)
 	if err != nil {
 		return nil, nil, nil, nil, "", nil, "", err
 	}
 
+	astInfo := buildPkgAST(p, files)
+	if astInfo != nil {
+		astInfo.testFiles = tests
+		astInfo.buildConstraints = constraints
+		astInfo.consts = consts
+	}
+
+	return dpkg, p.Fset, p.TypesInfo, astInfo, p.PkgPath, p.Module, dir, nil
+}
+
+// loadPackage loads the syntax of importPath from dir with the packages
+// loader, along with its type information if needTypes is set. It also
+// returns the directory the package was loaded from.
+func (d *Godoc) loadPackage(importPath, dir string, needTypes bool) (*packages.Package, string, error) {
+	mode := packages.NeedName |
+		packages.NeedFiles |
+		packages.NeedSyntax |
+		packages.NeedCompiledGoFiles |
+		packages.NeedModule
+
+	if needTypes {
+		mode |= packages.NeedTypes | packages.NeedTypesInfo
+	}
+
+	cfg, err := d.packagesConfig(importPath, dir, mode)
+	if err != nil {
+		return nil, "", err
+	}
+
+	pkgs, err := packages.Load(cfg, importPath)
+	if err != nil {
+		return nil, "", err
+	}
+
 	var hasErrors bool
 	for _, pkg := range pkgs {
 		if len(pkg.Errors) > 0 {
@@ -334,7 +858,7 @@ func (d *Godoc) loadDocPkg(importPath, dir string, needTypes bool) (*doc.Package
 	}
 
 	if hasErrors {
-		return nil, nil, nil, nil, "", nil, "", fmt.Errorf("build/load errors for %q", importPath)
+		return nil, "", fmt.Errorf("build/load errors for %q", importPath)
 	}
 
 	var p *packages.Package
@@ -346,27 +870,123 @@ func (d *Godoc) loadDocPkg(importPath, dir string, needTypes bool) (*doc.Package
 	}
 
 	if p == nil {
-		return nil, nil, nil, nil, "", nil, "", fmt.Errorf("no syntax found for %q", importPath)
+		return nil, "", fmt.Errorf("no syntax found for %q", importPath)
 	}
 
-	var files []*ast.File
-	for i, f := range p.Syntax {
-		fn := p.GoFiles[i]
-		if strings.HasSuffix(fn, "_test.go") {
-			continue
+	return p, cfg.Dir, nil
+}
+
+// newDocPackage computes the documentation of the package declared in
+// files, with the examples of the given test files.
+//
+// [doc.NewFromFiles] only uses test files for their examples, so when files
+// include test files, as with [WithTestFiles], the documentation is computed
+// with [doc.New] instead, and the examples are attached to it afterwards.
+func newDocPackage(fset *token.FileSet, files, tests []*ast.File, importPath, name string, mode doc.Mode) (*doc.Package, error) {
+	var regular []*ast.File
+	pkgFiles := make(map[string]*ast.File, len(files))
+	for _, f := range files {
+		filename := fset.File(f.Pos()).Name()
+		if !strings.HasSuffix(filename, "_test.go") {
+			regular = append(regular, f)
 		}
 
-		files = append(files, f)
+		pkgFiles[filename] = f
 	}
 
-	dpkg, err := doc.NewFromFiles(p.Fset, files, p.PkgPath)
+	withExamples, err := doc.NewFromFiles(fset, append(regular, tests...), importPath, mode)
+	if err != nil || len(regular) == len(files) {
+		return withExamples, err
+	}
+
+	// ast.Package is deprecated, but it is the input of doc.New.
+	dpkg := doc.New(&ast.Package{Name: name, Files: pkgFiles}, importPath, mode)
+	copyExamples(dpkg, withExamples)
+
+	return dpkg, nil
+}
+
+// packagesConfig returns the [packages.Config] used to load importPath from
+// dir with the given mode.
+//
+// The packages loader always starts the go command found in the PATH of the
+// process, so when a go binary is configured, that command is made to switch
+// to it: GOTOOLCHAIN names the version of the binary, which is found first in
+// the PATH of the command through a link named after that version. Should the
+// link be unavailable, the command switches to the same toolchain version.
+func (d *Godoc) packagesConfig(importPath, dir string, mode packages.LoadMode) (*packages.Config, error) {
+	cfg := &packages.Config{
+		Mode:    mode,
+		Env:     append(os.Environ(), "GOWORK=off"),
+		Dir:     dir, // empty = current working directory/module
+		Context: d.context(),
+	}
+
+	cfg.Env = append(cfg.Env, d.goEnvList()...)
+
+	// load from GOROOT
+	if dir == "." && importPath != "." && !strings.Contains(importPath, "/") {
+		cfg.Dir = ""
+	}
+
+	if d.goos != "" {
+		cfg.Env = append(cfg.Env, "GOOS="+d.goos)
+	}
+
+	if d.goarch != "" {
+		cfg.Env = append(cfg.Env, "GOARCH="+d.goarch)
+	}
+
+	cfg.BuildFlags = d.buildFlags()
+
+	if d.goBinary != "" {
+		version, err := d.goBinaryVersion()
+		if err != nil {
+			return nil, err
+		}
+
+		toolchain := version
+		if binDir, err := d.goBinaryPathDir(version); err == nil {
+			cfg.Env = append(cfg.Env, "PATH="+binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+			toolchain += "+path"
+		}
+
+		cfg.Env = append(cfg.Env, "GOTOOLCHAIN="+toolchain)
+	}
+
+	return cfg, nil
+}
+
+// loadPkgName loads only the package clause name of importPath from dir,
+// along with the module providing it.
+func (d *Godoc) loadPkgName(importPath, dir string) (string, *packages.Module, error) {
+	if d.source != nil {
+		name, err := d.loadSourcePkgName(importPath)
+
+		return name, nil, err
+	}
+
+	cfg, err := d.packagesConfig(importPath, dir, packages.NeedName|packages.NeedModule)
 	if err != nil {
-		return nil, nil, nil, nil, "", nil, "", err
+		return "", nil, err
 	}
 
-	astInfo := buildPkgAST(p, files)
+	pkgs, err := packages.Load(cfg, importPath)
+	if err != nil {
+		return "", nil, err
+	}
 
-	return dpkg, p.Fset, p.TypesInfo, astInfo, p.PkgPath, p.Module, cfg.Dir, nil
+	for _, p := range pkgs {
+		if len(p.Errors) > 0 {
+			return "", nil, fmt.Errorf("build/load errors for %q", importPath)
+		}
+
+		if p.Name != "" {
+			return p.Name, p.Module, nil
+		}
+	}
+
+	return "", nil, fmt.Errorf("no package found for %q", importPath)
 }
 
 // checkModuleDep ensures the target import is available for loading.
@@ -374,23 +994,17 @@ func (d *Godoc) loadDocPkg(importPath, dir string, needTypes bool) (*doc.Package
 // If the importPath is already in the go.mod of the specified dir, uses that
 // dir. Otherwise, creates a temp module and adds the import there.
 func (d *Godoc) checkModuleDep(importPath, version string) (string, func(), error) {
-	modFilePath := filepath.Join(d.workdir, "go.mod")
-	data, err := os.ReadFile(modFilePath)
-	if err == nil {
-		f, err := modfile.Parse(modFilePath, data, nil)
-		if err == nil {
-			for _, r := range f.Require {
-				if r.Mod.Path == importPath {
-					if version == "" || r.Mod.Version == version {
-						return d.workdir, nil, nil
-					}
-					// Version mismatch, fallback to temp
-				}
-			}
-		}
-		// go.mod exists but importPath not present/parse error, fallback to temp.
+	ctx := d.context()
+	if err := ctx.Err(); err != nil {
+		return "", nil, err
 	}
 
+	modPath, modVersion := requiredModule(d.workdir, importPath)
+	if modPath != "" && (version == "" || modVersion == version) {
+		return d.workdir, nil, nil
+	}
+	// importPath not required/version mismatch/parse error, fallback to temp.
+
 	targetKey := importPath
 	if version != "" {
 		targetKey = importPath + "@" + version
@@ -407,12 +1021,17 @@ func (d *Godoc) checkModuleDep(importPath, version string) (string, func(), erro
 				target = targetKey
 			}
 
-			if err := d.runGo(d.workdir, "list", target); err == nil {
+			if err := d.goList(d.workdir, target); err == nil {
 				cache.Store(targetKey, true)
 
 				return d.workdir, nil, nil
 			}
 
+			// A canceled listing says nothing about the workdir.
+			if err := ctx.Err(); err != nil {
+				return "", nil, err
+			}
+
 			cache.Store(targetKey, false)
 		}
 	} else {
@@ -421,11 +1040,19 @@ func (d *Godoc) checkModuleDep(importPath, version string) (string, func(), erro
 			target = targetKey
 		}
 
-		if err := d.runGo(d.workdir, "list", target); err == nil {
+		if err := d.goList(d.workdir, target); err == nil {
 			return d.workdir, nil, nil
 		}
 	}
 
+	if d.modMode == ModVendor {
+		return "", nil, fmt.Errorf("%q is not provided by the module in %q or its vendor directory", targetKey, d.workdir)
+	}
+
+	if err := ctx.Err(); err != nil {
+		return "", nil, err
+	}
+
 	tempDir, err := os.MkdirTemp("", "godoc-*")
 	if err != nil {
 		return "", nil, err
@@ -439,12 +1066,32 @@ func (d *Godoc) checkModuleDep(importPath, version string) (string, func(), erro
 		return "", nil, fmt.Errorf("go mod init failed: %w", err)
 	}
 
+	if err := ctx.Err(); err != nil {
+		cleanup()
+
+		return "", nil, err
+	}
+
 	target := importPath
 	if version != "" {
 		target = importPath + "@" + version
 	}
 
-	if err := d.runGo(tempDir, "get", target); err != nil {
+	// "upgrade" and "patch" are resolved relative to the version currently
+	// required by the workdir, so seed the temp module with that requirement
+	// and query the module itself.
+	if isRelativeVersionQuery(version) && modPath != "" && modVersion != "" {
+		current := modPath + "@" + modVersion
+		if err := d.goGet(tempDir, current); err != nil {
+			cleanup()
+
+			return "", nil, fmt.Errorf("go get %q failed: %w", current, err)
+		}
+
+		target = modPath + "@" + version
+	}
+
+	if err := d.goGet(tempDir, target); err != nil {
 		cleanup()
 
 		return "", nil, fmt.Errorf("go get %q failed: %w", target, err)
@@ -1,20 +1,28 @@
 package godoc
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"go/ast"
 	"go/doc"
 	"go/doc/comment"
+	"go/parser"
 	"go/token"
 	"go/types"
 	"io/fs"
 	"os"
 	"path/filepath"
+	"reflect"
 	"runtime"
+	"slices"
+	"sort"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"golang.org/x/tools/go/packages"
 )
@@ -30,6 +38,51 @@ func TestSymbolDocHTMLLazyGeneration(t *testing.T) {
 	}
 }
 
+func TestSymbolDocHTMLMemoized(t *testing.T) {
+	renders := 0
+	printer := &comment.Printer{
+		HeadingLevel: 3,
+		DocLinkURL: func(link *comment.DocLink) string {
+			renders++
+			return "#" + link.Name
+		},
+	}
+
+	parser := &comment.Parser{LookupSym: func(recv, name string) bool { return name == "T" }}
+	sym := makeSymbolDoc("example.com/p", &doc.Package{Name: "p"}, parser, printer, "func", "F", "", "", "F returns a [T].\n", "", nil, nil, nil, nil)
+	cp := sym
+
+	html := sym.HTML()
+	if !strings.Contains(html, `<a href="#T">T</a>`) {
+		t.Fatalf("expected rendered doc link, got %q", html)
+	}
+
+	if got := cp.HTML(); got != html || sym.HTML() != html {
+		t.Errorf("expected memoized HTML %q, got %q", html, got)
+	}
+
+	if renders != 1 {
+		t.Errorf("expected HTML to be rendered once, got %d renders", renders)
+	}
+}
+
+func TestSymbolDocHTMLConcurrent(t *testing.T) {
+	sym := makeSymbolDoc("example.com/p", &doc.Package{Name: "p"}, nil, nil, "func", "F", "", "", "F does things.\n", "", nil, nil, nil, nil)
+	want := renderDocHTML(nil, sym.docParsed)
+
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Go(func() {
+			// Each goroutine renders through its own copy.
+			if got := sym.HTML(); got != want {
+				t.Errorf("expected %q, got %q", want, got)
+			}
+		})
+	}
+
+	wg.Wait()
+}
+
 func TestGodocContextDefaults(t *testing.T) {
 	t.Run("nil option resets to background", func(t *testing.T) {
 		g := New(Option(func(g *Godoc) { g.ctx = nil }))
@@ -56,7 +109,7 @@ func TestGodocLoadFillsImportPathFromCache(t *testing.T) {
 	resetCacheGlobals()
 	t.Cleanup(resetCacheGlobals)
 
-	cache, err := getCache()
+	cache, err := getCache(cacheMaxEntries)
 	if err != nil {
 		t.Fatalf("unexpected cache error: %v", err)
 	}
@@ -104,7 +157,7 @@ func TestPersistentCacheHitSkipsLoad(t *testing.T) {
 		t.Fatalf("initial load failed: %v", err)
 	}
 
-	cache1, err := getCache()
+	cache1, err := getCache(cacheMaxEntries)
 	if err != nil {
 		t.Fatalf("failed to access cache after initial load: %v", err)
 	}
@@ -122,7 +175,7 @@ func TestPersistentCacheHitSkipsLoad(t *testing.T) {
 
 	g2 := New()
 	called := false
-	cache, err := getCache()
+	cache, err := getCache(cacheMaxEntries)
 	if err != nil {
 		t.Fatalf("failed to reload cache: %v", err)
 	}
@@ -152,6 +205,123 @@ func TestPersistentCacheHitSkipsLoad(t *testing.T) {
 	}
 }
 
+func TestPersistentSymbolIndexSkipsLoad(t *testing.T) {
+	resetCacheGlobals()
+	t.Cleanup(resetCacheGlobals)
+
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	g := New()
+	if _, err := g.Load("fmt", "Println", ""); err != nil {
+		t.Fatalf("initial load failed: %v", err)
+	}
+
+	// Reload the cache from disk, where symbols lose their parsed doc.
+	resetCacheGlobals()
+
+	g2 := New()
+	g2.loadPkg = func(string, string, bool) (*doc.Package, *token.FileSet, *types.Info, *packageAST, string, *packages.Module, string, error) {
+		return nil, nil, nil, nil, "", nil, "", fmt.Errorf("loadPkg should not be called when the symbol index is cached")
+	}
+
+	res, err := g2.Load("fmt", "Printf", "")
+	if err != nil {
+		t.Fatalf("expected Printf to be served from the cached symbol index, got error: %v", err)
+	}
+
+	sym := res.(SymbolDoc)
+	if sym.ImportPath != "fmt" || sym.Name != "Printf" || sym.Decl == "" {
+		t.Fatalf("unexpected symbol from the cached index: %+v", sym)
+	}
+
+	if !strings.Contains(sym.HTML(), "<p>") {
+		t.Fatalf("expected HTML rendered from the doc text, got %q", sym.HTML())
+	}
+
+	if _, err := g2.Load("fmt", "NoSuchSymbol", ""); !errors.Is(err, ErrSymbolNotFound) {
+		t.Fatalf("expected a missing symbol to be reported from the cached index, got %v", err)
+	}
+}
+
+func TestPackageCacheServesSymbols(t *testing.T) {
+	resetCacheGlobals()
+	t.Cleanup(resetCacheGlobals)
+
+	// The package is loaded with type information for its embedded
+	// interfaces, so its symbol index is complete.
+	g := New(WithCacheMode(CacheMemory))
+	if _, err := g.Load("io", "", ""); err != nil {
+		t.Fatalf("package load failed: %v", err)
+	}
+
+	loadPkg := g.loadDocPkg
+	g.loadPkg = func(string, string, bool) (*doc.Package, *token.FileSet, *types.Info, *packageAST, string, *packages.Module, string, error) {
+		return nil, nil, nil, nil, "", nil, "", fmt.Errorf("loadPkg should not be called when the package is cached")
+	}
+
+	res, err := g.Load("io", "Reader", "")
+	if err != nil {
+		t.Fatalf("expected Reader to be served from the cached package, got error: %v", err)
+	}
+
+	if sym := res.(SymbolDoc); sym.Name != "Reader" || sym.Kind != "type" || len(sym.Methods) != 1 {
+		t.Fatalf("unexpected symbol from the cached package: %+v", sym)
+	}
+
+	if _, err := g.Load("io", "ReadWriter.Write", ""); err != nil {
+		t.Fatalf("expected the embedded interface method to be served from the cached package, got error: %v", err)
+	}
+
+	// An index built without type information is not served for symbols.
+	g.loadPkg = loadPkg
+	if _, err := g.Load("fmt", "", ""); err != nil {
+		t.Fatalf("package load failed: %v", err)
+	}
+
+	var typed bool
+	g.loadPkg = func(importPath, dir string, needTypes bool) (*doc.Package, *token.FileSet, *types.Info, *packageAST, string, *packages.Module, string, error) {
+		typed = needTypes
+		return loadPkg(importPath, dir, needTypes)
+	}
+
+	if _, err := g.Load("fmt", "Stringer", ""); err != nil || !typed {
+		t.Fatalf("expected Stringer to be loaded with type information, got typed=%v, error: %v", typed, err)
+	}
+}
+
+func TestListSymbolsAndSearchUseCachedIndex(t *testing.T) {
+	resetCacheGlobals()
+	t.Cleanup(resetCacheGlobals)
+
+	g := New(WithCacheMode(CacheMemory))
+	refs, err := g.ListSymbols("fmt", "")
+	if err != nil {
+		t.Fatalf("ListSymbols failed: %v", err)
+	}
+
+	g.loadPkg = func(string, string, bool) (*doc.Package, *token.FileSet, *types.Info, *packageAST, string, *packages.Module, string, error) {
+		return nil, nil, nil, nil, "", nil, "", fmt.Errorf("loadPkg should not be called when the index is cached")
+	}
+
+	cached, err := g.ListSymbols("fmt", "")
+	if err != nil {
+		t.Fatalf("expected ListSymbols to be served from the cache, got error: %v", err)
+	}
+
+	if len(cached) != len(refs) {
+		t.Fatalf("expected %d cached symbols, got %d", len(refs), len(cached))
+	}
+
+	found, err := g.Search("fmt", "/^Stringer$/", "")
+	if err != nil {
+		t.Fatalf("expected Search to be served from the cache, got error: %v", err)
+	}
+
+	if len(found) != 1 || found[0].Name != "Stringer" {
+		t.Fatalf("unexpected search results from the cache: %+v", found)
+	}
+}
+
 func TestGetOrLoadPkgPropagatesSetCacheError(t *testing.T) {
 	root := t.TempDir()
 	cacheHome := filepath.Join(root, "cachehome")
@@ -159,7 +329,7 @@ func TestGetOrLoadPkgPropagatesSetCacheError(t *testing.T) {
 	resetCacheGlobals()
 	t.Cleanup(resetCacheGlobals)
 
-	if _, err := getCache(); err != nil {
+	if _, err := getCache(cacheMaxEntries); err != nil {
 		t.Fatalf("unexpected cache init error: %v", err)
 	}
 
@@ -196,7 +366,7 @@ func TestGetOrLoadSymbolSetCacheError(t *testing.T) {
 	resetCacheGlobals()
 	t.Cleanup(resetCacheGlobals)
 
-	if _, err := getCache(); err != nil {
+	if _, err := getCache(cacheMaxEntries); err != nil {
 		t.Fatalf("unexpected cache init error: %v", err)
 	}
 
@@ -268,11 +438,11 @@ func TestLoadDocPkgInvalidImportPath(t *testing.T) {
 func TestFieldAndExprHelpers(t *testing.T) {
 	fset := token.NewFileSet()
 	field := &ast.Field{Type: ast.NewIdent("int")}
-	if got := fieldTypeString(nil, nil, nil); got != "" {
+	if got := fieldTypeString(nil, nil, nil, buildOptions{}); got != "" {
 		t.Fatalf("expected empty string for nil field, got %q", got)
 	}
 
-	if got := fieldTypeString(field, nil, fset); got != "int" {
+	if got := fieldTypeString(field, nil, fset, buildOptions{}); got != "int" {
 		t.Fatalf("expected int type, got %q", got)
 	}
 
@@ -303,7 +473,7 @@ func TestExtractArgsFallbackPaths(t *testing.T) {
 		},
 	}
 
-	args := extractArgs(decl, fset, nil)
+	args := extractArgs(decl, fset, nil, buildOptions{})
 	if len(args) != 2 {
 		t.Fatalf("expected 2 args, got %d", len(args))
 	}
@@ -334,7 +504,7 @@ func TestExtractArgsWithTypesInfo(t *testing.T) {
 		ell.Elt:                       {Type: types.Typ[types.String]},
 	}}
 
-	args := extractArgs(decl, fset, typesInfo)
+	args := extractArgs(decl, fset, typesInfo, buildOptions{})
 	if len(args) != 2 {
 		t.Fatalf("expected 2 args, got %d", len(args))
 	}
@@ -359,7 +529,7 @@ func TestExtractResultsFallbackPaths(t *testing.T) {
 		},
 	}
 
-	results := extractResults(decl, fset, nil)
+	results := extractResults(decl, fset, nil, buildOptions{})
 	if len(results) != 2 {
 		t.Fatalf("expected 2 results, got %d", len(results))
 	}
@@ -389,7 +559,7 @@ func TestExtractResultsWithTypesInfo(t *testing.T) {
 		decl.Type.Results.List[1].Type: {Type: types.Universe.Lookup("error").Type()},
 	}}
 
-	results := extractResults(decl, fset, typesInfo)
+	results := extractResults(decl, fset, typesInfo, buildOptions{})
 	if len(results) != 2 {
 		t.Fatalf("expected 2 results, got %d", len(results))
 	}
@@ -410,7 +580,7 @@ func TestArgsFromSignature(t *testing.T) {
 	)
 
 	sig := types.NewSignatureType(nil, nil, nil, params, nil, true)
-	args := argsFromSignature(sig, []string{"hint", ""})
+	args := argsFromSignature(sig, []string{"hint", ""}, buildOptions{})
 
 	if len(args) != 2 {
 		t.Fatalf("expected 2 args, got %d", len(args))
@@ -429,7 +599,7 @@ func TestArgsFromSignatureNonSliceVariadic(t *testing.T) {
 	params := types.NewTuple(types.NewVar(token.NoPos, nil, "", types.Typ[types.String]))
 	sig := types.NewSignatureType(nil, nil, nil, params, nil, true)
 
-	args := argsFromSignature(sig, nil)
+	args := argsFromSignature(sig, nil, buildOptions{})
 	if len(args) != 1 {
 		t.Fatalf("expected single arg, got %d", len(args))
 	}
@@ -446,7 +616,7 @@ func TestResultsFromSignature(t *testing.T) {
 	)
 
 	sig := types.NewSignatureType(nil, nil, nil, nil, results, false)
-	out := resultsFromSignature(sig, []string{"value", ""})
+	out := resultsFromSignature(sig, []string{"value", ""}, buildOptions{})
 
 	if len(out) != 2 {
 		t.Fatalf("expected 2 results, got %d", len(out))
@@ -479,6 +649,31 @@ func TestGetVersionFromMod(t *testing.T) {
 	if version := getVersionFromMod(tempDir, "example.com/bar"); version != "" {
 		t.Fatalf("expected empty version for missing dependency, got %q", version)
 	}
+
+	if version := getVersionFromMod(tempDir, "example.com/foo/sub"); version != "v1.2.3" {
+		t.Fatalf("expected package in module to resolve to v1.2.3, got %q", version)
+	}
+
+	if version := getVersionFromMod(tempDir, "example.com/foobar"); version != "" {
+		t.Fatalf("expected empty version for sibling path, got %q", version)
+	}
+}
+
+func TestNormalizeVersion(t *testing.T) {
+	tests := map[string]string{
+		"":         "",
+		"latest":   "latest",
+		"@latest":  "latest",
+		" @patch ": "patch",
+		"@v1.2.3":  "v1.2.3",
+		"@upgrade": "upgrade",
+	}
+
+	for in, want := range tests {
+		if got := normalizeVersion(in); got != want {
+			t.Errorf("normalizeVersion(%q) = %q, want %q", in, got, want)
+		}
+	}
 }
 
 func TestGetVersionFromModParseError(t *testing.T) {
@@ -499,7 +694,7 @@ func TestGetOrLoadPkgAddsActualVersionKey(t *testing.T) {
 	t.Cleanup(resetCacheGlobals)
 
 	g := New()
-	cache, err := getCache()
+	cache, err := getCache(cacheMaxEntries)
 	if err != nil {
 		t.Fatalf("unexpected cache init error: %v", err)
 	}
@@ -525,7 +720,7 @@ func TestGetOrLoadSymbolAddsActualVersionKey(t *testing.T) {
 	t.Cleanup(resetCacheGlobals)
 
 	g := New()
-	cache, err := getCache()
+	cache, err := getCache(cacheMaxEntries)
 	if err != nil {
 		t.Fatalf("unexpected cache init error: %v", err)
 	}
@@ -691,6 +886,134 @@ func TestBuildDocFallbackLoadError(t *testing.T) {
 	}
 }
 
+func TestBuildDocVersionQueryResolvesConcreteVersion(t *testing.T) {
+	g := New()
+	d := &g
+	d.workdir = t.TempDir()
+
+	modDir := t.TempDir()
+	loadCalls := 0
+
+	d.loadPkg = func(importPath, dir string, needTypes bool) (*doc.Package, *token.FileSet, *types.Info, *packageAST, string, *packages.Module, string, error) {
+		loadCalls++
+		if loadCalls == 1 {
+			// Loaded from the workdir at the currently required version,
+			// which does not satisfy the query.
+			return &doc.Package{Name: "foo"}, token.NewFileSet(), &types.Info{}, nil, importPath, &packages.Module{Path: "example.com/foo", Version: "v1.0.0"}, "", nil
+		}
+
+		if dir != modDir {
+			t.Fatalf("expected query load to use mod dir %q, got %q", modDir, dir)
+		}
+
+		return &doc.Package{Name: "foo"}, token.NewFileSet(), &types.Info{}, nil, importPath, &packages.Module{Path: "example.com/foo", Version: "v1.0.5"}, "", nil
+	}
+
+	d.checkDep = func(importPath, version string) (string, func(), error) {
+		if version != "patch" {
+			t.Fatalf("expected normalized patch query, got %q", version)
+		}
+
+		return modDir, func() {}, nil
+	}
+
+	pkgDoc, _, _, actualVersion, meta, err := d.buildDoc("example.com/foo", "@patch", false)
+	if err != nil {
+		t.Fatalf("unexpected build error: %v", err)
+	}
+
+	if loadCalls != 2 {
+		t.Fatalf("expected fallback load for version query, got %d loads", loadCalls)
+	}
+
+	if actualVersion != "v1.0.5" || meta.ModuleVersion != "v1.0.5" {
+		t.Fatalf("expected resolved version v1.0.5, got %q (meta %q)", actualVersion, meta.ModuleVersion)
+	}
+
+	if pkgDoc.ModuleVersion != "v1.0.5" {
+		t.Fatalf("expected resolved version in package docs, got %q", pkgDoc.ModuleVersion)
+	}
+}
+
+func TestModuleVersion(t *testing.T) {
+	const src = "package foo\n\n// F does nothing.\nfunc F() {}\n"
+
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "foo.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+
+	g := New(WithCacheMode(CacheDisabled))
+	g.loadPkg = func(importPath, dir string, needTypes bool) (*doc.Package, *token.FileSet, *types.Info, *packageAST, string, *packages.Module, string, error) {
+		dpkg, err := doc.NewFromFiles(fset, []*ast.File{f}, importPath)
+
+		return dpkg, fset, nil, nil, importPath, &packages.Module{Path: "example.com/foo", Version: "v1.2.3"}, "", err
+	}
+	g.checkDep = func(importPath, version string) (string, func(), error) {
+		return t.TempDir(), nil, nil
+	}
+
+	res, err := g.Load("example.com/foo", "", "latest")
+	if err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+
+	if got := res.(PackageDoc).ModuleVersion; got != "v1.2.3" {
+		t.Fatalf("expected resolved module version in package docs, got %q", got)
+	}
+
+	res, err = g.Load("example.com/foo", "F", "latest")
+	if err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+
+	if got := res.(SymbolDoc).ModuleVersion; got != "v1.2.3" {
+		t.Fatalf("expected resolved module version in symbol docs, got %q", got)
+	}
+
+	std := New(WithCacheMode(CacheDisabled))
+	res, err = std.Load("errors", "New", "")
+	if err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+
+	if got := res.(SymbolDoc).ModuleVersion; got != "" {
+		t.Fatalf("expected no module version for the standard library, got %q", got)
+	}
+}
+
+func TestBuildDocPatchQuery(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping network-dependent test in short mode")
+	}
+
+	workdir := t.TempDir()
+	goMod := "module example.com/test\n\ngo 1.21\n\nrequire golang.org/x/mod v0.20.0\n"
+	if err := os.WriteFile(filepath.Join(workdir, "go.mod"), []byte(goMod), 0o644); err != nil {
+		t.Fatalf("failed writing go.mod: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	g := New(WithWorkdir(workdir), WithContext(ctx))
+	d := &g
+
+	_, _, _, actualVersion, meta, err := d.buildDoc("golang.org/x/mod/semver", "@patch", false)
+	if err != nil {
+		t.Skipf("module proxy unavailable: %v", err)
+	}
+
+	if !strings.HasPrefix(actualVersion, "v0.20.") {
+		t.Fatalf("expected patch release of v0.20, got %q", actualVersion)
+	}
+
+	if meta.ModuleVersion != actualVersion {
+		t.Fatalf("expected module version %q, got %q", actualVersion, meta.ModuleVersion)
+	}
+}
+
 func TestSignatureForDeclUsesTypesInfo(t *testing.T) {
 	decl := &ast.FuncDecl{Type: &ast.FuncType{}}
 	sig := types.NewSignatureType(nil, nil, nil, types.NewTuple(), nil, false)
@@ -709,3 +1032,785 @@ func TestExprStringFallback(t *testing.T) {
 		t.Fatalf("expected fallback string \"BadExpr\", got %q", got)
 	}
 }
+
+func TestCollectDocLinks(t *testing.T) {
+	parser := comment.Parser{
+		LookupSym: func(recv, name string) bool {
+			return name == "Reader" || (recv == "Reader" && name == "Read")
+		},
+	}
+	doc := parser.Parse("Use [Reader] and [Reader.Read].\n\n  - see [fmt.Println]\n  - or [the docs] and [io.EOF]\n\n[the docs]: https://go.dev/doc\n")
+
+	links := collectDocLinks(doc)
+	var got []string
+	for _, l := range links {
+		ref := l.Name
+		if l.Recv != "" {
+			ref = l.Recv + "." + ref
+		}
+		if l.ImportPath != "" {
+			ref = l.ImportPath + "." + ref
+		}
+		got = append(got, ref)
+	}
+
+	want := []string{"Reader", "Reader.Read", "fmt.Println", "io.EOF"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("expected links %v, got %v", want, got)
+	}
+
+	if collectDocLinks(nil) != nil {
+		t.Fatalf("expected no links for nil doc")
+	}
+}
+
+func TestHTMLWithLinksWithoutParsedDoc(t *testing.T) {
+	pkg := PackageDoc{
+		DocText: "See [Client] and [Missing].\n",
+		Types:   []TypeDoc{{Name: "Client"}},
+	}
+
+	html, links := pkg.HTMLWithLinks()
+	if html == "" {
+		t.Fatalf("expected HTML to be rendered from doc text")
+	}
+
+	if len(links) != 1 || links[0].Name != "Client" {
+		t.Fatalf("expected only the Client link, got %+v", links)
+	}
+
+	sym := SymbolDoc{DocText: "Wraps [bytes.Buffer].\n"}
+	if _, links := sym.HTMLWithLinks(); len(links) != 1 || links[0].ImportPath != "bytes" {
+		t.Fatalf("expected bytes.Buffer link, got %+v", links)
+	}
+
+	// Only the names known to the symbol are resolved in its package.
+	sym = SymbolDoc{
+		Kind:    "type",
+		Name:    "Client",
+		DocText: "A [Client] is closed by [Client.Close], unlike [Missing] or [Client.Open].\n",
+		TypeDoc: &TypeDoc{Name: "Client", Methods: []MethodDoc{{Name: "Close"}}},
+	}
+
+	_, links = sym.HTMLWithLinks()
+	var names []string
+	for _, link := range links {
+		names = append(names, link.Recv+"."+link.Name)
+	}
+
+	if want := []string{".Client", "Client.Close"}; !slices.Equal(names, want) {
+		t.Fatalf("expected links %v, got %v", want, names)
+	}
+
+	sym = SymbolDoc{Kind: "method", Name: "Close", Receiver: "Client", DocText: "See [Client.Close] and [Other].\n"}
+	if _, links := sym.HTMLWithLinks(); len(links) != 1 || links[0].Recv != "Client" {
+		t.Fatalf("expected only the Client.Close link, got %+v", links)
+	}
+}
+
+func TestIsDeprecated(t *testing.T) {
+	cases := []struct {
+		text string
+		want bool
+	}{
+		{"", false},
+		{"Deprecated: use Bar instead.\n", true},
+		{"Foo does things.\n\nDeprecated: use Bar instead.\n", true},
+		{"Foo does things.\nDeprecated: not a paragraph start.\n", false},
+		{"Deprecated:use Bar.\n", false},
+		{"NotDeprecated: nope.\n", false},
+		{"Foo does things.\n\n\tDeprecated: in a code block\n", false},
+	}
+
+	for _, tc := range cases {
+		if got := isDeprecated(tc.text); got != tc.want {
+			t.Errorf("isDeprecated(%q) = %v, want %v", tc.text, got, tc.want)
+		}
+	}
+}
+
+func TestDeprecationNote(t *testing.T) {
+	cases := []struct {
+		text string
+		want string
+	}{
+		{"Foo does things.\n", ""},
+		{"Deprecated: use Bar instead.\n", "use Bar instead."},
+		{"Foo does things.\n\nDeprecated: use [Bar]\ninstead.\n\nMore text.\n", "use [Bar] instead."},
+		{"Deprecated:\n", ""},
+	}
+
+	for _, tc := range cases {
+		if _, got := deprecation(tc.text); got != tc.want {
+			t.Errorf("deprecation(%q) note = %q, want %q", tc.text, got, tc.want)
+		}
+	}
+}
+
+func TestGoGetWaitsForFetchSlot(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	g := New(WithContext(ctx), WithMaxConcurrentFetches(1))
+	d := &g
+
+	if got := d.Config().MaxConcurrentFetches; got != 1 {
+		t.Fatalf("expected fetch limit 1, got %d", got)
+	}
+
+	// Occupy the only slot, so goGet has to wait until the context ends.
+	d.fetchSem <- struct{}{}
+	cancel()
+
+	err := d.goGet(t.TempDir(), "example.com/foo")
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected cancellation while waiting for fetch slot, got %v", err)
+	}
+
+	if len(d.fetchSem) != 1 {
+		t.Fatalf("expected occupied slot to be left untouched, got %d", len(d.fetchSem))
+	}
+}
+
+func TestWithMaxConcurrentFetches(t *testing.T) {
+	if got := New().fetchSem; got != fetchSem {
+		t.Fatalf("expected instances to share the global fetch limit by default")
+	}
+
+	g := New(WithMaxConcurrentFetches(0))
+	if got := g.Config().MaxConcurrentFetches; got != 0 {
+		t.Fatalf("expected unlimited fetches, got limit %d", got)
+	}
+
+	g.SetOptions(WithMaxConcurrentFetches(3))
+	if got := g.Config().MaxConcurrentFetches; got != 3 {
+		t.Fatalf("expected fetch limit 3, got %d", got)
+	}
+}
+
+func TestWithConcurrency(t *testing.T) {
+	def := New()
+	if got := def.Config().Concurrency; got != runtime.NumCPU() {
+		t.Fatalf("expected the default limit to be NumCPU, got %d", got)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	g := New(WithContext(ctx), WithConcurrency(1))
+	d := &g
+
+	if got := d.Config().Concurrency; got != 1 {
+		t.Fatalf("expected load limit 1, got %d", got)
+	}
+
+	// Occupy the only slot, so the load has to wait until the context ends.
+	d.loadSem <- struct{}{}
+	cancel()
+
+	_, _, _, _, _, _, _, err := d.loadDocPkg("errors", "", false)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected cancellation while waiting for load slot, got %v", err)
+	}
+
+	if len(d.loadSem) != 1 {
+		t.Fatalf("expected occupied slot to be left untouched, got %d", len(d.loadSem))
+	}
+
+	g.SetOptions(WithConcurrency(0))
+	if got := g.Config().Concurrency; got != runtime.NumCPU() {
+		t.Fatalf("expected n <= 0 to restore the default limit, got %d", got)
+	}
+}
+
+func TestStructFieldDocsRawComments(t *testing.T) {
+	const src = `package p
+
+// T is a type.
+type T struct {
+	// Name is the name.
+	//nolint:revive
+	Name string
+
+	Count int //go:generate echo count
+}
+`
+
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "p.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+
+	dpkg, err := doc.NewFromFiles(fset, []*ast.File{f}, "example.com/p")
+	if err != nil {
+		t.Fatalf("doc failed: %v", err)
+	}
+
+	fields := structFieldDocs(dpkg.Types[0], fset, nil, nil, buildOptions{})
+	if fields[0].Doc != "Name is the name.\n" || fields[0].RawDoc != "" {
+		t.Fatalf("expected directives stripped and no raw doc by default, got %+v", fields[0])
+	}
+
+	fields = structFieldDocs(dpkg.Types[0], fset, nil, nil, buildOptions{rawComments: true})
+	if want := "// Name is the name.\n//nolint:revive"; fields[0].RawDoc != want {
+		t.Fatalf("expected raw doc %q, got %q", want, fields[0].RawDoc)
+	}
+
+	if want := "//go:generate echo count"; fields[1].RawDoc != want {
+		t.Fatalf("expected raw doc %q, got %q", want, fields[1].RawDoc)
+	}
+}
+
+func TestFieldDocHTML(t *testing.T) {
+	const src = `package p
+
+// T is a type.
+type T struct {
+	// Mode is one of:
+	//   - [Fast]
+	//   - [Slow]
+	Mode int
+
+	Name string
+}
+
+// Fast and Slow are modes.
+const (
+	Fast = iota
+	Slow
+)
+`
+
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "p.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+
+	dpkg, err := doc.NewFromFiles(fset, []*ast.File{f}, "example.com/p")
+	if err != nil {
+		t.Fatalf("doc failed: %v", err)
+	}
+
+	symbols := buildSymbolIndex(dpkg, fset, nil, nil, "example.com/p", buildOptions{})
+	fields := symbols["T"].TypeDoc.Fields
+
+	html := fields[0].HTML()
+	if !strings.Contains(html, "<ul>") || !strings.Contains(html, `<a href="#Fast">Fast</a>`) {
+		t.Fatalf("expected list with doc links in field HTML, got %q", html)
+	}
+
+	if fields[0].DocHTML != "" {
+		t.Fatalf("expected field HTML to be rendered lazily, got DocHTML %q", fields[0].DocHTML)
+	}
+
+	if got := fields[1].HTML(); got != "" {
+		t.Fatalf("expected no HTML for an undocumented field, got %q", got)
+	}
+
+	pkgDoc := toPkgDoc(dpkg, fset, nil, nil, "example.com/p", buildOptions{})
+	if got := pkgDoc.Types[0].Fields[0].HTML(); !strings.Contains(got, "<ul>") {
+		t.Fatalf("expected field HTML in package docs, got %q", got)
+	}
+
+	// Fields restored from the cache keep their exported fields only.
+	restored := FieldDoc{Name: "Mode", Doc: fields[0].Doc}
+	if got := restored.HTML(); !strings.Contains(got, "<li>") {
+		t.Fatalf("expected field HTML rendered from the doc text, got %q", got)
+	}
+}
+
+func TestGoCmdEnvSumDB(t *testing.T) {
+	g := New()
+	if env := g.goCmdEnv(); env[len(env)-1] != "GOWORK=off" {
+		t.Fatalf("expected GOSUMDB to be inherited by default, got %v", env[len(env)-3:])
+	}
+
+	g.SetOptions(WithSumDB("off"))
+	env := g.goCmdEnv()
+	if env[len(env)-1] != "GOSUMDB=off" {
+		t.Fatalf("expected GOSUMDB=off to override the environment, got %v", env[len(env)-3:])
+	}
+
+	if got := g.Config().SumDB; got != "off" {
+		t.Fatalf("expected SumDB off in config, got %q", got)
+	}
+}
+
+func TestWithGoEnv(t *testing.T) {
+	// lookup returns the effective value of key, the last one in env.
+	lookup := func(env []string, key string) string {
+		var value string
+		for _, kv := range env {
+			if k, v, _ := strings.Cut(kv, "="); k == key {
+				value = v
+			}
+		}
+
+		return value
+	}
+
+	goEnv := map[string]string{
+		"GOPROXY": "https://proxy.example.com",
+		"GOWORK":  "auto",
+		"GOOS":    "windows",
+		"GOSUMDB": "sum.example.com",
+	}
+
+	g := New(WithGoEnv(goEnv))
+	goEnv["GOPROXY"] = "direct" // The option keeps its own copy.
+
+	env := g.goCmdEnv()
+	if got := lookup(env, "GOPROXY"); got != "https://proxy.example.com" {
+		t.Fatalf("expected GOPROXY from WithGoEnv, got %q", got)
+	}
+
+	if got := lookup(env, "GOWORK"); got != "auto" {
+		t.Fatalf("expected GOWORK to be overridable, got %q", got)
+	}
+
+	cfg, err := g.packagesConfig("fmt", ".", 0)
+	if err != nil {
+		t.Fatalf("packagesConfig: %v", err)
+	}
+
+	if got := lookup(cfg.Env, "GOOS"); got != "windows" {
+		t.Fatalf("expected GOOS from WithGoEnv for packages, got %q", got)
+	}
+
+	if got := g.Config().GOOS; got != "windows" {
+		t.Fatalf("expected config GOOS windows, got %q", got)
+	}
+
+	variant := g.cacheVariant()
+	if variant != "env=GOOS=windows" {
+		t.Fatalf("expected only build variables in cache variant, got %q", variant)
+	}
+
+	// Explicit options take precedence.
+	g.SetOptions(WithGOOS("linux"), WithSumDB("off"))
+
+	cfg, err = g.packagesConfig("fmt", ".", 0)
+	if err != nil {
+		t.Fatalf("packagesConfig: %v", err)
+	}
+
+	if got := lookup(cfg.Env, "GOOS"); got != "linux" {
+		t.Fatalf("expected WithGOOS to override WithGoEnv, got %q", got)
+	}
+
+	if variant := g.cacheVariant(); variant != "env=GOOS=windows,goos=linux" {
+		t.Fatalf("expected GOOS from WithGOOS in cache variant, got %q", variant)
+	}
+
+	if got := lookup(g.goCmdEnv(), "GOSUMDB"); got != "off" {
+		t.Fatalf("expected WithSumDB to override WithGoEnv, got %q", got)
+	}
+}
+
+func TestPackagesConfigGoBinary(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+
+	bin := filepath.Join(runtime.GOROOT(), "bin", "go")
+	g := New(WithGoBinary(bin))
+
+	version, err := g.goBinaryVersion()
+	if err != nil {
+		t.Fatalf("goBinaryVersion: %v", err)
+	}
+
+	cfg, err := g.packagesConfig("fmt", ".", 0)
+	if err != nil {
+		t.Fatalf("packagesConfig: %v", err)
+	}
+
+	var path, toolchain string
+	for _, kv := range cfg.Env {
+		if v, ok := strings.CutPrefix(kv, "PATH="); ok {
+			path = v
+		}
+
+		if v, ok := strings.CutPrefix(kv, "GOTOOLCHAIN="); ok {
+			toolchain = v
+		}
+	}
+
+	if toolchain != version+"+path" {
+		t.Fatalf("expected GOTOOLCHAIN %s+path, got %q", version, toolchain)
+	}
+
+	binDir, _, _ := strings.Cut(path, string(os.PathListSeparator))
+	link := filepath.Join(binDir, version)
+	if runtime.GOOS == "windows" {
+		link += ".exe"
+	}
+
+	if target, err := os.Readlink(link); err != nil || target != bin {
+		t.Fatalf("expected %s to link to %s, got %q (%v)", link, bin, target, err)
+	}
+
+	// The link is reused.
+	if again, err := g.goBinaryPathDir(version); err != nil || again != binDir {
+		t.Fatalf("expected %s to be reused, got %q (%v)", binDir, again, err)
+	}
+}
+
+func TestGoCmdEnvGoProxy(t *testing.T) {
+	t.Setenv("GOPROXY", "https://proxy.golang.org")
+
+	lookup := func(env []string) string {
+		var value string
+		for _, kv := range env {
+			if v, ok := strings.CutPrefix(kv, "GOPROXY="); ok {
+				value = v
+			}
+		}
+
+		return value
+	}
+
+	g := New()
+	if got := lookup(g.goCmdEnv()); got != "https://proxy.golang.org" {
+		t.Fatalf("expected GOPROXY to be inherited by default, got %q", got)
+	}
+
+	g.SetOptions(WithGoEnv(map[string]string{"GOPROXY": "direct"}), WithGoProxy("https://athens.internal"))
+	if got := lookup(g.goCmdEnv()); got != "https://athens.internal" {
+		t.Fatalf("expected WithGoProxy to override the environment and WithGoEnv, got %q", got)
+	}
+
+	if got := os.Getenv("GOPROXY"); got != "https://proxy.golang.org" {
+		t.Fatalf("expected the process environment to be unchanged, got %q", got)
+	}
+
+	if got := g.Config().GoProxy; got != "https://athens.internal" {
+		t.Fatalf("expected GoProxy in config, got %q", got)
+	}
+}
+
+func TestMatchFilePattern(t *testing.T) {
+	tests := []struct {
+		filename, pattern string
+		want              bool
+	}{
+		{"/src/os/file_linux.go", "_linux.go", true},
+		{"/src/os/file_unix.go", "_linux.go", false},
+		{"/src/os/file_linux.go", "*_linux.go", true},
+		{"/src/os/file_linux.go", "file_*.go", true},
+		{"/src/linux/file.go", "*_linux.go", false},
+	}
+
+	for _, tt := range tests {
+		got, err := matchFilePattern(tt.filename, tt.pattern)
+		if err != nil {
+			t.Fatalf("matchFilePattern(%q, %q) error: %v", tt.filename, tt.pattern, err)
+		}
+
+		if got != tt.want {
+			t.Errorf("matchFilePattern(%q, %q) = %v, want %v", tt.filename, tt.pattern, got, tt.want)
+		}
+	}
+
+	if _, err := matchFilePattern("file.go", "[a-"); !errors.Is(err, ErrInvalidFilePattern) {
+		t.Errorf("Expected ErrInvalidFilePattern, got %v", err)
+	}
+}
+
+func TestCompareValueDocsIsTotal(t *testing.T) {
+	a := ValueDoc{Names: []string{"X"}, Doc: "a"}
+	b := ValueDoc{Names: []string{"X"}, Doc: "b"}
+	c := ValueDoc{Names: []string{"X"}, Values: []string{"1"}}
+
+	for _, values := range [][]ValueDoc{{a, b, c}, {c, b, a}, {b, c, a}} {
+		sort.Slice(values, func(i, j int) bool {
+			return compareValueDocs(values[i], values[j]) < 0
+		})
+
+		if !reflect.DeepEqual(values, []ValueDoc{a, b, c}) {
+			t.Fatalf("Expected a total order, got %+v", values)
+		}
+	}
+}
+
+func TestBuildDocJSONIsReproducible(t *testing.T) {
+	g := New()
+
+	var encoded [2][]byte
+	for i := range encoded {
+		pkgDoc, _, _, _, _, err := g.buildDoc("net/http", "", false)
+		if err != nil {
+			t.Fatalf("unexpected build error: %v", err)
+		}
+
+		data, err := json.Marshal(pkgDoc)
+		if err != nil {
+			t.Fatalf("unexpected marshal error: %v", err)
+		}
+
+		encoded[i] = data
+	}
+
+	if !bytes.Equal(encoded[0], encoded[1]) {
+		t.Fatalf("expected byte-identical JSON across loads")
+	}
+}
+
+func TestWithBuildTags(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod":           "module example.com/tagged\n\ngo 1.21\n",
+		"tagged.go":        "// Package tagged is documented.\npackage tagged\n\n// Always is always built.\nfunc Always() {}\n",
+		"custom.go":        "//go:build customtag && purego\n\npackage tagged\n\n// Tagged is only built with customtag and purego.\nfunc Tagged() {}\n",
+		"custom_test.go":   "//go:build customtag\n\npackage tagged\n\nfunc ExampleTagged() {}\n",
+		"untagged_test.go": "package tagged\n\nfunc ExampleAlways() {}\n",
+	}
+
+	for name, src := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(src), 0o644); err != nil {
+			t.Fatalf("failed writing %s: %v", name, err)
+		}
+	}
+
+	load := func(opts ...Option) (funcs, examples []string) {
+		t.Helper()
+
+		g := New(opts...)
+		dpkg, _, _, _, _, _, _, err := g.loadDocPkg(".", dir, false)
+		if err != nil {
+			t.Fatalf("failed loading package: %v", err)
+		}
+
+		for _, f := range dpkg.Funcs {
+			funcs = append(funcs, f.Name)
+			for _, ex := range f.Examples {
+				examples = append(examples, ex.Name)
+			}
+		}
+
+		return funcs, examples
+	}
+
+	funcs, examples := load()
+	if !reflect.DeepEqual(funcs, []string{"Always"}) || !reflect.DeepEqual(examples, []string{"Always"}) {
+		t.Errorf("expected only untagged declarations without tags, got %v and examples %v", funcs, examples)
+	}
+
+	funcs, examples = load(WithBuildTags("customtag", " purego ", ""))
+	if !reflect.DeepEqual(funcs, []string{"Always", "Tagged"}) || !reflect.DeepEqual(examples, []string{"Always", "Tagged"}) {
+		t.Errorf("expected tagged declarations with tags, got %v and examples %v", funcs, examples)
+	}
+
+	g := New(WithBuildTags("customtag", " purego ", ""))
+	if got := g.buildFlags(); !reflect.DeepEqual(got, []string{"-tags=customtag,purego"}) {
+		t.Errorf("expected comma-joined -tags flag, got %v", got)
+	}
+
+	if got := g.Config().BuildTags; !reflect.DeepEqual(got, []string{"customtag", "purego"}) {
+		t.Errorf("expected configured build tags, got %v", got)
+	}
+
+	if variant := g.cacheVariant(); !strings.Contains(variant, "tags=customtag+purego") {
+		t.Errorf("expected build tags in cache variant, got %q", variant)
+	}
+}
+
+func TestWithModMode(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod":                        "module example.com/app\n\ngo 1.21\n\nrequire example.com/dep v1.0.0\n",
+		"app.go":                        "package app\n\nimport _ \"example.com/dep\"\n",
+		"vendor/modules.txt":            "# example.com/dep v1.0.0\n## explicit; go 1.21\nexample.com/dep\n",
+		"vendor/example.com/dep/dep.go": "// Package dep is vendored.\npackage dep\n\n// Vendored is only available from the vendor directory.\nfunc Vendored() {}\n",
+	}
+
+	for name, src := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("failed creating directory of %s: %v", name, err)
+		}
+
+		if err := os.WriteFile(path, []byte(src), 0o644); err != nil {
+			t.Fatalf("failed writing %s: %v", name, err)
+		}
+	}
+
+	// Keep the go command from fetching the dependency.
+	env := WithGoEnv(map[string]string{"GOPROXY": "off", "GOFLAGS": ""})
+
+	g := New(WithWorkdir(dir), WithModMode(ModVendor), env)
+	dpkg, _, _, _, _, _, _, err := g.loadDocPkg("example.com/dep", dir, false)
+	if err != nil {
+		t.Fatalf("failed loading vendored package: %v", err)
+	}
+
+	if len(dpkg.Funcs) != 1 || dpkg.Funcs[0].Name != "Vendored" {
+		t.Errorf("expected the vendored declarations, got %+v", dpkg.Funcs)
+	}
+
+	mod := New(WithModMode(ModMod), env)
+	if _, _, _, _, _, _, _, err := mod.loadDocPkg("example.com/dep", dir, false); err == nil {
+		t.Errorf("expected -mod=mod to ignore the vendor directory")
+	}
+
+	if _, _, err := g.checkModuleDep("example.com/missing", ""); err == nil || !strings.Contains(err.Error(), "vendor directory") {
+		t.Errorf("expected missing vendored package not to be fetched, got %v", err)
+	}
+
+	if got := g.buildFlags(); !reflect.DeepEqual(got, []string{"-mod=vendor"}) {
+		t.Errorf("expected -mod=vendor flag, got %v", got)
+	}
+
+	if got := g.Config().ModMode; got != ModVendor {
+		t.Errorf("expected configured mod mode, got %v", got)
+	}
+
+	if variant := g.cacheVariant(); !strings.Contains(variant, "mod=vendor") {
+		t.Errorf("expected mod mode in cache variant, got %q", variant)
+	}
+}
+
+func TestWithTestFiles(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod":           "module example.com/fixtures\n\ngo 1.21\n",
+		"fixtures.go":      "// Package fixtures is documented.\npackage fixtures\n\n// Hello says hello.\nfunc Hello() string { return \"hello\" }\n",
+		"helpers_test.go":  "package fixtures\n\nimport \"testing\"\n\n// Fixture is a test fixture.\ntype Fixture struct{ Name string }\n\n// NewFixture returns a fixture.\nfunc NewFixture() *Fixture { return &Fixture{} }\n\nfunc TestHello(t *testing.T) {}\n\nfunc ExampleHello() {}\n",
+		"external_test.go": "package fixtures_test\n\n// External is not part of the package.\nfunc External() {}\n",
+	}
+
+	for name, src := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(src), 0o644); err != nil {
+			t.Fatalf("failed writing %s: %v", name, err)
+		}
+	}
+
+	load := func(opts ...Option) (funcs, types, examples []string) {
+		t.Helper()
+
+		g := New(opts...)
+		dpkg, _, _, _, _, _, _, err := g.loadDocPkg(".", dir, false)
+		if err != nil {
+			t.Fatalf("failed loading package: %v", err)
+		}
+
+		for _, f := range dpkg.Funcs {
+			funcs = append(funcs, f.Name)
+			for _, ex := range f.Examples {
+				examples = append(examples, ex.Name)
+			}
+		}
+
+		for _, typ := range dpkg.Types {
+			types = append(types, typ.Name)
+			for _, f := range typ.Funcs {
+				funcs = append(funcs, f.Name)
+			}
+		}
+
+		return funcs, types, examples
+	}
+
+	funcs, types, examples := load()
+	if !reflect.DeepEqual(funcs, []string{"Hello"}) || types != nil || !reflect.DeepEqual(examples, []string{"Hello"}) {
+		t.Errorf("expected test files used for examples only by default, got funcs %v, types %v, examples %v", funcs, types, examples)
+	}
+
+	funcs, types, examples = load(WithTestFiles(true))
+	if !reflect.DeepEqual(funcs, []string{"Hello", "NewFixture"}) || !reflect.DeepEqual(types, []string{"Fixture"}) || !reflect.DeepEqual(examples, []string{"Hello"}) {
+		t.Errorf("expected test helpers documented with test files, got funcs %v, types %v, examples %v", funcs, types, examples)
+	}
+
+	g := New(WithTestFiles(true))
+	if variant := g.cacheVariant(); variant != "test-files" {
+		t.Errorf("expected test files in cache variant, got %q", variant)
+	}
+}
+
+func TestWithTimeout(t *testing.T) {
+	g := New()
+	if call, cancel := g.withTimeout(); call != &g {
+		cancel()
+		t.Fatal("expected no copy without a timeout")
+	}
+
+	base, cancelBase := context.WithCancel(context.Background())
+	g.SetOptions(WithContext(base), WithTimeout(time.Hour))
+
+	call, cancel := g.withTimeout()
+	deadline, ok := call.context().Deadline()
+	if !ok || time.Until(deadline) <= 0 {
+		cancel()
+		t.Fatalf("expected a deadline on the call context, got %v, %v", deadline, ok)
+	}
+
+	cancel()
+	if call.context().Err() == nil {
+		t.Fatal("expected the call context to be released")
+	}
+
+	// The base context is not consumed, so the next call gets a fresh one.
+	next, cancel := g.withTimeout()
+	defer cancel()
+
+	if err := next.context().Err(); err != nil {
+		t.Fatalf("expected a fresh call context, got %v", err)
+	}
+
+	cancelBase()
+	if next.context().Err() == nil {
+		t.Fatal("expected the call context to derive from the base context")
+	}
+
+	g.SetOptions(WithContext(context.Background()), WithTimeout(time.Nanosecond), WithCacheMode(CacheDisabled))
+	if _, err := g.Load("errors", "", ""); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected Load to time out, got %v", err)
+	}
+}
+
+func TestParseStructTag(t *testing.T) {
+	tests := []struct {
+		tag  string
+		want map[string]string
+	}{
+		{"", nil},
+		{`json:"name,omitempty" xml:"n"`, map[string]string{"json": "name,omitempty", "xml": "n"}},
+		{`a:"x\"y"  b:"" a:"dup"`, map[string]string{"a": `x"y`, "b": ""}},
+		{`json:name`, nil},
+		{`json:"unterminated`, nil},
+		{`:"no key"`, nil},
+		{`json:"ok" garbage`, nil},
+	}
+
+	for _, tt := range tests {
+		if got := parseStructTag(tt.tag); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseStructTag(%q) = %v, want %v", tt.tag, got, tt.want)
+		}
+	}
+}
+
+func TestModuleFetchRespectsCancellation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	g := New(WithContext(ctx), WithWorkdir(t.TempDir()))
+	d := &g
+
+	if _, _, err := d.checkModuleDep("example.com/foo", "v1.0.0"); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected checkModuleDep to be canceled, got %v", err)
+	}
+
+	d.depCache.Range(func(key, _ any) bool {
+		t.Fatalf("expected no cached dependency lookup after cancellation, got %v", key)
+		return false
+	})
+
+	d.loadPkg = func(string, string, bool) (*doc.Package, *token.FileSet, *types.Info, *packageAST, string, *packages.Module, string, error) {
+		return nil, nil, nil, nil, "", nil, "", errors.New("not found")
+	}
+	d.checkDep = func(string, string) (string, func(), error) {
+		t.Fatal("expected no module fetch after cancellation")
+		return "", nil, nil
+	}
+
+	if _, _, _, _, _, err := d.buildDoc("example.com/foo", "", false); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected buildDoc to be canceled, got %v", err)
+	}
+}
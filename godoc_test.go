@@ -2,9 +2,16 @@ package godoc_test
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"go/build"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
 	"strings"
 	"testing"
+	"testing/fstest"
 	"time"
 
 	"go.dw1.io/godoc"
@@ -24,16 +31,28 @@ func (g testGodoc) Load(importPath, sel, version string) (godoc.Result, error) {
 
 func TestNew(t *testing.T) {
 	g := godoc.New(godoc.WithGOOS("linux"), godoc.WithGOARCH("amd64"), godoc.WithWorkdir("/tmp"))
-	// Fields are unexported, so we can't check them directly
-	// But we can verify the instance was created
-	_ = g
+
+	cfg := g.Config()
+	if cfg.GOOS != "linux" || cfg.GOARCH != "amd64" || cfg.Workdir != "/tmp" {
+		t.Fatalf("expected options to be applied, got %+v", cfg)
+	}
+
+	if cfg.GoVersion != runtime.Version() {
+		t.Fatalf("expected go version %q, got %q", runtime.Version(), cfg.GoVersion)
+	}
 }
 
 func TestNewDefaultDir(t *testing.T) {
 	g := godoc.New()
-	// Fields are unexported, so we can't check workdir directly
-	// But we can verify the instance was created with default
-	_ = g
+
+	cfg := g.Config()
+	if cfg.Workdir != "." {
+		t.Fatalf("expected default workdir \".\", got %q", cfg.Workdir)
+	}
+
+	if cfg.GOOS != build.Default.GOOS || cfg.GOARCH != build.Default.GOARCH {
+		t.Fatalf("expected default platform %s/%s, got %s/%s", build.Default.GOOS, build.Default.GOARCH, cfg.GOOS, cfg.GOARCH)
+	}
 }
 
 func TestLoadPackage(t *testing.T) {
@@ -151,6 +170,29 @@ func TestLoadSymbolMethod(t *testing.T) {
 	}
 }
 
+func TestLoadSymbolMethodAlternateSeparators(t *testing.T) {
+	g := newTestGodoc()
+	for _, sel := range []string{"Request/ParseForm", "Request#ParseForm"} {
+		result, err := g.Load("net/http", sel, "")
+		if err != nil {
+			t.Fatalf("Failed to load net/http %s: %v", sel, err)
+		}
+
+		symDoc, ok := result.(godoc.SymbolDoc)
+		if !ok {
+			t.Fatalf("Expected SymbolDoc for %s, got %T", sel, result)
+		}
+
+		if symDoc.Kind != "method" || symDoc.Receiver != "Request" || symDoc.Name != "ParseForm" {
+			t.Errorf("Expected Request.ParseForm method for %s, got %s %s.%s", sel, symDoc.Kind, symDoc.Receiver, symDoc.Name)
+		}
+	}
+
+	if _, err := g.Load("net/http", "Request//ParseForm", ""); !errors.Is(err, godoc.ErrInvalidSelector) {
+		t.Errorf("Expected ErrInvalidSelector for empty selector segment, got %v", err)
+	}
+}
+
 func TestLoadSymbolConst(t *testing.T) {
 	g := newTestGodoc()
 	result, err := g.Load("net/http", "StatusOK", "")
@@ -181,7 +223,27 @@ func TestLoadNonExistentSymbol(t *testing.T) {
 	g := godoc.New()
 	_, err := g.Load("fmt", "NonExistent", "")
 	if err == nil {
-		t.Errorf("Expected error for non-existent symbol")
+		t.Fatalf("Expected error for non-existent symbol")
+	}
+
+	if !errors.Is(err, godoc.ErrSymbolNotFound) {
+		t.Errorf("Expected ErrSymbolNotFound, got %v", err)
+	}
+
+	var notFound *godoc.SymbolNotFoundError
+	if !errors.As(err, &notFound) || notFound.ImportPath != "fmt" || notFound.Symbol != "NonExistent" {
+		t.Errorf("Expected SymbolNotFoundError for fmt.NonExistent, got %#v", err)
+	}
+
+	_, err = g.Load("fmt", "printf", "")
+	if !errors.As(err, &notFound) || len(notFound.Suggestions) == 0 || notFound.Suggestions[0] != "Printf" {
+		t.Errorf("Expected Printf suggested for fmt.printf, got %v", err)
+	} else if !strings.Contains(err.Error(), "did you mean Printf") {
+		t.Errorf("Expected suggestion in error message, got %q", err)
+	}
+
+	if _, err := g.Load("invalid/package", "Foo", ""); errors.Is(err, godoc.ErrSymbolNotFound) {
+		t.Errorf("Expected package load error not to match ErrSymbolNotFound, got %v", err)
 	}
 }
 
@@ -257,6 +319,144 @@ func TestStructFieldsIncluded(t *testing.T) {
 	if methodField.Type != "string" {
 		t.Fatalf("Expected Method field type string, got %s", methodField.Type)
 	}
+
+	if methodField.Deprecated {
+		t.Errorf("Expected Method field not to be deprecated")
+	}
+
+	for _, field := range requestDoc.Fields {
+		if field.Name == "Cancel" && !field.Deprecated {
+			t.Errorf("Expected Cancel field on net/http.Request to be deprecated")
+		}
+	}
+}
+
+func TestConstValues(t *testing.T) {
+	g := newTestGodoc()
+	result, err := g.Load("time", "", "")
+	if err != nil {
+		t.Fatalf("Failed to load time: %v", err)
+	}
+
+	pkgDoc, ok := result.(godoc.PackageDoc)
+	if !ok {
+		t.Fatalf("Expected PackageDoc, got %T", result)
+	}
+
+	var weekdays *godoc.ValueDoc
+	for i := range pkgDoc.Consts {
+		if len(pkgDoc.Consts[i].Names) > 0 && pkgDoc.Consts[i].Names[0] == "Sunday" {
+			weekdays = &pkgDoc.Consts[i]
+			break
+		}
+	}
+
+	if weekdays == nil {
+		t.Fatalf("Expected Weekday constants in time")
+	}
+
+	if len(weekdays.Values) != len(weekdays.Names) {
+		t.Fatalf("Expected values aligned with names, got %v for %v", weekdays.Values, weekdays.Names)
+	}
+
+	for i, want := range []string{"0", "1", "2", "3", "4", "5", "6"} {
+		if weekdays.Values[i] != want {
+			t.Errorf("Expected %s = %s, got %s", weekdays.Names[i], want, weekdays.Values[i])
+		}
+	}
+
+	result, err = g.Load("time", "Saturday", "")
+	if err != nil {
+		t.Fatalf("Failed to load time.Saturday: %v", err)
+	}
+
+	symDoc, ok := result.(godoc.SymbolDoc)
+	if !ok {
+		t.Fatalf("Expected SymbolDoc, got %T", result)
+	}
+
+	if symDoc.Value != "6" {
+		t.Errorf("Expected time.Saturday value 6, got %q", symDoc.Value)
+	}
+
+	result, err = g.Load("net/http", "StatusOK", "")
+	if err != nil {
+		t.Fatalf("Failed to load net/http.StatusOK: %v", err)
+	}
+
+	if v := result.(godoc.SymbolDoc).Value; v != "200" {
+		t.Errorf("Expected net/http.StatusOK value 200, got %q", v)
+	}
+
+	result, err = g.Load("io", "EOF", "")
+	if err != nil {
+		t.Fatalf("Failed to load io.EOF: %v", err)
+	}
+
+	if v := result.(godoc.SymbolDoc).Value; v != `errors.New("EOF")` {
+		t.Errorf("Expected io.EOF initializer, got %q", v)
+	}
+}
+
+func TestImplementers(t *testing.T) {
+	g := newTestGodoc(godoc.WithImplementers(true))
+	result, err := g.Load("io", "Reader", "")
+	if err != nil {
+		t.Fatalf("Failed to load io.Reader: %v", err)
+	}
+
+	symDoc, ok := result.(godoc.SymbolDoc)
+	if !ok {
+		t.Fatalf("Expected SymbolDoc, got %T", result)
+	}
+
+	for _, want := range []string{"*LimitedReader", "*PipeReader", "*SectionReader"} {
+		if !slices.Contains(symDoc.Implementers, want) {
+			t.Errorf("Expected %s in io.Reader implementers, got %v", want, symDoc.Implementers)
+		}
+	}
+
+	g = newTestGodoc()
+	result, err = g.Load("io", "Reader", "")
+	if err != nil {
+		t.Fatalf("Failed to load io.Reader: %v", err)
+	}
+
+	if impls := result.(godoc.SymbolDoc).Implementers; impls != nil {
+		t.Errorf("Expected no implementers unless enabled, got %v", impls)
+	}
+}
+
+func TestTypeConstructors(t *testing.T) {
+	g := newTestGodoc()
+	result, err := g.Load("bytes", "Buffer", "")
+	if err != nil {
+		t.Fatalf("Failed to load bytes.Buffer: %v", err)
+	}
+
+	symDoc, ok := result.(godoc.SymbolDoc)
+	if !ok || symDoc.TypeDoc == nil {
+		t.Fatalf("Expected type SymbolDoc, got %T", result)
+	}
+
+	var names []string
+	for _, c := range symDoc.Constructors {
+		names = append(names, c.Name)
+	}
+
+	if !slices.Equal(names, []string{"NewBuffer", "NewBufferString"}) {
+		t.Fatalf("Expected NewBuffer and NewBufferString constructors, got %v", names)
+	}
+
+	if ret := symDoc.Constructors[0].Returns; len(ret) != 1 || ret[0].Type != "*bytes.Buffer" {
+		t.Errorf("Expected NewBuffer to return *bytes.Buffer, got %+v", ret)
+	}
+
+	for _, m := range symDoc.Methods {
+		if strings.HasPrefix(m.Name, "New") {
+			t.Errorf("Expected constructor %s not to be listed as a method", m.Name)
+		}
+	}
 }
 
 func TestExtractArgs(t *testing.T) {
@@ -939,3 +1139,1574 @@ func TestLoadImportPathAbsolute(t *testing.T) {
 		t.Fatalf("expected ErrInvalidImportPath, got %v", err)
 	}
 }
+
+func TestPackageDocHTMLWithLinks(t *testing.T) {
+	g := newTestGodoc()
+	result, err := g.Load("net/http", "", "")
+	if err != nil {
+		t.Fatalf("Failed to load net/http: %v", err)
+	}
+
+	pkgDoc, ok := result.(godoc.PackageDoc)
+	if !ok {
+		t.Fatalf("Expected PackageDoc, got %T", result)
+	}
+
+	html, links := pkgDoc.HTMLWithLinks()
+	if html != pkgDoc.HTML() {
+		t.Errorf("Expected HTMLWithLinks to return the package HTML")
+	}
+
+	found := false
+	for _, link := range links {
+		if link.ImportPath == "" && link.Name == "Client" {
+			found = true
+			break
+		}
+	}
+	if !found {
+		t.Errorf("Expected a [Client] doc link in net/http package docs, got %d links", len(links))
+	}
+}
+
+func TestJSONSchema(t *testing.T) {
+	data, err := godoc.JSONSchema()
+	if err != nil {
+		t.Fatalf("JSONSchema failed: %v", err)
+	}
+
+	var schema struct {
+		OneOf []struct {
+			Title      string                     `json:"title"`
+			Properties map[string]json.RawMessage `json:"properties"`
+		} `json:"oneOf"`
+	}
+	if err := json.Unmarshal(data, &schema); err != nil {
+		t.Fatalf("invalid schema JSON: %v", err)
+	}
+
+	if len(schema.OneOf) != 2 || schema.OneOf[0].Title != "PackageDoc" || schema.OneOf[1].Title != "SymbolDoc" {
+		t.Fatalf("expected PackageDoc and SymbolDoc alternatives, got %+v", schema.OneOf)
+	}
+
+	for _, name := range []string{"import_path", "consts", "types"} {
+		if _, ok := schema.OneOf[0].Properties[name]; !ok {
+			t.Errorf("expected PackageDoc property %q", name)
+		}
+	}
+
+	// Fields of the embedded FuncDoc and TypeDoc are promoted.
+	for _, name := range []string{"kind", "args", "methods"} {
+		if _, ok := schema.OneOf[1].Properties[name]; !ok {
+			t.Errorf("expected SymbolDoc property %q", name)
+		}
+	}
+
+	if _, ok := schema.OneOf[0].Properties["DocHTML"]; ok {
+		t.Errorf("expected fields excluded from JSON to be omitted from the schema")
+	}
+}
+
+func TestPackageName(t *testing.T) {
+	g := newTestGodoc()
+
+	tests := map[string]string{
+		"fmt":                           "fmt",
+		"net/http":                      "http",
+		"go.dw1.io/godoc/cmd/godoc-cli": "main",
+	}
+
+	for importPath, want := range tests {
+		name, err := g.PackageName(importPath, "")
+		if err != nil {
+			t.Fatalf("PackageName(%q) failed: %v", importPath, err)
+		}
+
+		if name != want {
+			t.Errorf("PackageName(%q) = %q, want %q", importPath, name, want)
+		}
+	}
+
+	if _, err := g.PackageName("", ""); !errors.Is(err, godoc.ErrEmptyImportPath) {
+		t.Errorf("Expected ErrEmptyImportPath, got %v", err)
+	}
+}
+
+func TestWithGoBinary(t *testing.T) {
+	goBin := filepath.Join(runtime.GOROOT(), "bin", "go")
+	if _, err := os.Stat(goBin); err != nil {
+		t.Skipf("go binary not found: %v", err)
+	}
+
+	g := newTestGodoc(godoc.WithGoBinary(goBin))
+	if cfg := g.Config(); cfg.GoBinary != goBin || cfg.GoVersion != runtime.Version() {
+		t.Fatalf("expected go binary %s at %s, got %+v", goBin, runtime.Version(), cfg)
+	}
+
+	if _, err := g.Load("fmt", "Println", ""); err != nil {
+		t.Fatalf("Failed to load fmt.Println with go binary %s: %v", goBin, err)
+	}
+
+	g = newTestGodoc(godoc.WithGoBinary(filepath.Join(t.TempDir(), "missing-go")))
+	if _, err := g.Load("fmt", "Println", ""); !errors.Is(err, godoc.ErrInvalidGoBinary) {
+		t.Fatalf("Expected ErrInvalidGoBinary, got %v", err)
+	}
+}
+
+func TestWithGOOSCache(t *testing.T) {
+	if _, err := newTestGodoc(godoc.WithCacheMode(godoc.CacheMemory)).Load("syscall", "", ""); err != nil {
+		t.Fatalf("Failed to load syscall: %v", err)
+	}
+
+	// The docs of the default platform are not served for another one.
+	g := newTestGodoc(godoc.WithCacheMode(godoc.CacheMemory), godoc.WithGOOS("windows"))
+	result, err := g.Load("syscall", "", "")
+	if err != nil {
+		t.Fatalf("Failed to load syscall for windows: %v", err)
+	}
+
+	if !slices.ContainsFunc(result.(godoc.PackageDoc).Funcs, func(f godoc.FuncDoc) bool { return f.Name == "LoadDLL" }) {
+		t.Errorf("Expected syscall.LoadDLL in the windows docs")
+	}
+
+	if _, err := g.Load("syscall", "LoadDLL", ""); err != nil {
+		t.Errorf("Expected syscall.LoadDLL on windows, got %v", err)
+	}
+}
+
+func TestWithIncludePlatforms(t *testing.T) {
+	g := newTestGodoc(godoc.WithGOOS("linux"), godoc.WithGOARCH("amd64"), godoc.WithIncludePlatforms("linux/amd64", "darwin/arm64"))
+	result, err := g.Load("syscall", "Stat_t", "")
+	if err != nil {
+		t.Fatalf("Failed to load syscall.Stat_t: %v", err)
+	}
+
+	symDoc, ok := result.(godoc.SymbolDoc)
+	if !ok || symDoc.TypeDoc == nil {
+		t.Fatalf("Expected type SymbolDoc, got %T", result)
+	}
+
+	fields := make(map[string]godoc.FieldDoc, len(symDoc.Fields))
+	for _, f := range symDoc.Fields {
+		fields[f.Name+" "+f.Type] = f
+	}
+
+	if f, ok := fields["Ino uint64"]; !ok || f.Platforms != nil {
+		t.Errorf("Expected Ino on all platforms, got %+v", f)
+	}
+
+	// The type of Dev differs across platforms.
+	if f, ok := fields["Dev uint64"]; !ok || !slices.Equal(f.Platforms, []string{"linux/amd64"}) {
+		t.Errorf("Expected linux-only Dev uint64, got %+v", f)
+	}
+
+	if f, ok := fields["Dev int32"]; !ok || !slices.Equal(f.Platforms, []string{"darwin/arm64"}) {
+		t.Errorf("Expected darwin-only Dev int32, got %+v", f)
+	}
+
+	if f, ok := fields["Birthtimespec syscall.Timespec"]; !ok || !slices.Equal(f.Platforms, []string{"darwin/arm64"}) {
+		t.Errorf("Expected darwin-only Birthtimespec, got %+v", f)
+	}
+
+	if f, ok := fields["Atim syscall.Timespec"]; !ok || !slices.Equal(f.Platforms, []string{"linux/amd64"}) {
+		t.Errorf("Expected linux-only Atim, got %+v", f)
+	}
+
+	// Fields of named types declared everywhere are merged in package docs
+	// too.
+	result, err = g.Load("syscall", "", "")
+	if err != nil {
+		t.Fatalf("Failed to load syscall: %v", err)
+	}
+
+	for _, typ := range result.(godoc.PackageDoc).Types {
+		if typ.Name != "Rusage" {
+			continue
+		}
+
+		var utime []godoc.FieldDoc
+		for _, f := range typ.Fields {
+			if f.Name == "Utime" {
+				utime = append(utime, f)
+			}
+		}
+
+		if len(utime) != 1 || utime[0].Platforms != nil {
+			t.Errorf("Expected Utime once on all platforms, got %+v", utime)
+		}
+	}
+
+	g = newTestGodoc(godoc.WithIncludePlatforms("/amd64"))
+	if _, err := g.Load("syscall", "Stat_t", ""); !errors.Is(err, godoc.ErrInvalidPlatform) {
+		t.Errorf("Expected ErrInvalidPlatform, got %v", err)
+	}
+}
+
+func TestExamples(t *testing.T) {
+	g := newTestGodoc()
+	result, err := g.Load("strings", "Contains", "")
+	if err != nil {
+		t.Fatalf("Failed to load strings.Contains: %v", err)
+	}
+
+	examples := result.(godoc.SymbolDoc).Examples
+	if len(examples) != 1 || examples[0].Name != "Contains" {
+		t.Fatalf("Expected the Contains example, got %+v", examples)
+	}
+
+	ex := examples[0]
+	if !strings.HasPrefix(ex.Code, `fmt.Println(strings.Contains("seafood", "foo"))`) {
+		t.Errorf("Expected unindented example body, got %q", ex.Code)
+	}
+
+	if strings.Contains(ex.Code, "Output:") {
+		t.Errorf("Expected output comment to be split out of code, got %q", ex.Code)
+	}
+
+	if ex.Output != "true\nfalse\ntrue\ntrue\n" {
+		t.Errorf("Unexpected example output %q", ex.Output)
+	}
+
+	result, err = g.Load("strings", "", "")
+	if err != nil {
+		t.Fatalf("Failed to load strings: %v", err)
+	}
+
+	var names []string
+	for _, ex := range result.(godoc.PackageDoc).Examples {
+		names = append(names, ex.Name)
+	}
+
+	if !slices.Contains(names, "Contains") || !slices.Contains(names, "Builder") {
+		t.Errorf("Expected package examples to include Contains and Builder, got %v", names)
+	}
+
+	if !slices.IsSorted(names) {
+		t.Errorf("Expected package examples ordered by name, got %v", names)
+	}
+}
+
+func TestTypeParams(t *testing.T) {
+	g := newTestGodoc()
+	result, err := g.Load("slices", "Insert", "")
+	if err != nil {
+		t.Fatalf("Failed to load slices.Insert: %v", err)
+	}
+
+	symDoc := result.(godoc.SymbolDoc)
+	want := []godoc.ArgInfo{{Name: "S", Type: "~[]E"}, {Name: "E", Type: "any"}}
+	if !slices.Equal(symDoc.TypeParams, want) || !slices.Equal(symDoc.FuncDoc.TypeParams, want) {
+		t.Fatalf("Expected type params %v, got %v", want, symDoc.TypeParams)
+	}
+
+	result, err = g.Load("sync/atomic", "Pointer", "")
+	if err != nil {
+		t.Fatalf("Failed to load sync/atomic.Pointer: %v", err)
+	}
+
+	symDoc = result.(godoc.SymbolDoc)
+	want = []godoc.ArgInfo{{Name: "T", Type: "any"}}
+	if !slices.Equal(symDoc.TypeParams, want) || !slices.Equal(symDoc.TypeDoc.TypeParams, want) {
+		t.Fatalf("Expected type params %v, got %v", want, symDoc.TypeParams)
+	}
+
+	data, err := json.Marshal(symDoc)
+	if err != nil {
+		t.Fatalf("Failed to marshal symbol: %v", err)
+	}
+
+	if !strings.Contains(string(data), `"type_params":[{"name":"T","type":"any"}]`) {
+		t.Errorf("Expected type params in JSON, got %s", data)
+	}
+}
+
+func TestWithFilePattern(t *testing.T) {
+	g := newTestGodoc()
+	g.SetOptions(godoc.WithFilePattern("reader.go"))
+
+	result, err := g.Load("strings", "", "")
+	if err != nil {
+		t.Fatalf("Failed to load strings: %v", err)
+	}
+
+	pkgDoc := result.(godoc.PackageDoc)
+	if len(pkgDoc.Types) != 1 || pkgDoc.Types[0].Name != "Reader" {
+		t.Fatalf("Expected only the Reader type from reader.go, got %+v", pkgDoc.Types)
+	}
+
+	for _, f := range pkgDoc.Funcs {
+		if f.Name != "NewReader" {
+			t.Errorf("Expected only functions from reader.go, got %s", f.Name)
+		}
+	}
+
+	g.SetOptions(godoc.WithFilePattern("["))
+	if _, err := g.Load("strings", "", ""); !errors.Is(err, godoc.ErrInvalidFilePattern) {
+		t.Errorf("Expected ErrInvalidFilePattern, got %v", err)
+	}
+
+	g.SetOptions(godoc.WithFilePattern("*_plan9.go"))
+	if _, err := g.Load("strings", "", ""); err == nil {
+		t.Errorf("Expected an error when no file matches")
+	}
+}
+
+func TestWithUnexported(t *testing.T) {
+	g := newTestGodoc()
+	if _, err := g.Load("strings", "explode", ""); err == nil {
+		t.Fatalf("Expected unexported symbols to be hidden by default")
+	}
+
+	g = newTestGodoc(godoc.WithUnexported(true))
+	result, err := g.Load("strings", "explode", "")
+	if err != nil {
+		t.Fatalf("Failed to load unexported strings.explode: %v", err)
+	}
+
+	if symDoc := result.(godoc.SymbolDoc); symDoc.Kind != "func" {
+		t.Errorf("Expected func kind, got %q", symDoc.Kind)
+	}
+
+	result, err = g.Load("strings", "Builder", "")
+	if err != nil {
+		t.Fatalf("Failed to load strings.Builder: %v", err)
+	}
+
+	var names []string
+	for _, f := range result.(godoc.SymbolDoc).Fields {
+		names = append(names, f.Name)
+	}
+
+	if !slices.Contains(names, "buf") {
+		t.Errorf("Expected unexported field buf, got %v", names)
+	}
+}
+
+func TestSynopsis(t *testing.T) {
+	tests := []struct {
+		text, want string
+	}{
+		{"Printf formats according to a format specifier. It returns the count.", "Printf formats according to a format specifier."},
+		{"Line one\nwraps here.\n\nSecond paragraph.", "Line one wraps here."},
+		{"Copyright 2024 The Authors.", ""},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if got := godoc.Synopsis(tt.text); got != tt.want {
+			t.Errorf("Synopsis(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestTestFuncs(t *testing.T) {
+	g := newTestGodoc()
+	result, err := g.Load("strings", "", "")
+	if err != nil {
+		t.Fatalf("Failed to load strings: %v", err)
+	}
+
+	pkgDoc := result.(godoc.PackageDoc)
+	if !slices.Contains(pkgDoc.Tests, "TestIndex") {
+		t.Errorf("Expected TestIndex in tests, got %v", pkgDoc.Tests)
+	}
+
+	if !slices.Contains(pkgDoc.Benchmarks, "BenchmarkIndex") {
+		t.Errorf("Expected BenchmarkIndex in benchmarks, got %v", pkgDoc.Benchmarks)
+	}
+
+	if !slices.Contains(pkgDoc.Fuzzes, "FuzzReplace") {
+		t.Errorf("Expected FuzzReplace in fuzz tests, got %v", pkgDoc.Fuzzes)
+	}
+
+	for _, names := range [][]string{pkgDoc.Tests, pkgDoc.Benchmarks, pkgDoc.Fuzzes} {
+		if !slices.IsSorted(names) {
+			t.Errorf("Expected sorted names, got %v", names)
+		}
+	}
+}
+
+func TestFuncDecl(t *testing.T) {
+	g := newTestGodoc()
+	tests := []struct {
+		sel, want string
+	}{
+		{"Printf", "func Printf(format string, a ...any) (n int, err error)"},
+		{"Stringer.String", "func (Stringer) String() string"},
+	}
+
+	for _, tt := range tests {
+		result, err := g.Load("fmt", tt.sel, "")
+		if err != nil {
+			t.Fatalf("Failed to load fmt.%s: %v", tt.sel, err)
+		}
+
+		symDoc := result.(godoc.SymbolDoc)
+		if symDoc.Decl != tt.want || symDoc.FuncDoc.Decl != tt.want {
+			t.Errorf("Expected fmt.%s decl %q, got %q", tt.sel, tt.want, symDoc.Decl)
+		}
+	}
+
+	result, err := g.Load("bytes", "", "")
+	if err != nil {
+		t.Fatalf("Failed to load bytes: %v", err)
+	}
+
+	for _, f := range result.(godoc.PackageDoc).Funcs {
+		if f.Name == "NewBuffer" && f.Decl != "func NewBuffer(buf []byte) *Buffer" {
+			t.Errorf("Unexpected NewBuffer decl %q", f.Decl)
+		}
+	}
+
+	result, err = g.Load("bytes", "Buffer.Write", "")
+	if err != nil {
+		t.Fatalf("Failed to load bytes.Buffer.Write: %v", err)
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		t.Fatalf("Failed to marshal symbol: %v", err)
+	}
+
+	if !strings.Contains(string(data), `"decl":"func (b *Buffer) Write(p []byte) (n int, err error)"`) {
+		t.Errorf("Expected method decl in JSON, got %s", data)
+	}
+}
+
+func TestWithPrettyTypes(t *testing.T) {
+	handlerType := func(g testGodoc) string {
+		t.Helper()
+
+		result, err := g.Load("net/http", "HandleFunc", "")
+		if err != nil {
+			t.Fatalf("Failed to load net/http.HandleFunc: %v", err)
+		}
+
+		args := result.(godoc.SymbolDoc).Args
+		if len(args) != 2 {
+			t.Fatalf("Expected 2 args, got %v", args)
+		}
+
+		return args[1].Type
+	}
+
+	if got, want := handlerType(newTestGodoc()), "func(net/http.ResponseWriter, *net/http.Request)"; got != want {
+		t.Errorf("Expected default type %q, got %q", want, got)
+	}
+
+	g := newTestGodoc(godoc.WithPrettyTypes(true))
+	if got, want := handlerType(g), "func(http.ResponseWriter, *http.Request)"; got != want {
+		t.Errorf("Expected pretty type %q, got %q", want, got)
+	}
+
+	result, err := g.Load("net/http", "Client", "")
+	if err != nil {
+		t.Fatalf("Failed to load net/http.Client: %v", err)
+	}
+
+	for _, f := range result.(godoc.SymbolDoc).TypeDoc.Fields {
+		if f.Name == "CheckRedirect" && f.Type != "func(*http.Request, []*http.Request) error" {
+			t.Errorf("Unexpected CheckRedirect type %q", f.Type)
+		}
+	}
+}
+
+func TestSymbolPos(t *testing.T) {
+	g := newTestGodoc()
+	tests := []struct {
+		pkg, sel, file, name string
+	}{
+		{"strings", "Builder", "builder.go", "Builder"},
+		{"strings", "Builder.WriteString", "builder.go", "WriteString"},
+		{"strings", "NewReader", "reader.go", "NewReader"},
+		{"io", "Reader.Read", "io.go", "Read"},
+		{"io", "SeekEnd", "io.go", "SeekEnd"},
+		{"io", "EOF", "io.go", "EOF"},
+	}
+
+	for _, tt := range tests {
+		result, err := g.Load(tt.pkg, tt.sel, "")
+		if err != nil {
+			t.Fatalf("Failed to load %s.%s: %v", tt.pkg, tt.sel, err)
+		}
+
+		pos := result.(godoc.SymbolDoc).Pos
+		if pos == nil {
+			t.Errorf("Expected position for %s.%s", tt.pkg, tt.sel)
+			continue
+		}
+
+		if filepath.Base(pos.File) != tt.file {
+			t.Errorf("Expected %s.%s in %s, got %s", tt.pkg, tt.sel, tt.file, pos.File)
+			continue
+		}
+
+		data, err := os.ReadFile(pos.File)
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", pos.File, err)
+		}
+
+		lines := strings.Split(string(data), "\n")
+		if pos.Line < 1 || pos.Line > len(lines) {
+			t.Errorf("Line %d of %s.%s out of range", pos.Line, tt.pkg, tt.sel)
+			continue
+		}
+
+		if line := lines[pos.Line-1]; !strings.HasPrefix(line[pos.Column-1:], tt.name) {
+			t.Errorf("Expected %s at %s:%d:%d, got line %q", tt.name, pos.File, pos.Line, pos.Column, line)
+		}
+	}
+}
+
+func TestModuleInfo(t *testing.T) {
+	g := newTestGodoc()
+
+	mod, err := g.ModuleInfo(".", "")
+	if err != nil {
+		t.Fatalf("Failed to read main module: %v", err)
+	}
+
+	if mod.Path != "go.dw1.io/godoc" || mod.Version != "" || mod.GoVersion == "" {
+		t.Errorf("Unexpected main module %+v", mod)
+	}
+
+	var xmod godoc.ModuleRequire
+	for _, r := range mod.Requires {
+		if r.Path == "golang.org/x/mod" {
+			xmod = r
+		}
+	}
+
+	if xmod.Version == "" || xmod.Indirect {
+		t.Fatalf("Expected direct golang.org/x/mod requirement, got %+v", mod.Requires)
+	}
+
+	// The version required by go.mod is already in the module cache.
+	dep, err := g.ModuleInfo("golang.org/x/mod", "")
+	if err != nil {
+		t.Fatalf("Failed to load golang.org/x/mod: %v", err)
+	}
+
+	if dep.Path != "golang.org/x/mod" || dep.Version != xmod.Version || dep.GoVersion == "" {
+		t.Errorf("Unexpected module %+v", dep)
+	}
+
+	if _, err := g.ModuleInfo("", ""); !errors.Is(err, godoc.ErrEmptyImportPath) {
+		t.Errorf("Expected ErrEmptyImportPath, got %v", err)
+	}
+}
+
+func TestLoadMultiple(t *testing.T) {
+	g := newTestGodoc()
+	requests := []godoc.LoadRequest{
+		{ImportPath: "fmt"},
+		{ImportPath: "strings", Sel: "Builder.WriteString"},
+		{ImportPath: "fmt", Sel: "1Invalid"},
+		{ImportPath: "io", Sel: "Reader"},
+	}
+
+	results, errs := g.LoadMultiple(requests)
+	if len(results) != len(requests) || len(errs) != len(requests) {
+		t.Fatalf("Expected %d results and errors, got %d and %d", len(requests), len(results), len(errs))
+	}
+
+	if !errors.Is(errs[2], godoc.ErrInvalidSelector) || results[2] != nil {
+		t.Errorf("Expected ErrInvalidSelector for the invalid request, got %v", errs[2])
+	}
+
+	for _, i := range []int{0, 1, 3} {
+		if errs[i] != nil {
+			t.Fatalf("Request %d failed: %v", i, errs[i])
+		}
+	}
+
+	if pkgDoc, ok := results[0].(godoc.PackageDoc); !ok || pkgDoc.ImportPath != "fmt" {
+		t.Errorf("Unexpected result for fmt: %#v", results[0])
+	}
+
+	if symDoc, ok := results[1].(godoc.SymbolDoc); !ok || symDoc.Name != "WriteString" {
+		t.Errorf("Unexpected result for strings.Builder.WriteString: %#v", results[1])
+	}
+
+	if symDoc, ok := results[3].(godoc.SymbolDoc); !ok || symDoc.Name != "Reader" {
+		t.Errorf("Unexpected result for io.Reader: %#v", results[3])
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	g = newTestGodoc(godoc.WithContext(ctx))
+	_, errs = g.LoadMultiple(requests)
+	for i, err := range errs {
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected request %d to be canceled, got %v", i, err)
+		}
+	}
+}
+
+func TestListSymbols(t *testing.T) {
+	g := newTestGodoc()
+	refs, err := g.ListSymbols("strings", "")
+	if err != nil {
+		t.Fatalf("Failed to list strings symbols: %v", err)
+	}
+
+	if !slices.IsSortedFunc(refs, func(a, b godoc.SymbolRef) int { return strings.Compare(a.Name, b.Name) }) {
+		t.Errorf("Expected symbols ordered by name")
+	}
+
+	want := []godoc.SymbolRef{
+		{Name: "Builder", Kind: "type"},
+		{Name: "Builder.WriteString", Kind: "method", Receiver: "Builder"},
+		{Name: "NewReader", Kind: "func"},
+		{Name: "Reader.NewReader", Kind: "func"},
+		{Name: "Index", Kind: "func"},
+	}
+	for _, w := range want {
+		if !slices.Contains(refs, w) {
+			t.Errorf("Expected %+v in symbol list", w)
+		}
+
+		if _, err := g.Load("strings", w.Name, ""); err != nil {
+			t.Errorf("Listed symbol %q cannot be loaded: %v", w.Name, err)
+		}
+	}
+
+	if _, err := g.ListSymbols("", ""); !errors.Is(err, godoc.ErrEmptyImportPath) {
+		t.Errorf("Expected ErrEmptyImportPath, got %v", err)
+	}
+}
+
+func TestDeprecated(t *testing.T) {
+	g := newTestGodoc()
+
+	result, err := g.Load("strings", "Title", "")
+	if err != nil {
+		t.Fatalf("Failed to load strings.Title: %v", err)
+	}
+
+	symDoc := result.(godoc.SymbolDoc)
+	if !symDoc.Deprecated || !symDoc.FuncDoc.Deprecated || !strings.HasPrefix(symDoc.DeprecatedNote, "The rule Title uses") {
+		t.Errorf("Expected strings.Title to be deprecated, got %v %q", symDoc.Deprecated, symDoc.DeprecatedNote)
+	}
+
+	data, err := json.Marshal(symDoc)
+	if err != nil {
+		t.Fatalf("Failed to marshal symbol: %v", err)
+	}
+
+	if !strings.Contains(string(data), `"deprecated":true`) {
+		t.Errorf("Expected deprecated flag in JSON, got %s", data)
+	}
+
+	result, err = g.Load("strings", "Builder", "")
+	if err != nil {
+		t.Fatalf("Failed to load strings.Builder: %v", err)
+	}
+
+	if symDoc := result.(godoc.SymbolDoc); symDoc.Deprecated || symDoc.DeprecatedNote != "" {
+		t.Errorf("Expected strings.Builder not to be deprecated")
+	}
+
+	result, err = g.Load("reflect", "", "")
+	if err != nil {
+		t.Fatalf("Failed to load reflect: %v", err)
+	}
+
+	for _, typ := range result.(godoc.PackageDoc).Types {
+		if typ.Name == "SliceHeader" && !typ.Deprecated {
+			t.Errorf("Expected reflect.SliceHeader to be deprecated")
+		}
+	}
+
+	result, err = g.Load("net/http", "Transport", "")
+	if err != nil {
+		t.Fatalf("Failed to load net/http.Transport: %v", err)
+	}
+
+	for _, m := range result.(godoc.SymbolDoc).Methods {
+		if m.Name == "CancelRequest" && (!m.Deprecated || m.DeprecatedNote == "") {
+			t.Errorf("Expected Transport.CancelRequest to be deprecated, got %+v", m)
+		}
+	}
+}
+
+func TestListVersions(t *testing.T) {
+	proxy := t.TempDir()
+	files := map[string]string{
+		"list":         "v1.1.0\nv1.0.0\nv1.10.0\n",
+		"v1.10.0.info": `{"Version":"v1.10.0"}`,
+		"v1.10.0.mod":  "module example.com/mod\n",
+	}
+
+	dir := filepath.Join(proxy, "example.com", "mod", "@v")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	t.Setenv("GOPROXY", "file://"+filepath.ToSlash(proxy))
+
+	g := newTestGodoc(godoc.WithSumDB("off"))
+
+	versions, err := g.ListVersions("example.com/mod")
+	if err != nil {
+		t.Fatalf("Failed to list versions: %v", err)
+	}
+
+	if want := []string{"v1.0.0", "v1.1.0", "v1.10.0"}; !slices.Equal(versions, want) {
+		t.Errorf("Expected versions %v, got %v", want, versions)
+	}
+
+	if _, err := g.ListVersions("example.com/missing"); err == nil {
+		t.Errorf("Expected error for unknown module")
+	}
+
+	if _, err := g.ListVersions(""); !errors.Is(err, godoc.ErrEmptyImportPath) {
+		t.Errorf("Expected ErrEmptyImportPath, got %v", err)
+	}
+}
+
+func TestWithInlineTypes(t *testing.T) {
+	inlined := func(g testGodoc, sel string) []string {
+		t.Helper()
+
+		res, err := g.Load("bufio", sel, "")
+		if err != nil {
+			t.Fatalf("Failed to load bufio.%s: %v", sel, err)
+		}
+
+		var names []string
+		for _, typ := range res.(godoc.SymbolDoc).InlineTypes {
+			if typ.Decl == "" {
+				t.Errorf("Expected declaration of inlined type %s", typ.Name)
+			}
+
+			names = append(names, typ.Name)
+		}
+
+		return names
+	}
+
+	if got := inlined(newTestGodoc(), "NewReadWriter"); got != nil {
+		t.Errorf("Expected no inlined types by default, got %v", got)
+	}
+
+	g := newTestGodoc(godoc.WithInlineTypes(1))
+	tests := map[string][]string{
+		"NewReadWriter": {"Reader", "Writer", "ReadWriter"},
+		"ReadWriter":    {"Reader", "Writer"},
+		"NewScanner":    {"Scanner"},
+	}
+
+	for sel, want := range tests {
+		if got := inlined(g, sel); !slices.Equal(got, want) {
+			t.Errorf("Expected %s to inline %v, got %v", sel, want, got)
+		}
+	}
+
+	res, err := newTestGodoc(godoc.WithInlineTypes(2)).Load("go/ast", "NewIdent", "")
+	if err != nil {
+		t.Fatalf("Failed to load go/ast.NewIdent: %v", err)
+	}
+
+	var got []string
+	for _, typ := range res.(godoc.SymbolDoc).InlineTypes {
+		got = append(got, typ.Name)
+	}
+
+	// NewIdent returns an *Ident, whose Obj field is an *Object.
+	if want := []string{"Ident", "Object"}; !slices.Equal(got, want) {
+		t.Errorf("Expected NewIdent to inline %v at depth 2, got %v", want, got)
+	}
+}
+
+func TestInterfaceEmbeds(t *testing.T) {
+	g := newTestGodoc()
+
+	res, err := g.Load("io", "ReadCloser", "")
+	if err != nil {
+		t.Fatalf("Failed to load io.ReadCloser: %v", err)
+	}
+
+	sym := res.(godoc.SymbolDoc)
+	if want := []string{"Reader", "Closer"}; !slices.Equal(sym.Embeds, want) {
+		t.Errorf("Expected embeds %v, got %v", want, sym.Embeds)
+	}
+
+	// The method set stays flattened.
+	var methods []string
+	for _, m := range sym.Methods {
+		methods = append(methods, m.Name)
+	}
+
+	if want := []string{"Close", "Read"}; !slices.Equal(methods, want) {
+		t.Errorf("Expected methods %v, got %v", want, methods)
+	}
+
+	res, err = g.Load("io", "Reader", "")
+	if err != nil {
+		t.Fatalf("Failed to load io.Reader: %v", err)
+	}
+
+	if embeds := res.(godoc.SymbolDoc).Embeds; embeds != nil {
+		t.Errorf("Expected no embeds for io.Reader, got %v", embeds)
+	}
+}
+
+func TestWithSymbolMatch(t *testing.T) {
+	name := func(res godoc.Result) string {
+		return res.(godoc.SymbolDoc).Name
+	}
+
+	if _, err := newTestGodoc().Load("strings", "cut", ""); !errors.Is(err, godoc.ErrSymbolNotFound) {
+		t.Errorf("Expected ErrSymbolNotFound for strings.cut by default, got %v", err)
+	}
+
+	g := newTestGodoc(godoc.WithSymbolMatch(godoc.MatchCaseInsensitive))
+	if res, err := g.Load("strings", "cut", ""); err != nil || name(res) != "Cut" {
+		t.Errorf("Expected strings.cut to select Cut, got %v", err)
+	}
+
+	if _, err := g.Load("strings", "newrepl", ""); !errors.Is(err, godoc.ErrSymbolNotFound) {
+		t.Errorf("Expected ErrSymbolNotFound for a prefix without MatchPrefix, got %v", err)
+	}
+
+	g = newTestGodoc(godoc.WithSymbolMatch(godoc.MatchPrefix))
+	if res, err := g.Load("strings", "newrepl", ""); err != nil || name(res) != "NewReplacer" {
+		t.Errorf("Expected strings.newrepl to select NewReplacer, got %v", err)
+	}
+
+	if res, err := g.Load("strings", "CUT", ""); err != nil || name(res) != "Cut" {
+		t.Errorf("Expected case-insensitive match to take precedence over prefixes, got %v", err)
+	}
+
+	_, err := g.Load("strings", "cu", "")
+
+	var ambiguous *godoc.AmbiguousSymbolError
+	if !errors.As(err, &ambiguous) || !errors.Is(err, godoc.ErrAmbiguousSymbol) {
+		t.Fatalf("Expected AmbiguousSymbolError for strings.cu, got %v", err)
+	}
+
+	if want := []string{"Cut", "CutPrefix", "CutSuffix"}; !slices.Equal(ambiguous.Candidates, want) {
+		t.Errorf("Expected candidates %v, got %v", want, ambiguous.Candidates)
+	}
+}
+
+func TestPackageNotes(t *testing.T) {
+	g := newTestGodoc()
+
+	res, err := g.Load("unicode", "", "")
+	if err != nil {
+		t.Fatalf("Failed to load unicode: %v", err)
+	}
+
+	bugs := res.(godoc.PackageDoc).Notes["BUG"]
+	if len(bugs) == 0 {
+		t.Fatal("Expected BUG notes for unicode")
+	}
+
+	if bugs[0].UID == "" || !strings.Contains(bugs[0].Body, "case folding") {
+		t.Errorf("Unexpected BUG note: %+v", bugs[0])
+	}
+
+	res, err = g.Load("errors", "", "")
+	if err != nil {
+		t.Fatalf("Failed to load errors: %v", err)
+	}
+
+	if notes := res.(godoc.PackageDoc).Notes; notes != nil {
+		t.Errorf("Expected no notes for errors, got %v", notes)
+	}
+}
+
+func TestRecvPointer(t *testing.T) {
+	g := newTestGodoc()
+
+	res, err := g.Load("net/http", "Client.Do", "")
+	if err != nil {
+		t.Fatalf("Failed to load net/http.Client.Do: %v", err)
+	}
+
+	if sym := res.(godoc.SymbolDoc); !sym.RecvPointer {
+		t.Errorf("Expected a pointer receiver for Client.Do, got %+v", sym)
+	}
+
+	res, err = g.Load("time", "Time", "")
+	if err != nil {
+		t.Fatalf("Failed to load time.Time: %v", err)
+	}
+
+	for _, m := range res.(godoc.SymbolDoc).Methods {
+		switch m.Name {
+		case "Unix":
+			if m.RecvPointer {
+				t.Errorf("Expected a value receiver for Time.Unix")
+			}
+		case "UnmarshalJSON":
+			if !m.RecvPointer {
+				t.Errorf("Expected a pointer receiver for Time.UnmarshalJSON")
+			}
+		}
+	}
+}
+
+func TestArgInfoRef(t *testing.T) {
+	g := newTestGodoc()
+
+	res, err := g.Load("bufio", "NewReader", "")
+	if err != nil {
+		t.Fatalf("Failed to load bufio.NewReader: %v", err)
+	}
+
+	sym := res.(godoc.SymbolDoc)
+	if ref := sym.Args[0].Ref; ref == nil || *ref != (godoc.TypeRef{ImportPath: "io", Name: "Reader"}) {
+		t.Errorf("Expected argument ref io.Reader, got %+v", ref)
+	}
+
+	if ref := sym.Returns[0].Ref; ref == nil || *ref != (godoc.TypeRef{ImportPath: "bufio", Name: "Reader"}) {
+		t.Errorf("Expected result ref bufio.Reader, got %+v", ref)
+	}
+
+	res, err = g.Load("strings", "Join", "")
+	if err != nil {
+		t.Fatalf("Failed to load strings.Join: %v", err)
+	}
+
+	for _, arg := range res.(godoc.SymbolDoc).Args {
+		if arg.Ref != nil {
+			t.Errorf("Expected no ref for %s %s, got %+v", arg.Name, arg.Type, arg.Ref)
+		}
+	}
+}
+
+func TestPromotedMethods(t *testing.T) {
+	g := newTestGodoc()
+
+	res, err := g.Load("bufio", "ReadWriter", "")
+	if err != nil {
+		t.Fatalf("Failed to load bufio.ReadWriter: %v", err)
+	}
+
+	methods := make(map[string]godoc.MethodDoc)
+	for _, m := range res.(godoc.SymbolDoc).Methods {
+		methods[m.Name] = m
+	}
+
+	m, ok := methods["ReadString"]
+	if !ok {
+		t.Fatalf("Expected promoted method ReadString, got %v", methods)
+	}
+
+	if !m.Promoted || m.PromotedFrom != "bufio.Reader" || m.RecvType != "ReadWriter" || m.RecvPointer {
+		t.Errorf("Unexpected promoted method: %+v", m)
+	}
+
+	if m, ok := methods["WriteString"]; !ok || m.PromotedFrom != "bufio.Writer" {
+		t.Errorf("Expected WriteString promoted from bufio.Writer, got %+v", m)
+	}
+
+	// Buffered is declared by both embedded types, so it is not promoted.
+	if _, ok := methods["Buffered"]; ok {
+		t.Errorf("Expected ambiguous Buffered not to be promoted")
+	}
+
+	res, err = g.Load("bufio", "ReadWriter.ReadString", "")
+	if err != nil {
+		t.Fatalf("Failed to load bufio.ReadWriter.ReadString: %v", err)
+	}
+
+	if decl := res.(godoc.SymbolDoc).Decl; decl != "func (ReadWriter) ReadString(delim byte) (string, error)" {
+		t.Errorf("Unexpected declaration of the promoted method: %q", decl)
+	}
+
+	res, err = g.Load("bufio", "Reader", "")
+	if err != nil {
+		t.Fatalf("Failed to load bufio.Reader: %v", err)
+	}
+
+	for _, m := range res.(godoc.SymbolDoc).Methods {
+		if m.Promoted {
+			t.Errorf("Expected no promoted methods for bufio.Reader, got %s", m.Name)
+		}
+	}
+
+	// Package docs list the promoted methods too.
+	res, err = g.Load("bufio", "", "")
+	if err != nil {
+		t.Fatalf("Failed to load bufio: %v", err)
+	}
+
+	for _, typ := range res.(godoc.PackageDoc).Types {
+		if typ.Name != "ReadWriter" {
+			continue
+		}
+
+		if !slices.ContainsFunc(typ.Methods, func(m godoc.MethodDoc) bool { return m.Name == "ReadString" && m.Promoted }) {
+			t.Errorf("Expected promoted method ReadString in the package docs, got %+v", typ.Methods)
+		}
+	}
+}
+
+func TestPromotedMethodsAfterPackageLoad(t *testing.T) {
+	// Test files key the cache apart from the docs loaded by other tests.
+	g := newTestGodoc(godoc.WithCacheMode(godoc.CacheMemory), godoc.WithTestFiles(true))
+
+	// The package is loaded first, so that its documentation is cached
+	// before the promoted method is looked up.
+	if _, err := g.Load("bufio", "", ""); err != nil {
+		t.Fatalf("Failed to load bufio: %v", err)
+	}
+
+	res, err := g.Load("bufio", "ReadWriter.ReadString", "")
+	if err != nil {
+		t.Fatalf("Failed to load bufio.ReadWriter.ReadString after bufio: %v", err)
+	}
+
+	if sym := res.(godoc.SymbolDoc); sym.Kind != "method" || sym.Receiver != "ReadWriter" {
+		t.Errorf("Unexpected promoted method: %+v", sym)
+	}
+}
+
+func TestWithSortMode(t *testing.T) {
+	res, err := newTestGodoc().Load("errors", "", "")
+	if err != nil {
+		t.Fatalf("Failed to load errors: %v", err)
+	}
+
+	funcNames := func(res godoc.Result) []string {
+		var names []string
+		for _, f := range res.(godoc.PackageDoc).Funcs {
+			names = append(names, f.Name)
+		}
+
+		return names
+	}
+
+	if got, want := funcNames(res), []string{"As", "Is", "Join", "New", "Unwrap"}; !slices.Equal(got, want) {
+		t.Errorf("Expected alphabetical funcs %v by default, got %v", want, got)
+	}
+
+	res, err = newTestGodoc(godoc.WithSortMode(godoc.SortSource)).Load("errors", "", "")
+	if err != nil {
+		t.Fatalf("Failed to load errors in source order: %v", err)
+	}
+
+	// errors.go, join.go, then wrap.go.
+	if got, want := funcNames(res), []string{"New", "Join", "Unwrap", "Is", "As"}; !slices.Equal(got, want) {
+		t.Errorf("Expected funcs %v in source order, got %v", want, got)
+	}
+}
+
+func TestWithImplements(t *testing.T) {
+	res, err := newTestGodoc(godoc.WithImplements(true)).Load("io", "", "")
+	if err != nil {
+		t.Fatalf("Failed to load io: %v", err)
+	}
+
+	typeDocs := make(map[string]godoc.TypeDoc)
+	for _, td := range res.(godoc.PackageDoc).Types {
+		typeDocs[td.Name] = td
+	}
+
+	if got := typeDocs["Reader"].ImplementedBy; !slices.Contains(got, "*LimitedReader") || !slices.Contains(got, "*SectionReader") {
+		t.Errorf("Expected io.Reader to be implemented by *LimitedReader and *SectionReader, got %v", got)
+	}
+
+	if got := typeDocs["SectionReader"].Implements; !slices.Contains(got, "ReaderAt") || !slices.Contains(got, "Seeker") {
+		t.Errorf("Expected io.SectionReader to implement ReaderAt and Seeker, got %v", got)
+	}
+
+	res, err = newTestGodoc().Load("io", "Reader", "")
+	if err != nil {
+		t.Fatalf("Failed to load io.Reader: %v", err)
+	}
+
+	if td := res.(godoc.SymbolDoc).TypeDoc; td == nil || td.ImplementedBy != nil {
+		t.Errorf("Expected no relations without WithImplements, got %+v", td)
+	}
+}
+
+func TestSearch(t *testing.T) {
+	g := godoc.New()
+
+	refs, err := g.Search("encoding/json", "marshal", "")
+	if err != nil {
+		t.Fatalf("Failed to search encoding/json: %v", err)
+	}
+
+	byName := make(map[string]godoc.SymbolRef)
+	for _, ref := range refs {
+		byName[ref.Name] = ref
+	}
+
+	if ref := byName["Marshal"]; ref.Kind != "func" || ref.Snippet != "Marshal returns the JSON encoding of v." {
+		t.Errorf("Expected Marshal to match by name with its synopsis, got %+v", ref)
+	}
+
+	// Decoder.Decode only mentions Unmarshal in its documentation, so it
+	// comes after the name matches, with the matching line as snippet.
+	ref, ok := byName["Decoder.Decode"]
+	if !ok || !strings.Contains(strings.ToLower(ref.Snippet), "marshal") {
+		t.Errorf("Expected Decoder.Decode to match by documentation, got %+v", ref)
+	}
+
+	index := func(name string) int {
+		return slices.IndexFunc(refs, func(r godoc.SymbolRef) bool { return r.Name == name })
+	}
+
+	if index("Decoder.Decode") < index("Unmarshal") {
+		t.Errorf("Expected name matches before documentation matches, got %v", refs)
+	}
+
+	refs, err = g.Search("bufio", "/^New/", "")
+	if err != nil {
+		t.Fatalf("Failed to search bufio with a regular expression: %v", err)
+	}
+
+	if !slices.ContainsFunc(refs, func(r godoc.SymbolRef) bool { return r.Name == "Reader.NewReader" }) {
+		t.Errorf("Expected /^New/ to match Reader.NewReader by name, got %v", refs)
+	}
+
+	for _, query := range []string{"", "/(/"} {
+		if _, err := g.Search("bufio", query, ""); !errors.Is(err, godoc.ErrInvalidSearch) {
+			t.Errorf("Expected ErrInvalidSearch for query %q, got %v", query, err)
+		}
+	}
+}
+
+func TestFieldDocHTML(t *testing.T) {
+	res, err := newTestGodoc().Load("net/http", "Cookie", "")
+	if err != nil {
+		t.Fatalf("Failed to load net/http.Cookie: %v", err)
+	}
+
+	td := res.(godoc.SymbolDoc).TypeDoc
+	if td == nil {
+		t.Fatalf("Expected type documentation for net/http.Cookie")
+	}
+
+	for _, f := range td.Fields {
+		if f.Doc == "" {
+			continue
+		}
+
+		// The HTML of cached symbols is rendered before they are stored.
+		if f.DocHTML == "" || f.HTML() != f.DocHTML || !strings.HasPrefix(f.DocHTML, "<p>") {
+			t.Errorf("Expected HTML documentation of field %s, got %q", f.Name, f.DocHTML)
+		}
+	}
+}
+
+func TestTypeAlias(t *testing.T) {
+	g := newTestGodoc()
+
+	res, err := g.Load("os", "PathError", "")
+	if err != nil {
+		t.Fatalf("Failed to load os.PathError: %v", err)
+	}
+
+	sym := res.(godoc.SymbolDoc)
+	if !sym.IsAlias || sym.AliasTarget != "fs.PathError" {
+		t.Errorf("Expected os.PathError to be an alias of fs.PathError, got IsAlias=%v AliasTarget=%q", sym.IsAlias, sym.AliasTarget)
+	}
+
+	res, err = g.Load("os", "File", "")
+	if err != nil {
+		t.Fatalf("Failed to load os.File: %v", err)
+	}
+
+	if sym := res.(godoc.SymbolDoc); sym.IsAlias || sym.AliasTarget != "" {
+		t.Errorf("Expected os.File not to be an alias, got IsAlias=%v AliasTarget=%q", sym.IsAlias, sym.AliasTarget)
+	}
+}
+
+func TestWithHeadingLevel(t *testing.T) {
+	for _, tt := range []struct {
+		base int
+		want string
+	}{
+		{0, `<h2 id="hdr-Printing">`},
+		{4, `<h4 id="hdr-Printing">`},
+		{6, `<h2 id="hdr-Printing">`},
+	} {
+		res, err := newTestGodoc(godoc.WithHeadingLevel(tt.base)).Load("fmt", "", "")
+		if err != nil {
+			t.Fatalf("Failed to load fmt: %v", err)
+		}
+
+		if html := res.HTML(); !strings.Contains(html, tt.want) {
+			t.Errorf("Expected HTML with base level %d to contain %q", tt.base, tt.want)
+		}
+	}
+}
+
+func TestLoadMethodWithoutReceiver(t *testing.T) {
+	g := newTestGodoc()
+
+	res, err := g.Load("bytes", "Grow", "")
+	if err != nil {
+		t.Fatalf("Failed to load bytes.Grow: %v", err)
+	}
+
+	if sym := res.(godoc.SymbolDoc); sym.Kind != "method" || sym.Receiver != "Buffer" || sym.Name != "Grow" {
+		t.Errorf("Expected Grow to select Buffer.Grow, got %s %s.%s", sym.Kind, sym.Receiver, sym.Name)
+	}
+
+	// A function of the same name takes precedence over the method.
+	res, err = g.Load("time", "Unix", "")
+	if err != nil {
+		t.Fatalf("Failed to load time.Unix: %v", err)
+	}
+
+	if sym := res.(godoc.SymbolDoc); sym.Kind != "func" {
+		t.Errorf("Expected Unix to select the function rather than Time.Unix, got %s", sym.Kind)
+	}
+
+	_, err = g.Load("bytes", "Len", "")
+
+	var ambiguous *godoc.AmbiguousSymbolError
+	if !errors.As(err, &ambiguous) {
+		t.Fatalf("Expected AmbiguousSymbolError for bytes.Len, got %v", err)
+	}
+
+	if want := []string{"Buffer.Len", "Reader.Len"}; !slices.Equal(ambiguous.Candidates, want) {
+		t.Errorf("Expected candidates %v, got %v", want, ambiguous.Candidates)
+	}
+}
+
+func TestWithResolveDocLinks(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod": "module example.com/links\n\ngo 1.22\n",
+		"links.go": `// Package links reads from an [io.Reader] into a [Buffer].
+package links
+
+import "io"
+
+// Buffer holds bytes read with [Buffer.Fill], see [io.ReadAll].
+type Buffer struct {
+	// Src is read by [Buffer.Fill] and [io.ReadAll].
+	Src io.Reader
+}
+
+// Fill reads from a [Buffer] made by [New] until [io.EOF].
+func (b *Buffer) Fill() error { return nil }
+
+// New returns a [Buffer] reading from [io.Reader] r.
+func New(r io.Reader) *Buffer { return &Buffer{Src: r} }
+`,
+	}
+
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+
+	g := godoc.New(godoc.WithWorkdir(dir), godoc.WithCacheMode(godoc.CacheDisabled), godoc.WithResolveDocLinks(true))
+
+	res, err := g.Load("example.com/links", "", "")
+	if err != nil {
+		t.Fatalf("Failed to load package: %v", err)
+	}
+
+	pkg := res.(godoc.PackageDoc)
+	want := []godoc.DocLink{
+		{Text: "io.Reader", Target: "https://pkg.go.dev/io#Reader", ImportPath: "io", Name: "Reader"},
+		{Text: "Buffer", Target: "https://pkg.go.dev/example.com/links#Buffer", ImportPath: "example.com/links", Name: "Buffer"},
+	}
+	if !slices.Equal(pkg.DocLinks, want) {
+		t.Errorf("Expected package doc links %+v, got %+v", want, pkg.DocLinks)
+	}
+
+	if len(pkg.Types) != 1 || len(pkg.Types[0].Fields) != 1 || len(pkg.Types[0].Methods) != 1 {
+		t.Fatalf("Expected Buffer with one field and one method, got %+v", pkg.Types)
+	}
+
+	buf := pkg.Types[0]
+	if got := buf.Fields[0].DocLinks; len(got) != 2 || got[1].Target != "https://pkg.go.dev/io#ReadAll" {
+		t.Errorf("Expected field doc links to Buffer.Fill and io.ReadAll, got %+v", got)
+	}
+
+	if got := buf.Methods[0].DocLinks; len(got) != 3 || got[1].Name != "New" || got[1].Target != "https://pkg.go.dev/example.com/links#New" {
+		t.Errorf("Expected method doc links to Buffer, New, and io.EOF, got %+v", got)
+	}
+
+	res, err = g.Load("example.com/links", "New", "")
+	if err != nil {
+		t.Fatalf("Failed to load New: %v", err)
+	}
+
+	data, err := json.Marshal(res)
+	if err != nil {
+		t.Fatalf("Failed to marshal New: %v", err)
+	}
+
+	if !strings.Contains(string(data), `"doc_links":[{"text":"Buffer","target":"https://pkg.go.dev/example.com/links#Buffer"`) {
+		t.Errorf("Expected the JSON of New to include its doc links, got %s", data)
+	}
+
+	g.SetOptions(godoc.WithResolveDocLinks(false))
+
+	plain, err := g.Load("example.com/links", "", "")
+	if err != nil {
+		t.Fatalf("Failed to load package: %v", err)
+	}
+
+	if links := plain.(godoc.PackageDoc).DocLinks; links != nil {
+		t.Errorf("Expected no doc links by default, got %+v", links)
+	}
+}
+
+func TestWithSynopsisOnly(t *testing.T) {
+	g := newTestGodoc(godoc.WithSynopsisOnly(true))
+
+	res, err := g.Load("strings", "", "")
+	if err != nil {
+		t.Fatalf("Failed to load strings: %v", err)
+	}
+
+	pkg := res.(godoc.PackageDoc)
+	if pkg.Synopsis == "" {
+		t.Error("Expected the synopsis to be kept")
+	}
+
+	if pkg.DocText != "" || pkg.HTML() != "" {
+		t.Errorf("Expected no package documentation, got %q", pkg.DocText)
+	}
+
+	if len(pkg.Examples) > 0 || len(pkg.Tests) > 0 {
+		t.Errorf("Expected no examples or tests, got %d examples and %d tests", len(pkg.Examples), len(pkg.Tests))
+	}
+
+	for _, f := range pkg.Funcs {
+		if f.Doc != "" || f.Decl == "" {
+			t.Errorf("Expected %s to have a declaration but no doc, got %+v", f.Name, f)
+		}
+	}
+
+	for _, typ := range pkg.Types {
+		if typ.Doc != "" {
+			t.Errorf("Expected no doc for type %s", typ.Name)
+		}
+
+		for _, m := range typ.Methods {
+			if m.Doc != "" {
+				t.Errorf("Expected no doc for method %s.%s", typ.Name, m.Name)
+			}
+		}
+	}
+
+	res, err = g.Load("strings", "Builder.WriteString", "")
+	if err != nil {
+		t.Fatalf("Failed to load strings.Builder.WriteString: %v", err)
+	}
+
+	if sym := res.(godoc.SymbolDoc); sym.DocText != "" || sym.HTML() != "" || sym.Decl == "" || len(sym.Args) != 1 {
+		t.Errorf("Expected a signature without doc for Builder.WriteString, got %+v", sym)
+	}
+}
+
+func TestWithStripLicenseHeader(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod": "module example.com/licensed\n\ngo 1.22\n",
+		"doc.go": `// Copyright 2024 Example Corp. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+//
+// Package licensed does things.
+package licensed
+`,
+	}
+
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+
+	for _, tt := range []struct {
+		strip bool
+		want  string
+	}{
+		{false, "Copyright 2024 Example Corp."},
+		{true, "Package licensed does things."},
+	} {
+		g := godoc.New(godoc.WithWorkdir(dir), godoc.WithCacheMode(godoc.CacheDisabled), godoc.WithStripLicenseHeader(tt.strip))
+
+		res, err := g.Load("example.com/licensed", "", "")
+		if err != nil {
+			t.Fatalf("Failed to load package: %v", err)
+		}
+
+		pkg := res.(godoc.PackageDoc)
+		if !strings.HasPrefix(pkg.DocText, tt.want) {
+			t.Errorf("Expected the package doc to start with %q when stripping is %v, got %q", tt.want, tt.strip, pkg.DocText)
+		}
+
+		if tt.strip && (pkg.Synopsis != "Package licensed does things." || strings.Contains(pkg.HTML(), "Copyright")) {
+			t.Errorf("Expected the synopsis and HTML without the license, got %q and %q", pkg.Synopsis, pkg.HTML())
+		}
+	}
+}
+
+func TestVariadic(t *testing.T) {
+	g := newTestGodoc()
+
+	for _, tt := range []struct {
+		sel  string
+		want bool
+	}{
+		{"Println", true},
+		{"Sprint", true},
+		{"Sscan", true},
+		{"Stringer.String", false},
+		{"Errorf", true},
+		{"State.Write", false},
+	} {
+		res, err := g.Load("fmt", tt.sel, "")
+		if err != nil {
+			t.Fatalf("Failed to load fmt.%s: %v", tt.sel, err)
+		}
+
+		if sym := res.(godoc.SymbolDoc); sym.Variadic != tt.want {
+			t.Errorf("Expected fmt.%s to have Variadic=%v", tt.sel, tt.want)
+		}
+	}
+
+	res, err := g.Load("log", "", "")
+	if err != nil {
+		t.Fatalf("Failed to load log: %v", err)
+	}
+
+	for _, typ := range res.(godoc.PackageDoc).Types {
+		if typ.Name != "Logger" {
+			continue
+		}
+
+		want := map[string]bool{"Printf": true, "Println": true, "Output": false, "SetPrefix": false}
+		for _, m := range typ.Methods {
+			if v, ok := want[m.Name]; ok && m.Variadic != v {
+				t.Errorf("Expected Logger.%s to have Variadic=%v", m.Name, v)
+			}
+		}
+	}
+}
+
+func TestWithSource(t *testing.T) {
+	fsys := fstest.MapFS{
+		"src/example.com/greet/greet.go": {Data: []byte(`// Package greet says hello.
+package greet
+
+import "strings"
+
+// Greeter greets people.
+type Greeter struct {
+	Name string // Name of the greeter.
+}
+
+// Hello returns a greeting for names.
+func (g Greeter) Hello(names ...string) string {
+	return "Hello, " + strings.Join(names, ", ")
+}
+`)},
+		"src/example.com/greet/greet_windows.go": {Data: []byte(`package greet
+
+// Windows is only declared on windows.
+const Windows = true
+`)},
+		"src/example.com/greet/greet_test.go": {Data: []byte(`package greet_test
+
+func ExampleGreeter_Hello() {}
+`)},
+	}
+
+	g := godoc.New(godoc.WithSource(fsys, "src"), godoc.WithGOOS("linux"))
+
+	res, err := g.Load("example.com/greet", "", "")
+	if err != nil {
+		t.Fatalf("Failed to load from source: %v", err)
+	}
+
+	pkg := res.(godoc.PackageDoc)
+	if pkg.Name != "greet" || pkg.ImportPath != "example.com/greet" || pkg.Synopsis != "Package greet says hello." {
+		t.Fatalf("Unexpected package from source: %+v", pkg)
+	}
+
+	if len(pkg.Consts) != 0 {
+		t.Errorf("Expected files of other platforms to be skipped, got %+v", pkg.Consts)
+	}
+
+	res, err = g.Load("example.com/greet", "Greeter.Hello", "")
+	if err != nil {
+		t.Fatalf("Failed to load symbol from source: %v", err)
+	}
+
+	sym := res.(godoc.SymbolDoc)
+	if !sym.Variadic || len(sym.Examples) != 1 {
+		t.Errorf("Expected a variadic method with an example, got %+v", sym)
+	}
+
+	if name, err := g.PackageName("example.com/greet", ""); err != nil || name != "greet" {
+		t.Errorf("Expected package name greet, got %q (%v)", name, err)
+	}
+
+	if _, err := g.Load("example.com/missing", "", ""); err == nil {
+		t.Error("Expected an error for a package missing from the source")
+	}
+}
+
+func TestMarkdownGenericDecl(t *testing.T) {
+	result, err := newTestGodoc().Load("slices", "Values", "")
+	if err != nil {
+		t.Fatalf("Failed to load slices.Values: %v", err)
+	}
+
+	md := result.(godoc.SymbolDoc).Markdown()
+	if !strings.Contains(md, "```go\nfunc Values[Slice ~[]E, E any](s Slice) iter.Seq[E]\n```") {
+		t.Errorf("Expected the declaration to be left unchanged, got:\n%s", md)
+	}
+}
+
+func TestValueDecl(t *testing.T) {
+	g := newTestGodoc()
+	result, err := g.Load("io", "", "")
+	if err != nil {
+		t.Fatalf("Failed to load io: %v", err)
+	}
+
+	pkgDoc := result.(godoc.PackageDoc)
+	for _, v := range pkgDoc.Vars {
+		if slices.Contains(v.Names, "EOF") && v.Decl != `var EOF = errors.New("EOF")` {
+			t.Errorf("Unexpected EOF decl %q", v.Decl)
+		}
+	}
+
+	for _, c := range pkgDoc.Consts {
+		if slices.Contains(c.Names, "SeekStart") && !strings.Contains(c.Decl, "SeekStart   = 0 // seek relative to the origin of the file") {
+			t.Errorf("Unexpected SeekStart decl %q", c.Decl)
+		}
+	}
+
+	if md := pkgDoc.Markdown(); !strings.Contains(md, "```go\nvar EOF = errors.New(\"EOF\")\n```") {
+		t.Errorf("Expected the EOF declaration in the markdown, got:\n%s", md)
+	}
+
+	// Doc links in the comments of a declaration are left as written.
+	result, err = g.Load("bufio", "", "")
+	if err != nil {
+		t.Fatalf("Failed to load bufio: %v", err)
+	}
+
+	md := result.(godoc.PackageDoc).Markdown()
+	if !strings.Contains(md, "// unless the user provides an explicit buffer with [Scanner.Buffer].\n") {
+		t.Errorf("Expected the doc link in the MaxScanTokenSize comment to be left as written, got:\n%s", md)
+	}
+}
@@ -0,0 +1,227 @@
+package godoc
+
+import (
+	"fmt"
+	"go/doc/comment"
+	"html"
+	"strings"
+)
+
+// docLinkBaseURL is the base URL of doc links to other packages.
+const docLinkBaseURL = "https://pkg.go.dev"
+
+// FullHTML returns the package documentation as a complete HTML document.
+//
+// Like [PackageDoc.Markdown], the document starts with the package name and
+// import path, followed by an index of the [PackageDoc.TableOfContents], the
+// package comment, and one section per non-empty group of constants,
+// variables, functions, and types. Constructors and methods are listed under
+// their types. Elements have the IDs of the table of contents, so doc links
+// to symbols of the package link to their anchors, while doc links to other
+// packages link to pkg.go.dev.
+func (p PackageDoc) FullHTML() string {
+	return p.fullHTML(nil)
+}
+
+// fullHTML renders [PackageDoc.FullHTML], as a page of the given doc site,
+// if any: the page then links the stylesheet of the site, the pages of the
+// types, and the pages of the packages below the package.
+func (p PackageDoc) fullHTML(site *htmlSite) string {
+	h := htmlWriter{
+		importPath: p.ImportPath,
+		lookupSym:  p.lookupSym,
+		site:       site,
+	}
+
+	h.head(fmt.Sprintf("%s package - %s", p.Name, p.ImportPath))
+	h.printf("<h1>package %s</h1>\n", html.EscapeString(p.Name))
+	h.code(fmt.Sprintf("import %q", p.ImportPath))
+	h.index(p.TableOfContents())
+
+	h.heading(2, "pkg-overview", "Overview")
+	if p.docParsed != nil {
+		h.parsed(p.docParsed)
+	} else {
+		h.doc(p.DocText)
+	}
+
+	if len(p.Consts) > 0 {
+		h.heading(2, "pkg-constants", "Constants")
+		for _, c := range p.Consts {
+			h.value(c)
+		}
+	}
+
+	if len(p.Vars) > 0 {
+		h.heading(2, "pkg-variables", "Variables")
+		for _, v := range p.Vars {
+			h.value(v)
+		}
+	}
+
+	if funcs := p.standaloneFuncs(); len(funcs) > 0 {
+		h.heading(2, "pkg-functions", "Functions")
+		for _, f := range funcs {
+			h.function(3, f)
+		}
+	}
+
+	if len(p.Types) > 0 {
+		h.heading(2, "pkg-types", "Types")
+		for _, t := range p.Types {
+			h.typeDoc(3, t)
+		}
+	}
+
+	if site != nil {
+		h.subpackages()
+	}
+
+	h.printf("</body>\n</html>\n")
+
+	return h.sb.String()
+}
+
+// htmlWriter accumulates HTML.
+type htmlWriter struct {
+	sb         strings.Builder
+	importPath string
+	lookupSym  func(recv, name string) bool
+	printer    *comment.Printer
+
+	// site is the doc site of the page, if any, and typePage the type
+	// documented by the page if it is a type page of the site.
+	site     *htmlSite
+	typePage string
+}
+
+// printf writes formatted text.
+func (h *htmlWriter) printf(format string, args ...any) {
+	fmt.Fprintf(&h.sb, format, args...)
+}
+
+// head writes the start of the document up to the opening body tag.
+func (h *htmlWriter) head(title string) {
+	h.printf("<!DOCTYPE html>\n<html lang=\"en\">\n<head>\n<meta charset=\"utf-8\">\n")
+	h.printf("<title>%s</title>\n", html.EscapeString(title))
+	if h.site != nil {
+		h.printf("<link rel=\"stylesheet\" href=\"%s\">\n", html.EscapeString(h.site.rootURL(h.importPath)+siteStylesheet))
+	}
+	h.printf("</head>\n<body>\n")
+}
+
+// heading writes a heading of the given level with the given ID.
+func (h *htmlWriter) heading(level int, id, title string) {
+	h.printf("<h%d id=\"%s\">%s</h%d>\n", level, html.EscapeString(id), html.EscapeString(title), level)
+}
+
+// index writes the table of contents as a list of links.
+func (h *htmlWriter) index(toc []TOCEntry) {
+	h.printf("<nav id=\"pkg-index\">\n<ul>\n")
+	for _, e := range toc {
+		h.printf("<li class=\"level-%d\"><a href=\"#%s\">%s</a></li>\n", e.Level, html.EscapeString(e.ID), html.EscapeString(e.Title))
+	}
+	h.printf("</ul>\n</nav>\n")
+}
+
+// code writes s as a preformatted code block, if non-empty.
+func (h *htmlWriter) code(s string) {
+	if s != "" {
+		h.printf("<pre><code>%s</code></pre>\n", html.EscapeString(s))
+	}
+}
+
+// doc writes doc comment text, if non-empty.
+func (h *htmlWriter) doc(text string) {
+	if text != "" {
+		h.parsed(parseDocText(text, h.lookupSym))
+	}
+}
+
+// parsed writes a parsed doc comment.
+func (h *htmlWriter) parsed(d *comment.Doc) {
+	if h.printer == nil {
+		h.printer = &comment.Printer{
+			HeadingLevel: 3,
+			DocLinkURL:   h.docLinkURL,
+		}
+	}
+
+	h.sb.Write(h.printer.HTML(d))
+}
+
+// docLinkURL returns the URL of a doc link: the anchor of the symbol for
+// links within the package, and its pkg.go.dev page otherwise. In a doc
+// site, links to packages of the site point to their pages, and links from
+// a type page to other symbols of the package to the package page.
+func (h *htmlWriter) docLinkURL(link *comment.DocLink) string {
+	anchor := link.Name
+	if link.Recv != "" {
+		anchor = link.Recv + "." + link.Name
+	}
+
+	if link.ImportPath != "" && link.ImportPath != h.importPath {
+		if url, ok := h.site.pageURL(h.importPath, link.ImportPath); ok {
+			if anchor != "" {
+				url += "#" + anchor
+			}
+
+			return url
+		}
+
+		return link.DefaultURL(docLinkBaseURL)
+	}
+
+	if h.typePage != "" && link.Recv != h.typePage && (link.Recv != "" || link.Name != h.typePage) {
+		return "index.html#" + anchor
+	}
+
+	return "#" + anchor
+}
+
+// value writes a constant or variable group.
+func (h *htmlWriter) value(v ValueDoc) {
+	for _, name := range v.Names {
+		h.heading(3, name, name)
+	}
+
+	h.doc(v.Doc)
+}
+
+// typeDoc writes a type with a heading of the given level, followed by its
+// constructors and methods one level below. In a doc site, the heading is
+// followed by a link to the page of the type, unless on that page.
+func (h *htmlWriter) typeDoc(level int, t TypeDoc) {
+	h.heading(level, t.Name, "type "+t.Name)
+	if h.site != nil && h.typePage == "" {
+		h.printf("<p class=\"type-page\"><a href=\"%s\">%s</a></p>\n", html.EscapeString(typePageName(t.Name)), html.EscapeString(t.Name))
+	}
+
+	h.code(t.Decl)
+	h.doc(t.Doc)
+
+	for _, f := range t.Constructors {
+		h.function(level+1, f)
+	}
+
+	for _, m := range t.Methods {
+		recv := t.Name
+		if m.RecvPointer {
+			recv = "*" + recv
+		}
+
+		h.heading(level+1, t.Name+"."+m.Name, "func ("+recv+") "+m.Name)
+		// Interface methods are part of the declaration.
+		if t.Kind != "interface" {
+			h.code(methodSignature(m))
+		}
+		h.doc(m.Doc)
+	}
+}
+
+// function writes a function with a heading of the given level.
+func (h *htmlWriter) function(level int, f FuncDoc) {
+	h.heading(level, f.Name, "func "+f.Name)
+	h.code(funcDeclOrSignature(f))
+	h.doc(f.Doc)
+}
@@ -0,0 +1,76 @@
+package godoc
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPackageDocFullHTML(t *testing.T) {
+	p := PackageDoc{
+		ImportPath: "example.com/foo",
+		Name:       "foo",
+		DocText:    "Package foo does things with a [Client] and [io.Reader].\n\n# Usage\n\nCall [Client.Do].",
+		Consts:     []ValueDoc{{Names: []string{"A", "B"}, Doc: "Enum values."}},
+		Funcs: []FuncDoc{
+			{Name: "NewClient", Decl: "func NewClient() *Client"},
+			{Name: "Run", Decl: "func Run() error", Doc: "Run runs <things>."},
+		},
+		Types: []TypeDoc{
+			{
+				Name:         "Client",
+				Kind:         "struct",
+				Decl:         "type Client struct{}",
+				Constructors: []FuncDoc{{Name: "NewClient", Decl: "func NewClient() *Client"}},
+				Methods: []MethodDoc{{
+					Recv: "Client", RecvName: "c", RecvType: "*Client", RecvPointer: true, Name: "Do",
+					Returns: []ArgInfo{{Type: "error"}}, Doc: "Do does it.",
+				}},
+			},
+			{
+				Name:    "Doer",
+				Kind:    "interface",
+				Decl:    "type Doer interface{ Do() error }",
+				Methods: []MethodDoc{{Name: "Do", Returns: []ArgInfo{{Type: "error"}}}},
+			},
+		},
+	}
+
+	out := p.FullHTML()
+
+	for _, want := range []string{
+		"<!DOCTYPE html>",
+		"<title>foo package - example.com/foo</title>",
+		"<pre><code>import &#34;example.com/foo&#34;</code></pre>",
+		`<li class="level-1"><a href="#pkg-constants">Constants</a></li>`,
+		`<li class="level-3"><a href="#Client.Do">Do</a></li>`,
+		`<a href="#Client">Client</a>`,
+		`<a href="https://pkg.go.dev/io#Reader">io.Reader</a>`,
+		`<a href="#Client.Do">Client.Do</a>`,
+		`<h3 id="hdr-Usage">Usage</h3>`,
+		`<h3 id="A">A</h3>`,
+		`<h2 id="pkg-functions">Functions</h2>`,
+		`<h3 id="Run">func Run</h3>`,
+		"Run runs &lt;things&gt;.",
+		`<h4 id="NewClient">func NewClient</h4>`,
+		`<h4 id="Client.Do">func (*Client) Do</h4>`,
+		"<pre><code>func (c *Client) Do() error</code></pre>",
+		`<h4 id="Doer.Do">func (Doer) Do</h4>`,
+		"</html>\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected HTML to contain %q, got:\n%s", want, out)
+		}
+	}
+
+	if n := strings.Count(out, `id="NewClient"`); n != 1 {
+		t.Errorf("expected constructors to be listed once, under their type, got %d", n)
+	}
+
+	if strings.Contains(out, "pkg-variables") {
+		t.Errorf("expected no empty variables section, got:\n%s", out)
+	}
+
+	if strings.Contains(out, "<pre><code>func Do() error") {
+		t.Errorf("expected interface method signatures to be omitted, got:\n%s", out)
+	}
+}
@@ -0,0 +1,143 @@
+package godoc
+
+import (
+	"go/types"
+	"sort"
+)
+
+// addImplementers populates the Implementers of every interface symbol in
+// the given symbol index. Candidate types are the exported types declared in
+// the package itself and in its direct imports.
+func addImplementers(symbols map[string]SymbolDoc, typesInfo *types.Info, pkgPath string) {
+	pkg := typesPackage(typesInfo, pkgPath)
+	if pkg == nil {
+		return
+	}
+
+	for key, sym := range symbols {
+		if sym.Kind != "type" || sym.TypeDoc == nil || sym.TypeDoc.Kind != "interface" {
+			continue
+		}
+
+		obj, ok := pkg.Scope().Lookup(sym.Name).(*types.TypeName)
+		if !ok {
+			continue
+		}
+
+		sym.Implementers = implementers(obj, pkg)
+		symbols[key] = sym
+	}
+}
+
+// typesPackage returns the *[types.Package] with the given path whose
+// declarations are recorded in typesInfo.
+func typesPackage(typesInfo *types.Info, pkgPath string) *types.Package {
+	if typesInfo == nil {
+		return nil
+	}
+
+	for _, obj := range typesInfo.Defs {
+		if obj == nil || obj.Pkg() == nil || obj.Pkg().Path() != pkgPath {
+			continue
+		}
+
+		return obj.Pkg()
+	}
+
+	return nil
+}
+
+// implementers returns the concrete types declared in pkg or its direct
+// imports that implement the interface named by iface, either by value or
+// by pointer (reported as "*T"). Types from other packages are qualified by
+// their package name. Empty interfaces, which every type implements, yield
+// nil.
+func implementers(iface *types.TypeName, pkg *types.Package) []string {
+	it, ok := iface.Type().Underlying().(*types.Interface)
+	if !ok || it.NumMethods() == 0 {
+		return nil
+	}
+
+	if named, ok := iface.Type().(*types.Named); ok && named.TypeParams().Len() > 0 {
+		return nil
+	}
+
+	qualifier := types.RelativeTo(pkg)
+
+	var names []string
+	for _, p := range append([]*types.Package{pkg}, pkg.Imports()...) {
+		scope := p.Scope()
+		for _, name := range scope.Names() {
+			obj, ok := scope.Lookup(name).(*types.TypeName)
+			if !ok || !obj.Exported() || obj.IsAlias() {
+				continue
+			}
+
+			named, ok := obj.Type().(*types.Named)
+			if !ok || named.TypeParams().Len() > 0 || types.IsInterface(named) {
+				continue
+			}
+
+			switch {
+			case types.Implements(named, it):
+				names = append(names, types.TypeString(named, qualifier))
+			case types.Implements(types.NewPointer(named), it):
+				names = append(names, types.TypeString(types.NewPointer(named), qualifier))
+			}
+		}
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// typeRelations returns, for the type named by obj, the interfaces declared
+// in its package that it implements, by value or by pointer, if it is a
+// concrete type, or the concrete types declared in its package that
+// implement it (reported as "*T" if only by pointer) if it is an interface.
+// Only exported types are considered unless unexported is set. Generic
+// types, and empty interfaces, which every type implements, are skipped.
+func typeRelations(obj *types.TypeName, unexported bool) (implements, implementedBy []string) {
+	named, ok := obj.Type().(*types.Named)
+	if !ok || named.TypeParams().Len() > 0 || obj.Pkg() == nil {
+		return nil, nil
+	}
+
+	it, isIface := named.Underlying().(*types.Interface)
+	if isIface && it.NumMethods() == 0 {
+		return nil, nil
+	}
+
+	scope := obj.Pkg().Scope()
+	for _, name := range scope.Names() {
+		cand, ok := scope.Lookup(name).(*types.TypeName)
+		if !ok || cand == obj || cand.IsAlias() || (!cand.Exported() && !unexported) {
+			continue
+		}
+
+		candNamed, ok := cand.Type().(*types.Named)
+		if !ok || candNamed.TypeParams().Len() > 0 {
+			continue
+		}
+
+		candIface, candIsIface := candNamed.Underlying().(*types.Interface)
+
+		switch {
+		case isIface && !candIsIface:
+			switch {
+			case types.Implements(candNamed, it):
+				implementedBy = append(implementedBy, name)
+			case types.Implements(types.NewPointer(candNamed), it):
+				implementedBy = append(implementedBy, "*"+name)
+			}
+
+		case !isIface && candIsIface && candIface.NumMethods() > 0:
+			if types.Implements(named, candIface) || types.Implements(types.NewPointer(named), candIface) {
+				implements = append(implements, name)
+			}
+		}
+	}
+
+	return implements, implementedBy
+}
@@ -0,0 +1,125 @@
+package godoc
+
+import (
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"slices"
+	"testing"
+)
+
+const implementersTestSrc = `package p
+
+import "fmt"
+
+type Shape interface {
+	Area() float64
+}
+
+type Any interface{}
+
+type Square struct{}
+
+func (Square) Area() float64 { return 1 }
+
+type Circle struct{}
+
+func (*Circle) Area() float64 { return 3 }
+
+type Box[T any] struct{}
+
+func (Box[T]) Area() float64 { return 0 }
+
+var _ fmt.Stringer
+`
+
+func TestImplementersFromSource(t *testing.T) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "p.go", implementersTestSrc, 0)
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+
+	info := &types.Info{Defs: make(map[*ast.Ident]types.Object)}
+	conf := types.Config{Importer: importer.Default()}
+	if _, err := conf.Check("example.com/p", fset, []*ast.File{f}, info); err != nil {
+		t.Fatalf("type check failed: %v", err)
+	}
+
+	pkg := typesPackage(info, "example.com/p")
+	if pkg == nil {
+		t.Fatalf("expected package from types info")
+	}
+
+	shape := pkg.Scope().Lookup("Shape").(*types.TypeName)
+	got := implementers(shape, pkg)
+	want := []string{"*Circle", "Square"}
+	if !slices.Equal(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+
+	if got := implementers(pkg.Scope().Lookup("Any").(*types.TypeName), pkg); got != nil {
+		t.Fatalf("expected no implementers for empty interface, got %v", got)
+	}
+
+	stringer := pkg.Imports()[0].Scope().Lookup("Stringer").(*types.TypeName)
+	if got := implementers(stringer, pkg); got != nil {
+		t.Fatalf("expected no implementers of fmt.Stringer, got %v", got)
+	}
+}
+
+func TestAddImplementers(t *testing.T) {
+	symbols := map[string]SymbolDoc{
+		"Shape": {Kind: "type", Name: "Shape", TypeDoc: &TypeDoc{Name: "Shape", Kind: "interface"}},
+		"Area":  {Kind: "method", Name: "Area"},
+	}
+
+	addImplementers(symbols, nil, "example.com/p")
+
+	if symbols["Shape"].Implementers != nil {
+		t.Fatalf("expected no implementers without type info")
+	}
+}
+
+func TestTypeRelations(t *testing.T) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "p.go", implementersTestSrc+"\ntype shape interface{ Area() float64 }\n", 0)
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+
+	info := &types.Info{Defs: make(map[*ast.Ident]types.Object)}
+	conf := types.Config{Importer: importer.Default()}
+	pkg, err := conf.Check("example.com/p", fset, []*ast.File{f}, info)
+	if err != nil {
+		t.Fatalf("type check failed: %v", err)
+	}
+
+	lookup := func(name string) *types.TypeName {
+		return pkg.Scope().Lookup(name).(*types.TypeName)
+	}
+
+	implements, implementedBy := typeRelations(lookup("Shape"), false)
+	if implements != nil || !slices.Equal(implementedBy, []string{"*Circle", "Square"}) {
+		t.Errorf("unexpected relations of Shape: %v, %v", implements, implementedBy)
+	}
+
+	for _, name := range []string{"Square", "Circle"} {
+		implements, implementedBy := typeRelations(lookup(name), false)
+		if !slices.Equal(implements, []string{"Shape"}) || implementedBy != nil {
+			t.Errorf("unexpected relations of %s: %v, %v", name, implements, implementedBy)
+		}
+	}
+
+	if implements, _ := typeRelations(lookup("Square"), true); !slices.Equal(implements, []string{"Shape", "shape"}) {
+		t.Errorf("expected unexported interfaces with unexported, got %v", implements)
+	}
+
+	for _, name := range []string{"Any", "Box"} {
+		if implements, implementedBy := typeRelations(lookup(name), false); implements != nil || implementedBy != nil {
+			t.Errorf("expected no relations of %s, got %v, %v", name, implements, implementedBy)
+		}
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
 	"github.com/muesli/termenv"
 )
 
@@ -63,6 +64,8 @@ var helpEntries = []struct {
 	{"d/Ctrl+D", "half page down"},
 	{"u/Ctrl+U", "half page up"},
 	{"c", "copy to clipboard"},
+	{"y", "copy signature in view"},
+	{"Y", "copy import line"},
 	{"?", "toggle help"},
 	{"q/Esc", "quit"},
 }
@@ -72,6 +75,26 @@ type Document struct {
 	Content string
 	Raw     string
 	Label   string
+
+	// Import is the import line of the package, copied with Y.
+	Import string
+
+	// Segments are the declarations shown in Content, in order. The one at
+	// the top of the view is copied with y.
+	Segments []Segment
+}
+
+// Segment is a declaration shown in a [Document], such as the signature of
+// a function or the definition of a type.
+type Segment struct {
+	Text string
+}
+
+// segmentPos is a segment along with the line of the content where it is
+// shown.
+type segmentPos struct {
+	line int
+	text string
 }
 
 // Run launches an interactive pager to browse rendered markdown content.
@@ -92,6 +115,7 @@ type model struct {
 	doc           Document
 	showHelp      bool
 	statusMessage string
+	segments      []segmentPos
 }
 
 func newModel(doc Document) *model {
@@ -102,7 +126,78 @@ func newModel(doc Document) *model {
 	return &model{
 		viewport: vp,
 		doc:      doc,
+		segments: locateSegments(doc.Content, doc.Segments),
+	}
+}
+
+// segmentPrefixLen is the length of the start of a segment looked up in the
+// content, short enough not to be wrapped by the renderer.
+const segmentPrefixLen = 40
+
+// locateSegments finds the lines of content showing the start of each
+// segment, searching in order from the previous match. Segments that are not
+// found are left out.
+func locateSegments(content string, segments []Segment) []segmentPos {
+	lines := strings.Split(ansi.Strip(content), "\n")
+
+	var (
+		found []segmentPos
+		from  int
+	)
+	for _, seg := range segments {
+		first, _, _ := strings.Cut(seg.Text, "\n")
+		first = strings.TrimSpace(first)
+		if first == "" {
+			continue
+		}
+
+		if runes := []rune(first); len(runes) > segmentPrefixLen {
+			first = string(runes[:segmentPrefixLen])
+		}
+
+		for i := from; i < len(lines); i++ {
+			if strings.Contains(lines[i], first) {
+				found = append(found, segmentPos{line: i, text: seg.Text})
+				from = i + 1
+
+				break
+			}
+		}
 	}
+
+	return found
+}
+
+// focusedSegment returns the text of the last segment starting at or above
+// the top of the view, or of the first one in view if there is none.
+func (m *model) focusedSegment() string {
+	top := m.viewport.YOffset
+
+	var text string
+	for _, seg := range m.segments {
+		if seg.line > top {
+			if text == "" && seg.line < top+m.viewport.Height {
+				text = seg.text
+			}
+
+			break
+		}
+
+		text = seg.text
+	}
+
+	return text
+}
+
+// copyText copies text to the clipboard, reporting it as what in the status
+// bar.
+func (m *model) copyText(text, what string) tea.Cmd {
+	termenv.Copy(text)
+	if err := clipboard.WriteAll(text); err != nil {
+		return m.setStatusMessage(fmt.Sprintf("copy failed: %v", err))
+	}
+
+	return m.setStatusMessage("Copied " + what)
 }
 
 func (m *model) Init() tea.Cmd {
@@ -137,12 +232,17 @@ func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.setSize(m.width, m.height)
 		case "c":
 			if m.doc.Raw != "" {
-				termenv.Copy(m.doc.Raw)
-				if err := clipboard.WriteAll(m.doc.Raw); err != nil {
-					cmds = append(cmds, m.setStatusMessage(fmt.Sprintf("copy failed: %v", err)))
-				} else {
-					cmds = append(cmds, m.setStatusMessage("Copied contents"))
-				}
+				cmds = append(cmds, m.copyText(m.doc.Raw, "contents"))
+			}
+		case "y":
+			if text := m.focusedSegment(); text != "" {
+				cmds = append(cmds, m.copyText(text, "signature"))
+			} else {
+				cmds = append(cmds, m.setStatusMessage("no signature in view"))
+			}
+		case "Y":
+			if m.doc.Import != "" {
+				cmds = append(cmds, m.copyText(m.doc.Import, "import line"))
 			}
 		case "down", "j":
 			m.viewport.ScrollDown(1)
@@ -0,0 +1,81 @@
+package pager
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLocateSegments(t *testing.T) {
+	content := strings.Join([]string{
+		"\x1b[1mpackage foo\x1b[0m",
+		"",
+		"  \x1b[38;5;81mfunc\x1b[0m New() *Client",
+		"",
+		"  func (c *Client) DoSomethingWithAVeryLongName(ctx",
+		"  context.Context, name string) error",
+		"",
+		"  type Client struct{}",
+	}, "\n")
+
+	segments := []Segment{
+		{Text: "func New() *Client"},
+		{Text: "func Missing()"},
+		{Text: "func (c *Client) DoSomethingWithAVeryLongName(ctx context.Context, name string) error"},
+		{Text: "type Client struct{}\n"},
+		{Text: "\n"},
+	}
+
+	want := []segmentPos{
+		{line: 2, text: "func New() *Client"},
+		{line: 4, text: "func (c *Client) DoSomethingWithAVeryLongName(ctx context.Context, name string) error"},
+		{line: 7, text: "type Client struct{}\n"},
+	}
+
+	if got := locateSegments(content, segments); !reflect.DeepEqual(got, want) {
+		t.Errorf("locateSegments() = %+v, want %+v", got, want)
+	}
+
+	// Segments are searched in order, so an earlier one is not found again
+	// below a later one.
+	reversed := []Segment{{Text: "type Client struct{}"}, {Text: "func New() *Client"}}
+	if got := locateSegments(content, reversed); len(got) != 1 || got[0].line != 7 {
+		t.Errorf("expected only the first segment to be found, got %+v", got)
+	}
+}
+
+func TestFocusedSegment(t *testing.T) {
+	lines := make([]string, 20)
+	lines[3] = "func A()"
+	lines[10] = "func B()"
+
+	m := newModel(Document{
+		Content:  strings.Join(lines, "\n"),
+		Segments: []Segment{{Text: "func A()"}, {Text: "func B()"}},
+	})
+	m.viewport.Height = 5
+
+	tests := []struct {
+		top  int
+		want string
+	}{
+		{0, "func A()"},  // First segment in view
+		{3, "func A()"},  // Segment at the top
+		{7, "func A()"},  // Last segment above the top
+		{10, "func B()"}, // Next segment at the top
+		{12, "func B()"},
+	}
+
+	for _, tt := range tests {
+		m.viewport.SetYOffset(tt.top)
+		if got := m.focusedSegment(); got != tt.want {
+			t.Errorf("focusedSegment() at %d = %q, want %q", tt.top, got, tt.want)
+		}
+	}
+
+	m.segments = locateSegments(m.doc.Content, []Segment{{Text: "func B()"}})
+	m.viewport.SetYOffset(0)
+	if got := m.focusedSegment(); got != "" {
+		t.Errorf("expected no segment in view, got %q", got)
+	}
+}
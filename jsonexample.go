@@ -0,0 +1,456 @@
+package godoc
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// jsonExampleTypes maps well-known named types, qualified by package name,
+// to their JSON example values.
+var jsonExampleTypes = map[string]any{
+	"big.Float":       0,
+	"big.Int":         0,
+	"json.Number":     0,
+	"json.RawMessage": nil,
+	"time.Duration":   0,
+	"time.Time":       "0001-01-01T00:00:00Z",
+}
+
+// JSONExample returns an example of the JSON object a value of the struct
+// type is encoded to by encoding/json, e.g. to document the shape of a
+// request or response.
+//
+// Fields are named after their json tags, and fields tagged "-" as well as
+// unexported fields are omitted. Values are the zero values of the field
+// types, except that pointers are followed, and slices, arrays, and maps
+// hold a single element to show its shape. Anonymous struct types are
+// expanded recursively. Other named types, whose definitions are not part
+// of the TypeDoc, are rendered as null unless they are well known, such as
+// time.Time, and so are embedded structs without a json name, whose fields
+// are promoted. [PackageDoc.JSONExample] expands the named types of the
+// package too.
+//
+// It returns an error if t is not a struct type, or if a field has a type
+// that cannot be encoded as JSON, such as a channel or a function.
+func (t TypeDoc) JSONExample() (string, error) {
+	return (&jsonExampler{}).example(t)
+}
+
+// JSONExample returns an example of the JSON object a value of the struct
+// type with the given name is encoded to, as [TypeDoc.JSONExample] does,
+// except that the named types declared in the package are expanded
+// recursively, and the fields of the structs they embed are promoted. A type
+// already being expanded, as in a linked list, is rendered as null.
+//
+// It returns an error if the package declares no such type.
+func (p PackageDoc) JSONExample(typeName string) (string, error) {
+	e := &jsonExampler{
+		importPath: p.ImportPath,
+		pkgName:    p.Name,
+		types:      make(map[string]TypeDoc, len(p.Types)),
+		expanding:  make(map[string]bool),
+	}
+
+	for _, t := range p.Types {
+		e.types[t.Name] = t
+	}
+
+	t, ok := e.types[typeName]
+	if !ok {
+		return "", fmt.Errorf("type %s not found in %s", typeName, p.ImportPath)
+	}
+
+	return e.example(t)
+}
+
+// jsonExampler builds JSON examples, expanding the named types declared in
+// a package, if any.
+type jsonExampler struct {
+	importPath string
+	pkgName    string
+	types      map[string]TypeDoc // Types of the package by name
+	expanding  map[string]bool    // Types being expanded, to stop on cycles
+}
+
+// example returns the indented JSON example of the struct type t.
+func (e *jsonExampler) example(t TypeDoc) (string, error) {
+	if t.Kind != "struct" {
+		return "", fmt.Errorf("%s is not a struct type", t.Name)
+	}
+
+	obj, err := e.object(t)
+	if err != nil {
+		return "", err
+	}
+
+	data, err := json.MarshalIndent(obj, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}
+
+// object returns the JSON example object of the struct type t.
+func (e *jsonExampler) object(t TypeDoc) (jsonObject, error) {
+	if e.expanding != nil {
+		e.expanding[t.Name] = true
+		defer delete(e.expanding, t.Name)
+	}
+
+	obj := jsonObject{}
+	for _, f := range t.Fields {
+		if err := e.addField(&obj, f.Name, f.Tag, f.Embedded, e.parseType(f.Type)); err != nil {
+			return nil, fmt.Errorf("field %s: %w", f.Name, err)
+		}
+	}
+
+	return obj, nil
+}
+
+// jsonMember is a member of a [jsonObject].
+type jsonMember struct {
+	name  string
+	value any
+}
+
+// jsonObject is a JSON object that keeps its members in order.
+type jsonObject []jsonMember
+
+// MarshalJSON implements [json.Marshaler].
+func (o jsonObject) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+
+	for i, m := range o {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+
+		name, err := json.Marshal(m.name)
+		if err != nil {
+			return nil, err
+		}
+
+		value, err := json.Marshal(m.value)
+		if err != nil {
+			return nil, err
+		}
+
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
+}
+
+// has reports whether the object has a member with the given name.
+func (o jsonObject) has(name string) bool {
+	for _, m := range o {
+		if m.name == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// addField adds the struct field with the given name, tag, and type to the
+// object, following the naming rules of encoding/json. A nil type is that of
+// an abbreviated type string, such as "struct{...}", which is rendered as
+// null.
+func (e *jsonExampler) addField(o *jsonObject, name, tag string, embedded bool, expr ast.Expr) error {
+	if unquoted, err := strconv.Unquote(tag); err == nil {
+		tag = unquoted
+	}
+
+	if r, _ := utf8.DecodeRuneInString(name); !embedded && !unicode.IsUpper(r) {
+		return nil
+	}
+
+	jsonTag := reflect.StructTag(tag).Get("json")
+	if jsonTag == "-" {
+		return nil
+	}
+
+	jsonName, tagOpts, _ := strings.Cut(jsonTag, ",")
+	if jsonName == "" {
+		if embedded {
+			return e.promote(o, expr)
+		}
+
+		jsonName = name
+	}
+
+	if o.has(jsonName) {
+		return nil
+	}
+
+	var value any
+	if expr != nil {
+		var err error
+		if value, err = e.value(expr); err != nil {
+			return err
+		}
+	}
+
+	if hasTagOption(tagOpts, "string") {
+		switch value.(type) {
+		case bool, int, string:
+			data, _ := json.Marshal(value)
+			value = string(data)
+		}
+	}
+
+	*o = append(*o, jsonMember{name: jsonName, value: value})
+
+	return nil
+}
+
+// promote adds the fields of the embedded struct type expr to the object.
+// Only the fields of the struct types of the package are known.
+func (e *jsonExampler) promote(o *jsonObject, expr ast.Expr) error {
+	if star, ok := expr.(*ast.StarExpr); ok {
+		expr = star.X
+	}
+
+	t, ok := e.localType(expr)
+	if !ok || t.Kind != "struct" || e.expanding[t.Name] {
+		return nil
+	}
+
+	embedded, err := e.object(t)
+	if err != nil {
+		return err
+	}
+
+	for _, m := range embedded {
+		if !o.has(m.name) {
+			*o = append(*o, m)
+		}
+	}
+
+	return nil
+}
+
+// localType returns the type of the package the type expression refers to.
+func (e *jsonExampler) localType(expr ast.Expr) (TypeDoc, bool) {
+	var name string
+	switch x := expr.(type) {
+	case *ast.Ident:
+		name = x.Name
+	case *ast.SelectorExpr:
+		if pkg, ok := x.X.(*ast.Ident); ok && (pkg.Name == e.pkgName || pkg.Name == e.importPath) {
+			name = x.Sel.Name
+		}
+	}
+
+	t, ok := e.types[name]
+
+	return t, ok
+}
+
+// hasTagOption reports whether the comma-separated struct tag options
+// contain opt.
+func hasTagOption(opts, opt string) bool {
+	for o := range strings.SplitSeq(opts, ",") {
+		if o == opt {
+			return true
+		}
+	}
+
+	return false
+}
+
+// named returns the JSON example value of the type t of the package: the
+// object of a struct type, or the value of the underlying type of another
+// type. Types with their own text or JSON encoding, and types already being
+// expanded, are rendered as an empty string or null.
+func (e *jsonExampler) named(t TypeDoc) (any, error) {
+	if e.expanding[t.Name] {
+		return nil, nil
+	}
+
+	for _, m := range t.Methods {
+		switch m.Name {
+		case "MarshalJSON":
+			return nil, nil
+		case "MarshalText":
+			return "", nil
+		}
+	}
+
+	switch t.Kind {
+	case "struct":
+		return e.object(t)
+	case "other":
+		// Generic types are left out, as their declaration starts with
+		// their type parameters.
+		underlying, ok := strings.CutPrefix(t.Decl, "type "+t.Name+" ")
+		if !ok {
+			return nil, nil
+		}
+
+		e.expanding[t.Name] = true
+		defer delete(e.expanding, t.Name)
+
+		return e.value(e.parseType(underlying))
+	}
+
+	return nil, nil
+}
+
+// parseType parses the type with the given string form, or returns nil if
+// it is abbreviated, such as "struct{...}".
+//
+// Packages in the type string may be qualified by import path, as in
+// "net/http.Header", which is not valid Go syntax. They are rewritten to
+// package names before parsing, and the types of the package itself to their
+// bare names.
+func (e *jsonExampler) parseType(typ string) ast.Expr {
+	src := qualifiedTypeRe.ReplaceAllStringFunc(typ, func(s string) string {
+		slash := strings.LastIndex(s, "/")
+		dot := strings.LastIndex(s[slash:], ".")
+		if dot < 0 {
+			return s
+		}
+
+		if e.importPath != "" && s[:slash+dot] == e.importPath {
+			return s[slash+dot+1:]
+		}
+
+		pkg := strings.Map(func(r rune) rune {
+			if r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r) {
+				return r
+			}
+
+			return '_'
+		}, s[slash+1:slash+dot])
+
+		return pkg + s[slash+dot:]
+	})
+
+	expr, err := parser.ParseExpr(src)
+	if err != nil {
+		return nil
+	}
+
+	return expr
+}
+
+// value returns the JSON example value of the type expression.
+func (e *jsonExampler) value(expr ast.Expr) (any, error) {
+	if t, ok := e.localType(expr); ok {
+		return e.named(t)
+	}
+
+	switch x := expr.(type) {
+	case *ast.Ident:
+		switch x.Name {
+		case "bool":
+			return false, nil
+		case "string":
+			return "", nil
+		case "int", "int8", "int16", "int32", "int64",
+			"uint", "uint8", "uint16", "uint32", "uint64", "uintptr",
+			"byte", "rune", "float32", "float64":
+			return 0, nil
+		case "complex64", "complex128":
+			return nil, fmt.Errorf("unsupported type %s", x.Name)
+		}
+
+		// Interfaces, type parameters, and unknown named types.
+		return nil, nil
+
+	case *ast.SelectorExpr:
+		var name string
+		if pkg, ok := x.X.(*ast.Ident); ok {
+			name = pkg.Name + "." + x.Sel.Name
+		}
+
+		if name == "unsafe.Pointer" {
+			return nil, fmt.Errorf("unsupported type %s", name)
+		}
+
+		return jsonExampleTypes[name], nil
+
+	case *ast.ParenExpr:
+		return e.value(x.X)
+
+	case *ast.StarExpr:
+		return e.value(x.X)
+
+	case *ast.ArrayType:
+		if elt, ok := x.Elt.(*ast.Ident); ok && x.Len == nil && (elt.Name == "byte" || elt.Name == "uint8") {
+			// Byte slices are encoded as base64 strings.
+			return "", nil
+		}
+
+		elem, err := e.value(x.Elt)
+		if err != nil {
+			return nil, err
+		}
+
+		return []any{elem}, nil
+
+	case *ast.MapType:
+		key := "key"
+		if k, ok := x.Key.(*ast.Ident); ok && k.Name != "string" {
+			if v, _ := e.value(k); v == 0 {
+				key = "0"
+			}
+		}
+
+		elem, err := e.value(x.Value)
+		if err != nil {
+			return nil, err
+		}
+
+		return jsonObject{{name: key, value: elem}}, nil
+
+	case *ast.StructType:
+		obj := jsonObject{}
+		for _, field := range x.Fields.List {
+			var tag string
+			if field.Tag != nil {
+				tag = field.Tag.Value
+			}
+
+			if len(field.Names) == 0 {
+				if err := e.addField(&obj, "", tag, true, field.Type); err != nil {
+					return nil, err
+				}
+
+				continue
+			}
+
+			for _, name := range field.Names {
+				if err := e.addField(&obj, name.Name, tag, false, field.Type); err != nil {
+					return nil, fmt.Errorf("field %s: %w", name.Name, err)
+				}
+			}
+		}
+
+		return obj, nil
+
+	case *ast.ChanType:
+		return nil, fmt.Errorf("unsupported channel type")
+
+	case *ast.FuncType:
+		return nil, fmt.Errorf("unsupported function type")
+	}
+
+	// Interfaces and instantiated generic types.
+	return nil, nil
+}
@@ -0,0 +1,110 @@
+package godoc
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTypeDocJSONExample(t *testing.T) {
+	td := TypeDoc{
+		Name: "Request",
+		Kind: "struct",
+		Fields: []FieldDoc{
+			{Name: "ID", Type: "int64", Tag: `json:"id"`},
+			{Name: "Name", Type: "string", Tag: `json:"name,omitempty"`},
+			{Name: "Count", Type: "int", Tag: `json:"count,string"`},
+			{Name: "Secret", Type: "string", Tag: `json:"-"`},
+			{Name: "internal", Type: "bool", Tag: `json:"internal"`},
+			{Name: "Tags", Type: "[]string"},
+			{Name: "Data", Type: "[]byte"},
+			{Name: "Labels", Type: "map[string]*go.dw1.io/x.Label"},
+			{Name: "Created", Type: "time.Time", Tag: `json:"created"`},
+			{Name: "Header", Type: "net/http.Header", Embedded: true},
+			{Name: "Meta", Type: "*struct{Owner string \"json:\\\"owner\\\"\"; Scores [2]float64}", Tag: `json:"meta"`},
+		},
+	}
+
+	got, err := td.JSONExample()
+	if err != nil {
+		t.Fatalf("JSONExample failed: %v", err)
+	}
+
+	want := `{
+  "id": 0,
+  "name": "",
+  "count": "0",
+  "Tags": [
+    ""
+  ],
+  "Data": "",
+  "Labels": {
+    "key": null
+  },
+  "created": "0001-01-01T00:00:00Z",
+  "meta": {
+    "owner": "",
+    "Scores": [
+      0
+    ]
+  }
+}`
+	if got != want {
+		t.Errorf("unexpected JSON example:\n%s\nwant:\n%s", got, want)
+	}
+
+	td.Fields = append(td.Fields, FieldDoc{Name: "Done", Type: "chan struct{}"})
+	if _, err := td.JSONExample(); err == nil || !strings.Contains(err.Error(), "Done") {
+		t.Errorf("expected error for channel field, got %v", err)
+	}
+
+	if _, err := (TypeDoc{Name: "Reader", Kind: "interface"}).JSONExample(); err == nil {
+		t.Errorf("expected error for non-struct type")
+	}
+}
+
+func TestPackageDocJSONExample(t *testing.T) {
+	p := PackageDoc{
+		ImportPath: "example.com/api",
+		Name:       "api",
+		Types: []TypeDoc{
+			{
+				Name: "User",
+				Kind: "struct",
+				Fields: []FieldDoc{
+					{Name: "Base", Type: "example.com/api.Base", Embedded: true},
+					{Name: "Name", Type: "string", Tag: `json:"name"`},
+					{Name: "Address", Type: "*example.com/api.Address", Tag: `json:"address"`},
+					{Name: "Role", Type: "Role", Tag: `json:"role"`},
+					{Name: "Friends", Type: "[]*User", Tag: `json:"friends"`},
+				},
+			},
+			{Name: "Base", Kind: "struct", Fields: []FieldDoc{{Name: "ID", Type: "int64", Tag: `json:"id"`}}},
+			{Name: "Address", Kind: "struct", Fields: []FieldDoc{{Name: "City", Type: "string", Tag: `json:"city"`}}},
+			{Name: "Role", Kind: "other", Decl: "type Role string"},
+		},
+	}
+
+	got, err := p.JSONExample("User")
+	if err != nil {
+		t.Fatalf("JSONExample failed: %v", err)
+	}
+
+	want := `{
+  "id": 0,
+  "name": "",
+  "address": {
+    "city": ""
+  },
+  "role": "",
+  "friends": [
+    null
+  ]
+}`
+	if got != want {
+		t.Errorf("unexpected JSON example:\n%s\nwant:\n%s", got, want)
+	}
+
+	if _, err := p.JSONExample("Missing"); err == nil {
+		t.Errorf("expected error for unknown type")
+	}
+}
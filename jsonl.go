@@ -0,0 +1,50 @@
+package godoc
+
+import (
+	"fmt"
+	"io"
+)
+
+// StreamJSON writes results to w as JSON Lines: one JSON object per line,
+// in the shape of their MarshalJSON output. Each line is written as soon as
+// it is encoded, and w is flushed after each line if it has a Flush method,
+// like a [bufio.Writer] or an [net/http.ResponseWriter] implementing
+// [net/http.Flusher], so that consumers can process the results
+// incrementally.
+//
+// It stops at the first nil result or encoding or write error, which is
+// returned along with the index of the result.
+func StreamJSON(w io.Writer, results []Result) error {
+	for i, res := range results {
+		if res == nil {
+			return fmt.Errorf("result %d: nil result", i)
+		}
+
+		data, err := res.MarshalJSON()
+		if err != nil {
+			return fmt.Errorf("result %d: %w", i, err)
+		}
+
+		if _, err := w.Write(append(data, '\n')); err != nil {
+			return fmt.Errorf("result %d: %w", i, err)
+		}
+
+		if err := flush(w); err != nil {
+			return fmt.Errorf("result %d: %w", i, err)
+		}
+	}
+
+	return nil
+}
+
+// flush flushes w if it is buffered.
+func flush(w io.Writer) error {
+	switch f := w.(type) {
+	case interface{ Flush() error }:
+		return f.Flush()
+	case interface{ Flush() }:
+		f.Flush()
+	}
+
+	return nil
+}
@@ -0,0 +1,75 @@
+package godoc
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// flushCounter counts the flushes of a buffered writer.
+type flushCounter struct {
+	*bufio.Writer
+	flushes int
+}
+
+func (f *flushCounter) Flush() error {
+	f.flushes++
+	return f.Writer.Flush()
+}
+
+func TestStreamJSON(t *testing.T) {
+	results := []Result{
+		PackageDoc{ImportPath: "example.com/foo", Name: "foo"},
+		SymbolDoc{ImportPath: "example.com/foo", Package: "foo", Kind: "func", Name: "Bar", FuncDoc: &FuncDoc{Name: "Bar"}},
+	}
+
+	var buf bytes.Buffer
+	w := &flushCounter{Writer: bufio.NewWriter(&buf)}
+	if err := StreamJSON(w, results); err != nil {
+		t.Fatalf("StreamJSON: %v", err)
+	}
+
+	if w.flushes != len(results) {
+		t.Fatalf("expected a flush per line, got %d", w.flushes)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != len(results) {
+		t.Fatalf("expected %d lines, got %q", len(results), buf.String())
+	}
+
+	for i, line := range lines {
+		want, err := results[i].MarshalJSON()
+		if err != nil {
+			t.Fatalf("MarshalJSON: %v", err)
+		}
+
+		if line != string(want) {
+			t.Errorf("line %d: expected %s, got %s", i, want, line)
+		}
+
+		if !json.Valid([]byte(line)) {
+			t.Errorf("line %d is not valid JSON: %s", i, line)
+		}
+	}
+
+	err := StreamJSON(&buf, []Result{results[0], nil})
+	if err == nil || !strings.Contains(err.Error(), "result 1") {
+		t.Fatalf("expected an error for the nil result, got %v", err)
+	}
+
+	errWrite := errors.New("write failed")
+	if err := StreamJSON(failingWriter{errWrite}, results); !errors.Is(err, errWrite) {
+		t.Fatalf("expected the write error, got %v", err)
+	}
+}
+
+// failingWriter is an io.Writer that always fails.
+type failingWriter struct{ err error }
+
+func (w failingWriter) Write([]byte) (int, error) {
+	return 0, w.err
+}
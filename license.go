@@ -0,0 +1,41 @@
+package godoc
+
+import "strings"
+
+// stripLicenseHeader removes the leading paragraphs of the doc comment text
+// that are copyright or license notices.
+func stripLicenseHeader(text string) string {
+	for text != "" {
+		para, rest, _ := strings.Cut(text, "\n\n")
+		if !isLicenseParagraph(para) {
+			break
+		}
+
+		text = strings.TrimLeft(rest, "\n")
+	}
+
+	return text
+}
+
+// isLicenseParagraph reports whether the paragraph reads as a copyright or
+// license notice. The conventional "Package name ..." sentence never does.
+func isLicenseParagraph(para string) bool {
+	if strings.HasPrefix(para, "Package ") {
+		return false
+	}
+
+	lower := strings.ToLower(strings.TrimSpace(para))
+	for _, prefix := range licensePrefixes {
+		if strings.HasPrefix(lower, prefix) {
+			return true
+		}
+	}
+
+	for _, phrase := range licensePhrases {
+		if strings.Contains(lower, phrase) {
+			return true
+		}
+	}
+
+	return false
+}
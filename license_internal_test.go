@@ -0,0 +1,37 @@
+package godoc
+
+import "testing"
+
+func TestStripLicenseHeader(t *testing.T) {
+	tests := []struct {
+		name, text, want string
+	}{
+		{"none", "Package p does things.\n", "Package p does things.\n"},
+		{
+			"bsd",
+			"Copyright 2009 The Go Authors. All rights reserved.\nUse of this source code is governed by a BSD-style\nlicense that can be found in the LICENSE file.\n\nPackage p does things.\n",
+			"Package p does things.\n",
+		},
+		{"spdx", "SPDX-License-Identifier: MIT\n\nPackage p does things.\n", "Package p does things.\n"},
+		{
+			"mit",
+			"(c) 2024 Example Corp.\n\nPermission is hereby granted, free of charge, to any person.\n\nTHE SOFTWARE IS PROVIDED \"AS IS\".\n\nPackage p does things.\n",
+			"Package p does things.\n",
+		},
+		{
+			"later notice kept",
+			"Package p does things.\n\nCopyright notices are parsed with [Parse].\n",
+			"Package p does things.\n\nCopyright notices are parsed with [Parse].\n",
+		},
+		{"package sentence kept", "Package copyright detects copyright notices.\n", "Package copyright detects copyright notices.\n"},
+		{"only license", "Copyright 2024 Example Corp.\n", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := stripLicenseHeader(tt.text); got != tt.want {
+				t.Errorf("stripLicenseHeader(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
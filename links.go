@@ -0,0 +1,302 @@
+package godoc
+
+import (
+	"go/doc/comment"
+	"go/token"
+	"slices"
+)
+
+// collectDocLinks walks the given *[comment.Doc] and returns every
+// *[comment.DocLink] it references, in order of appearance.
+func collectDocLinks(d *comment.Doc) []*comment.DocLink {
+	if d == nil {
+		return nil
+	}
+
+	var links []*comment.DocLink
+	for _, block := range d.Content {
+		links = appendBlockLinks(links, block)
+	}
+
+	return links
+}
+
+// appendBlockLinks appends the doc links found in the given [comment.Block].
+func appendBlockLinks(links []*comment.DocLink, block comment.Block) []*comment.DocLink {
+	switch b := block.(type) {
+	case *comment.Paragraph:
+		links = appendTextLinks(links, b.Text)
+	case *comment.Heading:
+		links = appendTextLinks(links, b.Text)
+	case *comment.List:
+		for _, item := range b.Items {
+			for _, content := range item.Content {
+				links = appendBlockLinks(links, content)
+			}
+		}
+	}
+
+	return links
+}
+
+// appendTextLinks appends the doc links found in the given [comment.Text]
+// spans.
+func appendTextLinks(links []*comment.DocLink, text []comment.Text) []*comment.DocLink {
+	for _, t := range text {
+		switch t := t.(type) {
+		case *comment.DocLink:
+			links = append(links, t)
+		case *comment.Link:
+			links = appendTextLinks(links, t.Text)
+		}
+	}
+
+	return links
+}
+
+// parseDocText parses doc text without the originating *[doc.Package]. Since
+// the package symbol table is unavailable, any exported identifier is treated
+// as a symbol of the current package when resolving doc links.
+func parseDocText(text string, lookupSym func(recv, name string) bool) *comment.Doc {
+	if lookupSym == nil {
+		lookupSym = func(_, name string) bool {
+			return token.IsExported(name)
+		}
+	}
+
+	p := comment.Parser{LookupSym: lookupSym}
+
+	return p.Parse(text)
+}
+
+// lookupSym reports whether recv.name (or name, if recv is empty) is a symbol
+// documented in the package.
+func (p PackageDoc) lookupSym(recv, name string) bool {
+	if recv != "" {
+		for _, t := range p.Types {
+			if t.Name != recv {
+				continue
+			}
+
+			for _, m := range t.Methods {
+				if m.Name == name {
+					return true
+				}
+			}
+
+			for _, f := range t.Fields {
+				if f.Name == name {
+					return true
+				}
+			}
+		}
+
+		return false
+	}
+
+	for _, t := range p.Types {
+		if t.Name == name {
+			return true
+		}
+	}
+
+	for _, f := range p.Funcs {
+		if f.Name == name {
+			return true
+		}
+	}
+
+	for _, values := range [][]ValueDoc{p.Consts, p.Vars} {
+		for _, v := range values {
+			for _, n := range v.Names {
+				if n == name {
+					return true
+				}
+			}
+		}
+	}
+
+	return false
+}
+
+// lookupSym reports whether recv.name (or name, if recv is empty) is known to
+// be a symbol of the package from the documentation of the symbol: the
+// symbol itself, its receiver, or its methods and fields. Other names are
+// left unresolved, as the rest of the package is unknown.
+func (s SymbolDoc) lookupSym(recv, name string) bool {
+	typeName := s.Name
+	if s.Kind == "method" {
+		typeName = s.Receiver
+	}
+
+	if recv == "" {
+		return name == s.Name || name == typeName
+	}
+
+	if recv != typeName {
+		return false
+	}
+
+	if s.Kind == "method" {
+		return name == s.Name
+	}
+
+	if s.TypeDoc == nil {
+		return false
+	}
+
+	return slices.ContainsFunc(s.Methods, func(m MethodDoc) bool { return m.Name == name }) ||
+		slices.ContainsFunc(s.Fields, func(f FieldDoc) bool { return f.Name == name })
+}
+
+// HTMLWithLinks returns the HTML documentation for the package together with
+// the doc links (e.g. [fmt.Println] or [Reader.Read]) referenced by the package
+// comment, so callers can prefetch the linked packages and symbols.
+func (p PackageDoc) HTMLWithLinks() (string, []*comment.DocLink) {
+	parsed := p.docParsed
+	if parsed == nil && p.DocText != "" {
+		parsed = parseDocText(p.DocText, p.lookupSym)
+	}
+
+	html := p.HTML()
+	if html == "" && parsed != nil {
+		r := comment.Printer{HeadingLevel: 2}
+		html = string(r.HTML(parsed))
+	}
+
+	return html, collectDocLinks(parsed)
+}
+
+// HTMLWithLinks returns the HTML documentation for the symbol together with
+// the doc links referenced by its doc comment.
+func (s SymbolDoc) HTMLWithLinks() (string, []*comment.DocLink) {
+	parsed := s.docParsed
+	if parsed == nil && s.DocText != "" {
+		parsed = parseDocText(s.DocText, s.lookupSym)
+	}
+
+	return s.HTML(), collectDocLinks(parsed)
+}
+
+// docLinker resolves the doc links of the doc comments of a package to
+// [DocLink] values, for [WithResolveDocLinks].
+type docLinker struct {
+	parser     *comment.Parser
+	importPath string // Import path of the package, for links without one
+}
+
+// newDocLinker returns a docLinker parsing doc comments with parser, if
+// provided.
+func newDocLinker(parser *comment.Parser, importPath string) docLinker {
+	if parser == nil {
+		parser = new(comment.Parser)
+	}
+
+	return docLinker{parser: parser, importPath: importPath}
+}
+
+// links parses text and returns its resolved doc links.
+func (l docLinker) links(text string) []DocLink {
+	if text == "" {
+		return nil
+	}
+
+	return l.resolve(l.parser.Parse(text))
+}
+
+// resolve returns the distinct doc links of d, in order of appearance, with
+// absolute URLs.
+func (l docLinker) resolve(d *comment.Doc) []DocLink {
+	var links []DocLink
+	for _, link := range collectDocLinks(d) {
+		dl := DocLink{Text: manText(link.Text), ImportPath: link.ImportPath, Name: link.Name}
+		if link.Recv != "" {
+			dl.Name = link.Recv + "." + link.Name
+		}
+
+		if dl.ImportPath == "" {
+			dl.ImportPath = l.importPath
+		}
+
+		dl.Target = docLinkBaseURL + "/" + dl.ImportPath
+		if dl.Name != "" {
+			dl.Target += "#" + dl.Name
+		}
+
+		if !slices.Contains(links, dl) {
+			links = append(links, dl)
+		}
+	}
+
+	return links
+}
+
+// pkgDoc sets the doc links of p and of all its documentation.
+func (l docLinker) pkgDoc(p *PackageDoc) {
+	if p.docParsed != nil {
+		p.DocLinks = l.resolve(p.docParsed)
+	} else {
+		p.DocLinks = l.links(p.DocText)
+	}
+
+	for _, values := range [][]ValueDoc{p.Consts, p.Vars} {
+		for i := range values {
+			values[i].DocLinks = l.links(values[i].Doc)
+		}
+	}
+
+	for i := range p.Funcs {
+		p.Funcs[i].DocLinks = l.links(p.Funcs[i].Doc)
+	}
+
+	for i := range p.Types {
+		l.typeDoc(&p.Types[i])
+	}
+}
+
+// typeDoc sets the doc links of t and of its fields, methods, and
+// constructors. The slices of t are copied first, as they may be shared.
+func (l docLinker) typeDoc(t *TypeDoc) {
+	t.DocLinks = l.links(t.Doc)
+
+	t.Fields = slices.Clone(t.Fields)
+	for i := range t.Fields {
+		t.Fields[i].DocLinks = l.links(t.Fields[i].Doc)
+	}
+
+	t.Methods = slices.Clone(t.Methods)
+	for i := range t.Methods {
+		t.Methods[i].DocLinks = l.links(t.Methods[i].Doc)
+	}
+
+	t.Constructors = slices.Clone(t.Constructors)
+	for i := range t.Constructors {
+		t.Constructors[i].DocLinks = l.links(t.Constructors[i].Doc)
+	}
+}
+
+// symbols sets the doc links of the symbols of index, and of the function
+// or type they embed.
+func (l docLinker) symbols(index map[string]SymbolDoc) {
+	for key, sym := range index {
+		if sym.docParsed != nil {
+			sym.DocLinks = l.resolve(sym.docParsed)
+		} else {
+			sym.DocLinks = l.links(sym.DocText)
+		}
+
+		if sym.FuncDoc != nil {
+			fd := *sym.FuncDoc
+			fd.DocLinks = sym.DocLinks
+			sym.FuncDoc = &fd
+		}
+
+		if sym.TypeDoc != nil {
+			td := *sym.TypeDoc
+			l.typeDoc(&td)
+			sym.TypeDoc = &td
+		}
+
+		index[key] = sym
+	}
+}
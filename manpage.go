@@ -0,0 +1,277 @@
+package godoc
+
+import (
+	"fmt"
+	"go/doc/comment"
+	"strings"
+)
+
+const manSection = "3go"
+
+// ManPage returns the package documentation formatted as a man(7) page.
+//
+// The page has NAME, SYNOPSIS, and DESCRIPTION sections followed by one
+// section per non-empty group of constants, variables, functions, and types.
+// Constructors and methods are listed under their types. Like
+// [PackageDoc.Markdown], declarations are shown as written in the source,
+// with signatures rendered from the arguments as a fallback.
+func (p PackageDoc) ManPage() string {
+	var m manWriter
+
+	m.header(p.Name)
+	m.section("NAME")
+	m.text(nameLine(p.ImportPath, p.Synopsis))
+	m.section("SYNOPSIS")
+	m.code(fmt.Sprintf("import %q", p.ImportPath))
+
+	if p.DocText != "" {
+		m.section("DESCRIPTION")
+		m.doc(p.DocText)
+	}
+
+	if len(p.Consts) > 0 {
+		m.section("CONSTANTS")
+		for _, c := range p.Consts {
+			m.value(c)
+		}
+	}
+
+	if len(p.Vars) > 0 {
+		m.section("VARIABLES")
+		for _, v := range p.Vars {
+			m.value(v)
+		}
+	}
+
+	if funcs := p.standaloneFuncs(); len(funcs) > 0 {
+		m.section("FUNCTIONS")
+		for _, f := range funcs {
+			m.subsection(f.Name)
+			m.code(funcDeclOrSignature(f))
+			m.doc(f.Doc)
+		}
+	}
+
+	if len(p.Types) > 0 {
+		m.section("TYPES")
+		for _, t := range p.Types {
+			m.typeDoc(t)
+		}
+	}
+
+	return m.String()
+}
+
+// ManPage returns the symbol documentation formatted as a man(7) page.
+func (s SymbolDoc) ManPage() string {
+	var m manWriter
+
+	title := s.Name
+	if s.Kind == "method" && s.Receiver != "" {
+		title = s.Receiver + "." + s.Name
+	}
+
+	m.header(s.Package + "." + title)
+	m.section("NAME")
+	m.text(nameLine(s.Package+"."+title, Synopsis(s.DocText)))
+	m.section("SYNOPSIS")
+	m.code(fmt.Sprintf("import %q", s.ImportPath))
+
+	if s.TypeDoc != nil {
+		m.code(s.Decl)
+	} else {
+		m.code(symbolSignature(s))
+	}
+
+	if s.DocText != "" {
+		m.section("DESCRIPTION")
+		m.doc(s.DocText)
+	}
+
+	if s.TypeDoc != nil && s.TypeDoc.Kind != "interface" && len(s.Methods) > 0 {
+		m.section("METHODS")
+		for _, meth := range s.Methods {
+			m.subsection(meth.Name)
+			m.code(methodSignature(meth))
+			m.doc(meth.Doc)
+		}
+	}
+
+	return m.String()
+}
+
+// nameLine formats the content of a NAME section.
+func nameLine(name, synopsis string) string {
+	if synopsis == "" {
+		return name
+	}
+
+	return name + " - " + synopsis
+}
+
+// manWriter accumulates man(7) markup.
+type manWriter struct {
+	sb strings.Builder
+}
+
+// String returns the accumulated markup.
+func (m *manWriter) String() string {
+	return m.sb.String()
+}
+
+// line writes s followed by a newline.
+func (m *manWriter) line(s string) {
+	m.sb.WriteString(s)
+	m.sb.WriteByte('\n')
+}
+
+// header writes the .TH title line.
+func (m *manWriter) header(title string) {
+	m.line(fmt.Sprintf(".TH %s %s \"\" \"\" \"Go Documentation\"", manQuote(strings.ToUpper(title)), manSection))
+}
+
+// section starts a new .SH section.
+func (m *manWriter) section(name string) {
+	m.line(".SH " + manQuote(name))
+}
+
+// subsection starts a new .SS subsection.
+func (m *manWriter) subsection(name string) {
+	m.line(".SS " + manQuote(name))
+}
+
+// text writes a filled paragraph.
+func (m *manWriter) text(s string) {
+	for _, l := range strings.Split(strings.TrimSpace(s), "\n") {
+		m.line(manEscapeLine(l))
+	}
+}
+
+// code writes s as a no-fill block, preserving its line breaks.
+func (m *manWriter) code(s string) {
+	if s == "" {
+		return
+	}
+
+	m.line(".PP")
+	m.line(".nf")
+	m.line(".RS 4")
+	for _, l := range strings.Split(strings.TrimRight(s, "\n"), "\n") {
+		m.line(manEscapeLine(l))
+	}
+	m.line(".RE")
+	m.line(".fi")
+}
+
+// doc renders Go doc comment text.
+func (m *manWriter) doc(text string) {
+	if text == "" {
+		return
+	}
+
+	parsed := parseDocText(text, nil)
+	for _, block := range parsed.Content {
+		m.block(block)
+	}
+}
+
+// block renders a single parsed doc comment block.
+func (m *manWriter) block(block comment.Block) {
+	switch b := block.(type) {
+	case *comment.Paragraph:
+		m.line(".PP")
+		m.text(manText(b.Text))
+	case *comment.Heading:
+		m.line(".PP")
+		m.line(`\fB` + manEscape(manText(b.Text)) + `\fR`)
+	case *comment.Code:
+		m.code(b.Text)
+	case *comment.List:
+		for _, item := range b.Items {
+			bullet := `\(bu`
+			if item.Number != "" {
+				bullet = item.Number + "."
+			}
+
+			m.line(".IP " + bullet + " 4")
+			for i, content := range item.Content {
+				if para, ok := content.(*comment.Paragraph); ok && i == 0 {
+					m.text(manText(para.Text))
+					continue
+				}
+
+				m.block(content)
+			}
+		}
+	}
+}
+
+// value renders a constant or variable group.
+func (m *manWriter) value(v ValueDoc) {
+	m.subsection(strings.Join(v.Names, ", "))
+	m.doc(v.Doc)
+}
+
+// typeDoc renders a type along with its methods.
+func (m *manWriter) typeDoc(t TypeDoc) {
+	m.subsection(t.Name)
+	m.code(t.Decl)
+	m.doc(t.Doc)
+
+	for _, f := range t.Constructors {
+		m.code(funcDeclOrSignature(f))
+		m.doc(f.Doc)
+	}
+
+	if t.Kind == "interface" {
+		return
+	}
+
+	for _, meth := range t.Methods {
+		m.code(methodSignature(meth))
+		m.doc(meth.Doc)
+	}
+}
+
+// manText flattens the given [comment.Text] spans into plain text.
+func manText(text []comment.Text) string {
+	var sb strings.Builder
+	for _, t := range text {
+		switch t := t.(type) {
+		case comment.Plain:
+			sb.WriteString(string(t))
+		case comment.Italic:
+			sb.WriteString(string(t))
+		case *comment.Link:
+			sb.WriteString(manText(t.Text))
+		case *comment.DocLink:
+			sb.WriteString(manText(t.Text))
+		}
+	}
+
+	return sb.String()
+}
+
+// manEscape escapes troff special characters in s.
+func manEscape(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\e`)
+	s = strings.ReplaceAll(s, "-", `\-`)
+
+	return s
+}
+
+// manEscapeLine escapes s and guards lines that would otherwise be parsed as
+// troff requests.
+func manEscapeLine(s string) string {
+	s = manEscape(s)
+	if strings.HasPrefix(s, ".") || strings.HasPrefix(s, "'") {
+		s = `\&` + s
+	}
+
+	return s
+}
+
+// manQuote quotes s as a single troff macro argument.
+func manQuote(s string) string {
+	return `"` + strings.ReplaceAll(manEscape(s), `"`, `\(dq`) + `"`
+}
@@ -0,0 +1,96 @@
+package godoc
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPackageDocManPage(t *testing.T) {
+	p := PackageDoc{
+		ImportPath: "example.com/foo",
+		Name:       "foo",
+		Synopsis:   "Package foo does things.",
+		DocText:    "Package foo does things.\n\n.dot leading line and a [Client].\n\n\tcode := `x\\y`\n\nItems:\n  - first\n  - second\n",
+		Consts:     []ValueDoc{{Names: []string{"A", "B"}, Doc: "Enum values.\n"}},
+		Funcs: []FuncDoc{
+			{
+				Name:    "New",
+				Args:    []ArgInfo{{Name: "opts", Type: "...Option"}},
+				Returns: []ArgInfo{{Type: "*Client"}},
+			},
+			{
+				Name:    "Open",
+				Decl:    "func Open(name string) (*Client, error)",
+				Args:    []ArgInfo{{Name: "name", Type: "string"}},
+				Returns: []ArgInfo{{Type: "*example.com/foo.Client"}, {Type: "error"}},
+			},
+		},
+		Types: []TypeDoc{{
+			Name: "Client",
+			Kind: "struct",
+			Decl: "type Client struct{}",
+			Methods: []MethodDoc{{
+				Recv: "Client", RecvName: "c", RecvType: "*Client", Name: "Do",
+				Returns: []ArgInfo{{Type: "error"}},
+			}},
+		}},
+	}
+
+	page := p.ManPage()
+
+	for _, want := range []string{
+		`.TH "FOO" 3go`,
+		".SH \"NAME\"\nexample.com/foo \\- Package foo does things.\n",
+		".SH \"SYNOPSIS\"\n.PP\n.nf\n.RS 4\nimport \"example.com/foo\"\n",
+		"\\&.dot leading line and a Client.\n",
+		"code := `x\\ey`\n",
+		".IP \\(bu 4\nfirst\n",
+		".SS \"A, B\"\n.PP\nEnum values.\n",
+		"func New(opts ...Option) *Client\n",
+		"func Open(name string) (*Client, error)\n",
+		"func (c *Client) Do() error\n",
+		".SH \"TYPES\"\n.SS \"Client\"\n",
+	} {
+		if !strings.Contains(page, want) {
+			t.Errorf("expected man page to contain %q, got:\n%s", want, page)
+		}
+	}
+
+	// Constructors are listed under their type only.
+	p.Types[0].Constructors = p.Funcs[1:]
+	page = p.ManPage()
+	types := strings.Index(page, ".SH \"TYPES\"")
+	if open := strings.Index(page, "func Open("); open < types || strings.Count(page, "func Open(") != 1 {
+		t.Errorf("expected the constructor under its type only, got:\n%s", page)
+	}
+
+	if strings.Contains(page, ".SS \"Open\"") || !strings.Contains(page, ".SS \"New\"") {
+		t.Errorf("expected only New in the FUNCTIONS section, got:\n%s", page)
+	}
+}
+
+func TestSymbolDocManPage(t *testing.T) {
+	sym := SymbolDoc{
+		ImportPath:   "net/http",
+		Package:      "http",
+		Kind:         "method",
+		Name:         "Do",
+		Receiver:     "Client",
+		ReceiverName: "c",
+		ReceiverType: "*net/http.Client",
+		FuncDoc:      &FuncDoc{Name: "Do", Returns: []ArgInfo{{Type: "error"}}},
+		DocText:      "Do sends a request. More text.\n",
+	}
+
+	page := sym.ManPage()
+	for _, want := range []string{
+		`.TH "HTTP.CLIENT.DO" 3go`,
+		"http.Client.Do \\- Do sends a request.\n",
+		"func (c *net/http.Client) Do() error\n",
+		".SH \"DESCRIPTION\"\n",
+	} {
+		if !strings.Contains(page, want) {
+			t.Errorf("expected man page to contain %q, got:\n%s", want, page)
+		}
+	}
+}
@@ -0,0 +1,318 @@
+package godoc
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Markdown returns the package documentation formatted as Markdown.
+//
+// The document starts with the package name and import path followed by the
+// package comment and one section per non-empty group of constants,
+// variables, functions, and types. Constructors and methods are listed under
+// their types. Each constant or variable group is shown as one declaration
+// with its values. Doc links are converted with
+// [ConvertDocLinks] and code blocks are tagged with [AddLangIdentifier].
+func (p PackageDoc) Markdown() string {
+	var md markdownWriter
+
+	md.printf("# package %s\n\n", p.Name)
+	md.printf("```\nimport %q\n```\n\n", p.ImportPath)
+	md.doc(p.DocText)
+
+	if len(p.Consts) > 0 {
+		md.heading("CONSTANTS")
+		for _, c := range p.Consts {
+			md.value("const", c)
+		}
+	}
+
+	if len(p.Vars) > 0 {
+		md.heading("VARIABLES")
+		for _, v := range p.Vars {
+			md.value("var", v)
+		}
+	}
+
+	if funcs := p.standaloneFuncs(); len(funcs) > 0 {
+		md.heading("FUNCTIONS")
+		for _, f := range funcs {
+			md.function(f)
+		}
+	}
+
+	if len(p.Types) > 0 {
+		md.heading("TYPES")
+		for _, t := range p.Types {
+			md.code(t.Decl)
+			md.doc(t.Doc)
+
+			for _, f := range t.Constructors {
+				md.function(f)
+			}
+
+			// Interface methods are part of the declaration.
+			if t.Kind != "interface" {
+				md.methods(t.Methods)
+			}
+		}
+	}
+
+	return md.finish(p.ImportPath)
+}
+
+// Markdown returns the symbol documentation formatted as Markdown.
+//
+// The document starts with the import path of the package followed by the
+// declaration of the symbol and its doc comment. Types also list their
+// methods.
+func (s SymbolDoc) Markdown() string {
+	var md markdownWriter
+
+	md.printf("```\n// import %q\n```\n\n", s.ImportPath)
+
+	if s.Kind == "type" && s.TypeDoc != nil {
+		md.code(s.Decl)
+		md.doc(s.DocText)
+		if s.TypeDoc.Kind != "interface" {
+			md.methods(s.Methods)
+		}
+	} else {
+		md.code(symbolSignature(s))
+		md.doc(s.DocText)
+	}
+
+	for _, t := range s.InlineTypes {
+		md.code(t.Decl)
+	}
+
+	return md.finish(s.ImportPath)
+}
+
+// ConvertDocLinks converts the Go doc link syntax in text ([Name],
+// [Type.Method], and [pkg.Name]) into Markdown links to the documentation on
+// pkg.go.dev. Names without a package are resolved against the package with
+// the given import path; packages are given by import path, as in
+// [net/http.Client].
+//
+// Fenced code blocks and indented code blocks are left unchanged, so that
+// declarations such as "func F[S ~[]E, E any](s S) iter.Seq[E]" are kept
+// intact.
+func ConvertDocLinks(text, importPath string) string {
+	lines := strings.Split(text, "\n")
+	inFence, inIndented, prevBlank := false, false, true
+
+	for i, line := range lines {
+		trimmed := strings.TrimLeft(line, " \t")
+		blank := trimmed == ""
+		indented := strings.HasPrefix(line, "\t") || strings.HasPrefix(line, "    ")
+
+		switch {
+		case inFence:
+			inFence = !strings.HasPrefix(trimmed, "```")
+		case blank:
+			// Blank lines do not end an indented code block.
+		case indented && (prevBlank || inIndented):
+			// An indented code block cannot interrupt a paragraph, so the
+			// indented lines of a list item are not code.
+			inIndented = true
+		case strings.HasPrefix(trimmed, "```"):
+			inFence, inIndented = true, false
+		default:
+			inIndented = false
+			lines[i] = convertDocLinks(line, importPath)
+		}
+
+		prevBlank = blank
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// convertDocLinks converts the doc links of a line of text outside of code
+// blocks, as described by [ConvertDocLinks].
+func convertDocLinks(line, importPath string) string {
+	return docLinksRe.ReplaceAllStringFunc(line, func(match string) string {
+		m := docLinksRe.FindStringSubmatch(match)
+		pkg, name := strings.TrimSuffix(m[1], "."), m[2]
+		if pkg == "" {
+			return fmt.Sprintf(`[%s](%s/%s#%s)`, name, docLinkBaseURL, importPath, name)
+		}
+
+		return fmt.Sprintf(`[%s.%s](%s/%s#%s)`, pkg, name, docLinkBaseURL, pkg, name)
+	})
+}
+
+// AddLangIdentifier tags the fenced code blocks of the given Markdown that
+// have no language identifier as Go code.
+func AddLangIdentifier(markdown string) string {
+	lines := strings.Split(markdown, "\n")
+	inCodeBlock := false
+
+	for i, line := range lines {
+		trimmed := strings.TrimLeft(line, " \t")
+		if !strings.HasPrefix(trimmed, "```") {
+			continue
+		}
+
+		if !inCodeBlock && trimmed == "```" {
+			lines[i] = line[:len(line)-len(trimmed)] + "```go"
+		}
+
+		inCodeBlock = !inCodeBlock
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// standaloneFuncs returns the functions of the package that are not listed
+// as constructors of its types.
+func (p PackageDoc) standaloneFuncs() []FuncDoc {
+	constructors := make(map[string]bool)
+	for _, t := range p.Types {
+		for _, f := range t.Constructors {
+			constructors[f.Name] = true
+		}
+	}
+
+	var funcs []FuncDoc
+	for _, f := range p.Funcs {
+		if !constructors[f.Name] {
+			funcs = append(funcs, f)
+		}
+	}
+
+	return funcs
+}
+
+// funcDeclOrSignature returns the declaration of f, falling back to its
+// rendered signature.
+func funcDeclOrSignature(f FuncDoc) string {
+	if f.Decl != "" {
+		return f.Decl
+	}
+
+	return funcSignature(f)
+}
+
+// symbolSignature returns the declaration of a function or method symbol, or
+// "" for other kinds of symbols.
+func symbolSignature(s SymbolDoc) string {
+	switch {
+	case s.FuncDoc == nil:
+		return ""
+	case s.Decl != "":
+		return s.Decl
+	case s.Kind == "method":
+		return methodSignature(symbolMethod(s))
+	case s.Kind == "func":
+		return funcSignature(*s.FuncDoc)
+	default:
+		return ""
+	}
+}
+
+// symbolMethod returns the method described by the method symbol s.
+func symbolMethod(s SymbolDoc) MethodDoc {
+	return MethodDoc{
+		Recv:        s.Receiver,
+		RecvName:    s.ReceiverName,
+		RecvType:    s.ReceiverType,
+		RecvPointer: s.RecvPointer,
+		Name:        s.Name,
+		Args:        s.Args,
+		Returns:     s.Returns,
+		Variadic:    s.Variadic,
+	}
+}
+
+// markdownWriter accumulates Markdown.
+type markdownWriter struct {
+	sb strings.Builder
+}
+
+// printf writes formatted text.
+func (m *markdownWriter) printf(format string, args ...any) {
+	fmt.Fprintf(&m.sb, format, args...)
+}
+
+// heading writes a top-level section heading.
+func (m *markdownWriter) heading(title string) {
+	m.printf("# %s\n\n", title)
+}
+
+// code writes s as a Go code block, if non-empty.
+func (m *markdownWriter) code(s string) {
+	if s != "" {
+		m.printf("```go\n%s\n```\n\n", s)
+	}
+}
+
+// doc writes doc comment text as a paragraph, if non-empty.
+func (m *markdownWriter) doc(text string) {
+	if text != "" {
+		m.printf("%s\n\n", text)
+	}
+}
+
+// function writes the declaration and doc comment of a function.
+func (m *markdownWriter) function(f FuncDoc) {
+	m.code(funcDeclOrSignature(f))
+	m.doc(f.Doc)
+}
+
+// value writes a constant or variable group as declared in the source. A
+// group without a declaration is written as a single declaration with keyword,
+// with the known values aligned as by gofmt.
+func (m *markdownWriter) value(keyword string, v ValueDoc) {
+	if len(v.Names) == 0 {
+		return
+	}
+
+	if v.Decl != "" {
+		m.code(v.Decl)
+		m.doc(v.Doc)
+
+		return
+	}
+
+	width := 0
+	for _, name := range v.Names {
+		width = max(width, len(name))
+	}
+
+	specs := make([]string, len(v.Names))
+	for i, name := range v.Names {
+		specs[i] = name
+		if i < len(v.Values) && v.Values[i] != "" {
+			specs[i] = fmt.Sprintf("%-*s = %s", width, name, v.Values[i])
+		}
+	}
+
+	if len(specs) == 1 {
+		m.code(keyword + " " + specs[0])
+	} else {
+		m.code(keyword + " (\n\t" + strings.Join(specs, "\n\t") + "\n)")
+	}
+
+	m.doc(v.Doc)
+}
+
+// methods writes the signatures and doc comments of the given methods.
+func (m *markdownWriter) methods(methods []MethodDoc) {
+	for _, meth := range methods {
+		m.code(methodSignature(meth))
+		m.doc(meth.Doc)
+	}
+}
+
+// finish returns the accumulated Markdown with doc links resolved against
+// importPath and untagged code blocks tagged as Go.
+func (m *markdownWriter) finish(importPath string) string {
+	md := m.sb.String()
+	if importPath != "" {
+		md = ConvertDocLinks(md, importPath)
+	}
+
+	return AddLangIdentifier(md)
+}
@@ -0,0 +1,157 @@
+package godoc
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPackageDocMarkdown(t *testing.T) {
+	p := PackageDoc{
+		ImportPath: "example.com/foo",
+		Name:       "foo",
+		DocText:    "Package foo does things with a [Client].\n\n```\nx := 1\n```",
+		Consts:     []ValueDoc{{Names: []string{"A", "Bee"}, Values: []string{"0", "1"}, Doc: "Enum values."}},
+		Vars:       []ValueDoc{{Names: []string{"ErrFoo"}, Doc: "ErrFoo is returned on failure.", Decl: `var ErrFoo = errors.New("foo")`}},
+		Funcs: []FuncDoc{{
+			Name:    "New",
+			Args:    []ArgInfo{{Name: "opts", Type: "...Option"}},
+			Returns: []ArgInfo{{Type: "*Client"}},
+		}},
+		Types: []TypeDoc{
+			{
+				Name: "Client",
+				Kind: "struct",
+				Decl: "type Client struct{}",
+				Methods: []MethodDoc{{
+					Recv: "Client", RecvName: "c", RecvType: "*Client", Name: "Do",
+					Returns: []ArgInfo{{Type: "error"}},
+				}},
+			},
+			{
+				Name:    "Doer",
+				Kind:    "interface",
+				Decl:    "type Doer interface{ Do() error }",
+				Methods: []MethodDoc{{Name: "Do", Returns: []ArgInfo{{Type: "error"}}}},
+			},
+		},
+	}
+
+	md := p.Markdown()
+
+	for _, want := range []string{
+		"# package foo\n\n```go\nimport \"example.com/foo\"\n```\n\n",
+		"a [Client](https://pkg.go.dev/example.com/foo#Client).",
+		"```go\nx := 1\n```",
+		"# CONSTANTS\n\n```go\nconst (\n\tA   = 0\n\tBee = 1\n)\n```\n\nEnum values.\n\n",
+		"# VARIABLES\n\n```go\nvar ErrFoo = errors.New(\"foo\")\n```\n\nErrFoo is returned on failure.\n\n",
+		"# FUNCTIONS\n\n```go\nfunc New(opts ...Option) *Client\n```\n\n",
+		"```go\nfunc (c *Client) Do() error\n```\n\n",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("expected markdown to contain %q, got:\n%s", want, md)
+		}
+	}
+
+	p.Vars = nil
+	if md := p.Markdown(); strings.Contains(md, "VARIABLES") {
+		t.Errorf("expected no empty VARIABLES section, got:\n%s", md)
+	}
+
+	if strings.Contains(md, "func Do() error") {
+		t.Errorf("expected interface methods to be omitted, got:\n%s", md)
+	}
+
+	// Constructors are listed under their type only.
+	p.Types[0].Constructors = p.Funcs
+	md = p.Markdown()
+	if strings.Contains(md, "# FUNCTIONS") {
+		t.Errorf("expected no FUNCTIONS section for constructors, got:\n%s", md)
+	}
+
+	if want := "```go\ntype Client struct{}\n```\n\n```go\nfunc New(opts ...Option) *Client\n```\n\n```go\nfunc (c *Client) Do() error\n```"; !strings.Contains(md, want) {
+		t.Errorf("expected the constructor under its type, got:\n%s", md)
+	}
+}
+
+func TestSymbolDocMarkdown(t *testing.T) {
+	sym := SymbolDoc{
+		ImportPath:   "net/http",
+		Package:      "http",
+		Kind:         "method",
+		Name:         "Do",
+		Receiver:     "Client",
+		ReceiverName: "c",
+		ReceiverType: "*net/http.Client",
+		FuncDoc:      &FuncDoc{Name: "Do", Returns: []ArgInfo{{Type: "error"}}},
+		DocText:      "Do sends a [Request].",
+	}
+
+	want := "```go\n// import \"net/http\"\n```\n\n" +
+		"```go\nfunc (c *net/http.Client) Do() error\n```\n\n" +
+		"Do sends a [Request](https://pkg.go.dev/net/http#Request).\n\n"
+	if got := sym.Markdown(); got != want {
+		t.Errorf("expected markdown:\n%s\ngot:\n%s", want, got)
+	}
+
+	sym.Decl = "func (c *Client) Do() error"
+	if got := sym.Markdown(); !strings.Contains(got, "```go\nfunc (c *Client) Do() error\n```") {
+		t.Errorf("expected declaration to take precedence, got:\n%s", got)
+	}
+}
+
+func TestAddLangIdentifier(t *testing.T) {
+	in := "```\na\n```\n  ```\n  b\n  ```\n```sh\nc\n```"
+	want := "```go\na\n```\n  ```go\n  b\n  ```\n```sh\nc\n```"
+	if got := AddLangIdentifier(in); got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
+
+func TestMethodSignatureRecvPointer(t *testing.T) {
+	m := MethodDoc{Recv: "Client", RecvName: "c", RecvPointer: true, Name: "Close"}
+	if got, want := methodSignature(m), "func (c *Client) Close()"; got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+
+	m.RecvPointer = false
+	if got, want := methodSignature(m), "func (c Client) Close()"; got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+}
+
+func TestConvertDocLinks(t *testing.T) {
+	tests := []struct {
+		text, want string
+	}{
+		{"See [Client].", "See [Client](https://pkg.go.dev/example.com/foo#Client)."},
+		{"See [Client.Do].", "See [Client.Do](https://pkg.go.dev/example.com/foo#Client.Do)."},
+		{"See [io.Reader].", "See [io.Reader](https://pkg.go.dev/io#Reader)."},
+		{"See [net/http.Client.Do].", "See [net/http.Client.Do](https://pkg.go.dev/net/http#Client.Do)."},
+		{"See [golang.org/x/mod/module.Version].", "See [golang.org/x/mod/module.Version](https://pkg.go.dev/golang.org/x/mod/module#Version)."},
+		{"See [pkg.go.dev] or [x].", "See [pkg.go.dev] or [x]."},
+
+		// Code blocks are left unchanged.
+		{
+			"```go\nfunc Values[Slice ~[]E, E any](s Slice) iter.Seq[E]\n```\n\nSee [Client].",
+			"```go\nfunc Values[Slice ~[]E, E any](s Slice) iter.Seq[E]\n```\n\nSee [Client](https://pkg.go.dev/example.com/foo#Client).",
+		},
+		{
+			"```go\nconst (\n\t// MaxSize is the limit, see [Scanner.Buffer].\n\tMaxSize = 64\n)\n```",
+			"```go\nconst (\n\t// MaxSize is the limit, see [Scanner.Buffer].\n\tMaxSize = 64\n)\n```",
+		},
+		{
+			"Use it as in:\n\n\tx := m[Key]\n\n    y := s[E]\n\nSee [Client].",
+			"Use it as in:\n\n\tx := m[Key]\n\n    y := s[E]\n\nSee [Client](https://pkg.go.dev/example.com/foo#Client).",
+		},
+		{
+			"Items:\n  - first item about\n    [Client].",
+			"Items:\n  - first item about\n    [Client](https://pkg.go.dev/example.com/foo#Client).",
+		},
+	}
+
+	for _, tt := range tests {
+		if got := ConvertDocLinks(tt.text, "example.com/foo"); got != tt.want {
+			t.Errorf("ConvertDocLinks(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
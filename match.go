@@ -0,0 +1,84 @@
+package godoc
+
+import (
+	"slices"
+	"strings"
+)
+
+// SymbolMatch selects how [Godoc.Load] matches a selector to the symbols of
+// a package when no symbol has exactly the selected name.
+type SymbolMatch int
+
+const (
+	// MatchExact only matches the symbol with exactly the selected name. It
+	// is the default.
+	MatchExact SymbolMatch = iota
+
+	// MatchCaseInsensitive also matches the symbol whose name differs from
+	// the selector in case only, e.g. "println" selects fmt.Println.
+	MatchCaseInsensitive
+
+	// MatchPrefix is like [MatchCaseInsensitive], but also matches the
+	// symbol whose name starts with the selector, ignoring case, e.g.
+	// "newrepl" selects strings.NewReplacer.
+	MatchPrefix
+)
+
+// matchMethod returns the key ("Type.Method") of the only method of
+// symbols named sel, so that a method can be selected without its receiver,
+// or the candidate keys, sorted, if several types have such a method. It
+// matches nothing if sel is qualified.
+func matchMethod(sel string, symbols map[string]SymbolDoc) (string, []string) {
+	if strings.Contains(sel, ".") {
+		return "", nil
+	}
+
+	var candidates []string
+	for key, sym := range symbols {
+		if sym.Kind == "method" && strings.HasSuffix(key, "."+sel) {
+			candidates = append(candidates, key)
+		}
+	}
+
+	if len(candidates) == 1 {
+		return candidates[0], nil
+	}
+
+	slices.Sort(candidates)
+
+	return "", candidates
+}
+
+// matchSymbol returns the name among names that sel matches according to
+// mode, or the candidate names, sorted, if sel matches several of them.
+// Names equal to sel but for case take precedence over prefix matches.
+func matchSymbol(sel string, names []string, mode SymbolMatch) (string, []string) {
+	if mode == MatchExact {
+		return "", nil
+	}
+
+	lowerSel := strings.ToLower(sel)
+
+	var folded, prefixed []string
+	for _, name := range names {
+		switch {
+		case strings.EqualFold(name, sel):
+			folded = append(folded, name)
+		case mode == MatchPrefix && strings.HasPrefix(strings.ToLower(name), lowerSel):
+			prefixed = append(prefixed, name)
+		}
+	}
+
+	candidates := folded
+	if len(candidates) == 0 {
+		candidates = prefixed
+	}
+
+	if len(candidates) == 1 {
+		return candidates[0], nil
+	}
+
+	slices.Sort(candidates)
+
+	return "", candidates
+}
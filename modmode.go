@@ -0,0 +1,38 @@
+package godoc
+
+// ModMode selects how the go command resolves and updates module
+// dependencies when loading packages, as with its -mod flag.
+type ModMode int
+
+const (
+	// ModDefault lets the go command choose: vendor mode if the main module
+	// has a vendor directory consistent with go.mod, and readonly mode
+	// otherwise. It is the default.
+	ModDefault ModMode = iota
+
+	// ModMod lets the go command update go.mod and go.sum as needed.
+	ModMod
+
+	// ModReadonly forbids updates of go.mod, failing instead.
+	ModReadonly
+
+	// ModVendor loads dependencies from the vendor directory of the main
+	// module only. Packages not provided by the main module or the vendor
+	// directory are not fetched, so that builds can be air-gapped.
+	ModVendor
+)
+
+// flag returns the value of the -mod flag of the go command for m, or an
+// empty string for [ModDefault].
+func (m ModMode) flag() string {
+	switch m {
+	case ModMod:
+		return "mod"
+	case ModReadonly:
+		return "readonly"
+	case ModVendor:
+		return "vendor"
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,296 @@
+package godoc
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+	"golang.org/x/tools/go/packages"
+)
+
+// ModuleDoc describes a module as declared by its go.mod file.
+type ModuleDoc struct {
+	Path      string          `json:"path" jsonschema:"module path"`
+	Version   string          `json:"version,omitempty" jsonschema:"resolved module version; empty for the main module"`
+	GoVersion string          `json:"go_version,omitempty" jsonschema:"version in the go directive"`
+	Requires  []ModuleRequire `json:"requires,omitempty" jsonschema:"required modules in go.mod order"`
+}
+
+// ModuleRequire is a module requirement from a go.mod require block.
+type ModuleRequire struct {
+	Path     string `json:"path" jsonschema:"required module path"`
+	Version  string `json:"version" jsonschema:"required module version"`
+	Indirect bool   `json:"indirect,omitempty" jsonschema:"whether the requirement is marked // indirect"`
+}
+
+// ModuleInfo returns the path, version, Go directive, and requirements of
+// the module with the given path, read from its go.mod file.
+//
+// The module path "." denotes the main module of the working directory,
+// whose go.mod is read directly and version is ignored. Other modules are
+// downloaded into the module cache. Version is interpreted as in
+// [Godoc.Load]; if empty, it defaults to the version required by the working
+// directory's go.mod, or "latest" if the module is not required there.
+func (d *Godoc) ModuleInfo(modulePath, version string) (ModuleDoc, error) {
+	d, cancel := d.withTimeout()
+	defer cancel()
+
+	if err := validateInputs(modulePath, ""); err != nil {
+		return ModuleDoc{}, err
+	}
+
+	if modulePath == "." {
+		return readModuleDoc(filepath.Join(d.workdir, "go.mod"), "")
+	}
+
+	version = normalizeVersion(version)
+	if version == "" {
+		if modPath, modVersion := requiredModule(d.workdir, modulePath); modPath == modulePath {
+			version = modVersion
+		}
+	}
+
+	if version == "" {
+		version = "latest"
+	}
+
+	target := modulePath + "@" + version
+
+	release, err := d.acquireFetchSlot()
+	if err != nil {
+		return ModuleDoc{}, fmt.Errorf("go mod download %s: %w", target, err)
+	}
+	defer release()
+
+	out, err := d.goOutput(d.workdir, "mod", "download", "-json", target)
+
+	var info struct {
+		Version string
+		GoMod   string
+		Error   string
+	}
+
+	if jsonErr := json.Unmarshal(out, &info); jsonErr != nil && err == nil {
+		err = jsonErr
+	}
+
+	switch {
+	case info.Error != "":
+		return ModuleDoc{}, fmt.Errorf("go mod download %s: %s", target, info.Error)
+	case err != nil:
+		return ModuleDoc{}, fmt.Errorf("go mod download %s: %w", target, err)
+	}
+
+	return readModuleDoc(info.GoMod, info.Version)
+}
+
+// LoadModule returns the documentation of every package of the module with
+// the given path, ordered by import path, e.g. to generate a documentation
+// site for the whole module.
+//
+// The module path "." denotes the main module of the working directory.
+// Packages of nested modules are not included, nor are packages without Go
+// files for the target platform, nor internal packages unless enabled with
+// [WithIncludeInternal]. Version is interpreted as in [Godoc.Load];
+// the module is resolved once, so all packages are documented at the same
+// version. Each package is cached as if loaded with [Godoc.Load].
+//
+// Loading stops at the first package that fails to load, or when the
+// context of the instance is done.
+func (d *Godoc) LoadModule(modulePath, version string) ([]PackageDoc, error) {
+	d, cancel := d.withTimeout()
+	defer cancel()
+
+	if err := validateInputs(modulePath, ""); err != nil {
+		return nil, err
+	}
+
+	return d.loadPackageTree(modulePath, version)
+}
+
+// loadPackageTree returns the documentation of the package with the given
+// import path and of the packages below it in the same module, as described
+// for [Godoc.LoadModule]. The path may be that of a module, or "." for the
+// main module.
+func (d *Godoc) loadPackageTree(root, version string) ([]PackageDoc, error) {
+	version = normalizeVersion(version)
+
+	dir := d.workdir
+	if root != "." {
+		checkDep := d.checkDep
+		if checkDep == nil {
+			checkDep = d.checkModuleDep
+		}
+
+		modDir, cleanup, err := checkDep(root, version)
+		if err != nil {
+			return nil, fmt.Errorf("module dependency setup failed: %w", err)
+		}
+
+		if cleanup != nil && modDir != d.workdir {
+			defer cleanup()
+		}
+
+		dir = modDir
+	}
+
+	importPaths, err := d.treePackages(root, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	// Load the packages from the directory the module was resolved in,
+	// rather than resolving the module again for each of them.
+	md := *d
+	md.checkDep = func(string, string) (string, func(), error) {
+		return dir, nil, nil
+	}
+
+	docs := make([]PackageDoc, 0, len(importPaths))
+	for _, importPath := range importPaths {
+		if err := d.context().Err(); err != nil {
+			return nil, err
+		}
+
+		pkgDoc, _, err := md.getOrLoadPkg(importPath, version)
+		if err != nil {
+			return nil, fmt.Errorf("load %s: %w", importPath, err)
+		}
+
+		docs = append(docs, pkgDoc)
+	}
+
+	return docs, nil
+}
+
+// treePackages returns the sorted import paths of the package with the
+// given import path and of the packages below it in the same module, or of
+// the packages of the main module for ".", as resolved in dir.
+func (d *Godoc) treePackages(root, dir string) ([]string, error) {
+	pattern := root + "/..."
+	if root == "." {
+		pattern = "./..."
+	}
+
+	cfg, err := d.packagesConfig(pattern, dir, packages.NeedName|packages.NeedFiles|packages.NeedModule)
+	if err != nil {
+		return nil, err
+	}
+
+	pkgs, err := packages.Load(cfg, pattern)
+	if err != nil {
+		if ctxErr := d.context().Err(); ctxErr != nil {
+			err = ctxErr
+		}
+
+		return nil, fmt.Errorf("list packages of %s: %w", root, err)
+	}
+
+	var importPaths []string
+	for _, p := range pkgs {
+		switch {
+		case p.Module == nil, len(p.GoFiles) == 0:
+			continue
+		case root == "." && !p.Module.Main, root != "." && root != p.Module.Path && !strings.HasPrefix(root, p.Module.Path+"/"):
+			// Package of a nested module.
+			continue
+		case !d.includeInternal && isInternalPath(strings.TrimPrefix(p.PkgPath, root)):
+			continue
+		}
+
+		importPaths = append(importPaths, p.PkgPath)
+	}
+
+	slices.Sort(importPaths)
+
+	return importPaths, nil
+}
+
+// isInternalPath reports whether importPath denotes an internal package, or
+// a package within one.
+func isInternalPath(importPath string) bool {
+	return slices.Contains(strings.Split(importPath, "/"), "internal")
+}
+
+// readModuleDoc parses the go.mod file at the given path into a [ModuleDoc]
+// for the given module version.
+func readModuleDoc(path, version string) (ModuleDoc, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ModuleDoc{}, err
+	}
+
+	return parseModuleDoc(path, data, version)
+}
+
+// parseModuleDoc parses the contents of a go.mod file into a [ModuleDoc] for
+// the given module version.
+func parseModuleDoc(path string, data []byte, version string) (ModuleDoc, error) {
+	f, err := modfile.ParseLax(path, data, nil)
+	if err != nil {
+		return ModuleDoc{}, err
+	}
+
+	var mod ModuleDoc
+	if f.Module != nil {
+		mod.Path = f.Module.Mod.Path
+	}
+
+	mod.Version = version
+	if f.Go != nil {
+		mod.GoVersion = f.Go.Version
+	}
+
+	for _, r := range f.Require {
+		mod.Requires = append(mod.Requires, ModuleRequire{
+			Path:     r.Mod.Path,
+			Version:  r.Mod.Version,
+			Indirect: r.Indirect,
+		})
+	}
+
+	return mod, nil
+}
+
+// ListVersions returns the released versions of the module with the given
+// path known to the module proxy, in semantic version order. Pseudo-versions
+// are not listed, so the result is empty for a module without tagged
+// releases.
+func (d *Godoc) ListVersions(modulePath string) ([]string, error) {
+	d, cancel := d.withTimeout()
+	defer cancel()
+
+	if err := validateInputs(modulePath, ""); err != nil {
+		return nil, err
+	}
+
+	release, err := d.acquireFetchSlot()
+	if err != nil {
+		return nil, fmt.Errorf("go list -m -versions %s: %w", modulePath, err)
+	}
+	defer release()
+
+	out, err := d.goOutput(d.workdir, "list", "-m", "-e", "-json", "-versions", modulePath)
+
+	var info struct {
+		Versions []string
+		Error    *struct{ Err string }
+	}
+
+	if jsonErr := json.Unmarshal(out, &info); jsonErr != nil && err == nil {
+		err = jsonErr
+	}
+
+	switch {
+	case info.Error != nil:
+		return nil, fmt.Errorf("go list -m -versions %s: %s", modulePath, info.Error.Err)
+	case err != nil:
+		return nil, fmt.Errorf("go list -m -versions %s: %w", modulePath, err)
+	}
+
+	return info.Versions, nil
+}
@@ -0,0 +1,140 @@
+package godoc
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestParseModuleDoc(t *testing.T) {
+	data := []byte(`module example.com/foo
+
+go 1.22
+
+require example.com/bar v1.2.3
+
+require (
+	example.com/baz v0.1.0 // indirect
+	example.com/qux v2.0.0+incompatible
+)
+
+replace example.com/bar => ../bar
+`)
+
+	mod, err := parseModuleDoc("go.mod", data, "v1.0.0")
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+
+	if mod.Path != "example.com/foo" || mod.Version != "v1.0.0" || mod.GoVersion != "1.22" {
+		t.Errorf("unexpected module %+v", mod)
+	}
+
+	want := []ModuleRequire{
+		{Path: "example.com/bar", Version: "v1.2.3"},
+		{Path: "example.com/baz", Version: "v0.1.0", Indirect: true},
+		{Path: "example.com/qux", Version: "v2.0.0+incompatible"},
+	}
+	if !slices.Equal(mod.Requires, want) {
+		t.Errorf("expected requires %v, got %v", want, mod.Requires)
+	}
+
+	if _, err := parseModuleDoc("go.mod", []byte("module"), ""); err == nil {
+		t.Error("expected error for malformed go.mod")
+	}
+}
+
+func TestLoadModule(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod":             "module example.com/app\n\ngo 1.21\n",
+		"app.go":             "// Package app is the root package.\npackage app\n",
+		"sub/sub.go":         "// Package sub is a sub-package.\npackage sub\n\n// Sub is exported.\nfunc Sub() {}\n",
+		"internal/x/x.go":    "// Package x is internal.\npackage x\n",
+		"ignored/ignored.go": "//go:build ignore\n\npackage ignored\n",
+		"nested/go.mod":      "module example.com/app/nested\n\ngo 1.21\n",
+		"nested/nested.go":   "// Package nested is another module.\npackage nested\n",
+	}
+
+	for name, src := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("failed creating directory of %s: %v", name, err)
+		}
+
+		if err := os.WriteFile(path, []byte(src), 0o644); err != nil {
+			t.Fatalf("failed writing %s: %v", name, err)
+		}
+	}
+
+	g := New(WithWorkdir(dir), WithCacheMode(CacheDisabled))
+	docs, err := g.LoadModule(".", "")
+	if err != nil {
+		t.Fatalf("LoadModule failed: %v", err)
+	}
+
+	var importPaths []string
+	for _, doc := range docs {
+		importPaths = append(importPaths, doc.ImportPath)
+	}
+
+	want := []string{"example.com/app", "example.com/app/sub"}
+	if !slices.Equal(importPaths, want) {
+		t.Fatalf("expected packages %v, got %v", want, importPaths)
+	}
+
+	if len(docs[1].Funcs) != 1 || docs[1].Funcs[0].Name != "Sub" {
+		t.Errorf("expected documentation of sub, got %+v", docs[1].Funcs)
+	}
+
+	withInternal := New(WithWorkdir(dir), WithCacheMode(CacheDisabled), WithIncludeInternal(true))
+	docs, err = withInternal.LoadModule(".", "")
+	if err != nil {
+		t.Fatalf("LoadModule with internal packages failed: %v", err)
+	}
+
+	if len(docs) != 3 || docs[1].ImportPath != "example.com/app/internal/x" {
+		t.Errorf("expected the internal package to be included, got %d packages", len(docs))
+	}
+
+	// Internal packages are loaded by import path from anywhere in the
+	// module, regardless of WithIncludeInternal.
+	sub := New(WithWorkdir(filepath.Join(dir, "sub")), WithCacheMode(CacheDisabled))
+	if _, err := sub.Load("example.com/app/internal/x", "", ""); err != nil {
+		t.Errorf("expected the internal package to load from a subdirectory, got %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	g.SetOptions(WithContext(ctx))
+	if _, err := g.LoadModule(".", ""); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected LoadModule to be canceled, got %v", err)
+	}
+}
+
+func TestModuleInfoWaitsForFetchSlot(t *testing.T) {
+	g := New(WithMaxConcurrentFetches(1), WithTimeout(time.Millisecond))
+
+	// Occupy the only slot, so ModuleInfo has to wait until the timeout.
+	g.fetchSem <- struct{}{}
+
+	if _, err := g.ModuleInfo("example.com/foo", "v1.0.0"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the timeout while waiting for a fetch slot, got %v", err)
+	}
+}
+
+func TestListVersionsWaitsForFetchSlot(t *testing.T) {
+	g := New(WithMaxConcurrentFetches(1), WithTimeout(time.Millisecond))
+
+	// Occupy the only slot, so ListVersions has to wait until the timeout.
+	g.fetchSem <- struct{}{}
+
+	if _, err := g.ListVersions("example.com/foo"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the timeout while waiting for a fetch slot, got %v", err)
+	}
+}
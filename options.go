@@ -1,6 +1,17 @@
 package godoc
 
-import "context"
+import (
+	"cmp"
+	"context"
+	"go/build"
+	"go/types"
+	"io/fs"
+	"maps"
+	"runtime"
+	"slices"
+	"strings"
+	"time"
+)
 
 // Option is a function that configures a Godoc instance.
 type Option func(*Godoc)
@@ -38,6 +49,395 @@ func WithContext(ctx context.Context) Option {
 	}
 }
 
+// WithTimeout limits the duration of each call loading documentation, such
+// as [Godoc.Load], including fetching remote modules with "go get". The
+// deadline is derived from the base context set with [WithContext] at the
+// start of every call, so each call gets the full timeout. A zero or
+// negative duration means no timeout.
+//
+// With [Godoc.LoadMultiple], the timeout applies to each request.
+func WithTimeout(d time.Duration) Option {
+	return func(g *Godoc) {
+		g.timeout = d
+	}
+}
+
+// WithMaxConcurrentFetches limits the number of concurrent "go get"
+// invocations used to fetch remote modules. Loads that are served from the
+// cache are not limited.
+//
+// By default, all [Godoc] instances share a limit of
+// [DefaultMaxConcurrentFetches]. A positive n gives the instance its own
+// limit, and n <= 0 removes the limit.
+func WithMaxConcurrentFetches(n int) Option {
+	return func(g *Godoc) {
+		if n <= 0 {
+			g.fetchSem = nil
+			return
+		}
+
+		g.fetchSem = make(chan struct{}, n)
+	}
+}
+
+// WithSumDB sets the checksum database (GOSUMDB) used when fetching remote
+// modules, e.g. a mirror such as "sum.golang.google.cn". An empty value keeps
+// the setting of the environment.
+//
+// Setting it to "off" allows documenting internal or unpublished modules
+// that have no public checksums, at the cost of disabling checksum
+// verification for every fetched module: a compromised proxy or origin could
+// then serve tampered code without being detected. Prefer narrowing the
+// exemption with GONOSUMDB or GOPRIVATE in the environment where possible.
+func WithSumDB(sumDB string) Option {
+	return func(g *Godoc) {
+		g.sumDB = sumDB
+	}
+}
+
+// WithGoProxy sets the module proxy (GOPROXY) used when fetching remote
+// modules, e.g. an internal Athens proxy such as "https://athens.internal",
+// or a comma- or pipe-separated list with fallbacks, as accepted by the go
+// command. It only applies to the go commands run by the instance, so the
+// process environment is left unchanged. An empty value keeps the setting of
+// the environment.
+func WithGoProxy(url string) Option {
+	return func(g *Godoc) {
+		g.goProxy = url
+	}
+}
+
+// WithGoEnv sets go environment variables, such as GOFLAGS, GOPROXY, or
+// GOPRIVATE, for the go commands used to load packages and fetch remote
+// modules. They override the variables of the process environment, and
+// replace those set by previous calls.
+//
+// Options setting a variable explicitly take precedence over env: GOOS and
+// GOARCH are overridden by [WithGOOS] and [WithGOARCH] when those are set,
+// GOSUMDB by [WithSumDB], GOPROXY by [WithGoProxy], and GOTOOLCHAIN by
+// [WithGoBinary]. GOWORK and
+// GO111MODULE default to "off" and "on", but may be overridden here.
+//
+// Since variables such as GOFLAGS can change the documented files, docs
+// built with variables affecting the build are cached separately.
+func WithGoEnv(env map[string]string) Option {
+	return func(g *Godoc) {
+		g.goEnv = maps.Clone(env)
+	}
+}
+
+// WithGoBinary sets the path of the go binary used to fetch remote modules
+// ("go mod init" and "go get"). An empty path uses "go" from PATH.
+//
+// Package loading always starts the go command found in PATH, which then
+// switches to the configured binary through GOTOOLCHAIN, so the go command in
+// PATH must be Go 1.21 or later. Standard library docs are thus those of the
+// configured toolchain.
+func WithGoBinary(path string) Option {
+	return func(g *Godoc) {
+		g.goBinary = path
+	}
+}
+
+// WithIncludePlatforms merges the struct fields declared on the given
+// platforms into the documented types, for types such as syscall.Stat_t
+// whose fields differ per platform. Platforms are given as "goos/goarch" or
+// "goos", in which case the configured GOARCH is used.
+//
+// Fields that are not declared on every platform list the platforms
+// declaring them in [FieldDoc.Platforms]. Platforms the package cannot be
+// loaded for are ignored.
+func WithIncludePlatforms(platforms ...string) Option {
+	return func(g *Godoc) {
+		g.platforms = platforms
+	}
+}
+
+// WithBuildTags sets additional build tags, such as "integration" or
+// "purego", so that files guarded by build constraints on them are
+// documented. The tags are passed to the go command as a single
+// comma-separated -tags flag.
+func WithBuildTags(tags ...string) Option {
+	return func(g *Godoc) {
+		g.buildTags = nil
+		for _, tag := range tags {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				g.buildTags = append(g.buildTags, tag)
+			}
+		}
+	}
+}
+
+// WithModMode sets how the go command resolves module dependencies, as
+// with its -mod flag. With [ModVendor], packages are loaded from the vendor
+// directory of the module in the working directory, and packages it does
+// not provide are not fetched with go get.
+func WithModMode(mode ModMode) Option {
+	return func(g *Godoc) {
+		g.modMode = mode
+	}
+}
+
+// WithFilePattern restricts the documentation to the symbols declared in the
+// package files whose base name matches pattern, such as "*_linux.go" or
+// "_linux.go" (a pattern without metacharacters is matched as a suffix).
+//
+// Only files built for the configured platform are considered, so pair it
+// with [WithGOOS] or [WithGOARCH] to document files for another platform.
+func WithFilePattern(pattern string) Option {
+	return func(g *Godoc) {
+		g.filePattern = pattern
+	}
+}
+
+// WithImplementers enables listing the concrete types that implement each
+// documented interface. Candidates are the types declared in the package
+// itself and in its direct imports; the search is opt-in to bound its cost.
+func WithImplementers(enabled bool) Option {
+	return func(g *Godoc) {
+		g.implementers = enabled
+	}
+}
+
+// WithRawComments preserves the raw comments of struct fields, including
+// comment markers and directives such as "//go:" pragmas or "//nolint", in
+// [FieldDoc.RawDoc].
+func WithRawComments(enabled bool) Option {
+	return func(g *Godoc) {
+		g.rawComments = enabled
+	}
+}
+
+// WithEvictionCallback sets a function called with the key of every cache
+// entry evicted to make room for documentation loaded by the instance, e.g.
+// to track cache churn in metrics or logs. Keys are opaque identifiers.
+//
+// The callback runs synchronously on the loading goroutine. Evictions from
+// the persistent cache are detected by comparing its keys once it is full,
+// so entries evicted by concurrent loads may be reported by either load.
+func WithEvictionCallback(fn func(key string)) Option {
+	return func(g *Godoc) {
+		g.onEvict = fn
+	}
+}
+
+// WithCacheMode sets how the instance caches the documentation it loads:
+// [CachePersistent] (the default), [CacheMemory], or [CacheDisabled].
+//
+// The in-memory cache is shared by all instances in [CacheMemory] mode, and
+// is cleared by [ClearCache] and [InvalidateImportPath] as well.
+func WithCacheMode(mode CacheMode) Option {
+	return func(g *Godoc) {
+		g.cacheMode = mode
+	}
+}
+
+// WithCacheDir sets the directory of the persistent cache file, e.g. a
+// project-local or tmpfs directory, instead of the "godoc" directory under
+// the user cache directory. It is created if needed. Instances with the
+// same cache directory share their cache.
+func WithCacheDir(dir string) Option {
+	return func(g *Godoc) {
+		g.cacheDir = dir
+	}
+}
+
+// WithCacheTTL sets how long cached documentation is served before it is
+// rebuilt, e.g. a short TTL so that edits to local packages are picked up,
+// or a long one for immutable module versions. A zero or negative duration,
+// the default, means cached documentation does not expire.
+//
+// The TTL is checked when an entry is read, against the time it was built,
+// so instances with different TTLs can share the process-wide cache: it is
+// initialized once and not reset when the TTL changes. Entries persisted by
+// previous versions have no build time and expire with any TTL.
+func WithCacheTTL(d time.Duration) Option {
+	return func(g *Godoc) {
+		g.cacheTTL = d
+	}
+}
+
+// WithMaxCacheSize sets the maximum number of entries of the cache, e.g. a
+// larger cache for a server documenting many packages, or a tiny one for an
+// embedded tool. The least recently stored entries are evicted first. A zero
+// or negative size, the default, means 10,000 entries.
+//
+// Caches are shared by instances with the same [CacheMode] and cache
+// directory, and sized when first used, so the size only takes effect if
+// the instance is the first one to use its cache in the process. A
+// persisted cache is resized when loaded from its file.
+func WithMaxCacheSize(n int) Option {
+	return func(g *Godoc) {
+		g.cacheSize = n
+	}
+}
+
+// WithPrettyTypes renders the types of arguments, results, and struct
+// fields compactly: packages are qualified by name rather than import path
+// (e.g. "*http.Request" instead of "*net/http.Request"), parameter names are
+// omitted from function types, and the bodies of anonymous struct and
+// interface types are abbreviated to "struct{...}" and "interface{...}".
+func WithPrettyTypes(enabled bool) Option {
+	return func(g *Godoc) {
+		g.prettyTypes = enabled
+	}
+}
+
+// WithInlineTypes attaches to each symbol the declarations of the types of
+// its package it references, such as the types of the arguments and results
+// of a function or of the fields of a struct, as [SymbolDoc.InlineTypes].
+// The types referenced by inlined types are inlined as well, up to the given
+// depth, which gives a self-contained view of an API. A depth <= 0 disables
+// inlining, which is the default.
+func WithInlineTypes(depth int) Option {
+	return func(g *Godoc) {
+		g.inlineTypes = depth
+	}
+}
+
+// WithSymbolMatch sets how selectors are matched to symbols when no symbol
+// has exactly the selected name: [MatchExact] (the default),
+// [MatchCaseInsensitive], or [MatchPrefix]. A selector matching several
+// symbols fails with an [AmbiguousSymbolError] listing them.
+func WithSymbolMatch(mode SymbolMatch) Option {
+	return func(g *Godoc) {
+		g.symbolMatch = mode
+	}
+}
+
+// WithTestFiles includes the declarations of the package's _test.go files,
+// such as exported test helpers and fixtures, in the documentation. The
+// test, benchmark, fuzz, and example functions themselves are not
+// documented, and files of an external "_test" package are only used for
+// their examples, as they are by default.
+func WithTestFiles(enabled bool) Option {
+	return func(g *Godoc) {
+		g.testFiles = enabled
+	}
+}
+
+// WithUnexported includes unexported declarations, such as lowercase
+// functions, types, and struct fields, in the documentation. By default only
+// the exported API is documented.
+func WithUnexported(enabled bool) Option {
+	return func(g *Godoc) {
+		g.unexported = enabled
+	}
+}
+
+// WithSortMode sets the order of the constants, variables, functions,
+// types, and methods of the documentation: by name with [SortAlpha], the
+// default, or as declared with [SortSource].
+func WithSortMode(mode SortMode) Option {
+	return func(g *Godoc) {
+		g.sortMode = mode
+	}
+}
+
+// WithImplements records the "implements" relationships between the types
+// of a package in [TypeDoc.Implements] and [TypeDoc.ImplementedBy]. Unlike
+// [WithImplementers], it only relates types declared in the same package,
+// but annotates every type of the package documentation. Every pair of types
+// is checked, so it is opt-in.
+func WithImplements(enabled bool) Option {
+	return func(g *Godoc) {
+		g.implements = enabled
+	}
+}
+
+// WithHeadingLevel sets the level of the HTML headings of doc comments, e.g.
+// to embed the HTML in a page with headings of its own: headings are
+// rendered at level base in package comments and base+1 in symbol comments.
+// A base outside 1 to 5 means the default, 2 (<h2> and <h3>).
+func WithHeadingLevel(base int) Option {
+	return func(g *Godoc) {
+		g.headingLevel = base
+	}
+}
+
+// WithIncludeInternal includes internal packages, whose import path has an
+// "internal" element, when traversing the packages of a module with
+// [Godoc.LoadModule]. By default they are skipped, as they are not part of
+// the API of the module.
+//
+// It does not affect loading a single package: the go command only
+// restricts importing internal packages, so [Godoc.Load] documents any
+// internal package given by its full import path, be it of the main module
+// (from any directory within it), of a dependency, or of a remote module.
+func WithIncludeInternal(enabled bool) Option {
+	return func(g *Godoc) {
+		g.includeInternal = enabled
+	}
+}
+
+// WithConcurrency limits the number of packages loaded concurrently by the
+// instance, e.g. with [Godoc.LoadMultiple], as each load parses and
+// type-checks a package in memory. A positive n sets the limit, and n <= 0
+// restores the default, [runtime.NumCPU].
+//
+// Remote module fetches are limited separately, with
+// [WithMaxConcurrentFetches].
+func WithConcurrency(n int) Option {
+	return func(g *Godoc) {
+		if n <= 0 {
+			n = runtime.NumCPU()
+		}
+
+		g.loadSem = make(chan struct{}, n)
+	}
+}
+
+// WithResolveDocLinks records the doc links of the documentation, such as
+// [io.Reader] or [Client.Do], as the DocLinks of the package and of each of
+// its symbols, fields, and methods, resolved to absolute pkg.go.dev URLs.
+// Links are taken from the parsed doc comments, so that consumers of the
+// JSON output can render them without parsing the text again.
+func WithResolveDocLinks(enabled bool) Option {
+	return func(g *Godoc) {
+		g.resolveDocLinks = enabled
+	}
+}
+
+// WithSynopsisOnly builds a short form of the documentation, e.g. to list
+// the packages of a module with [Godoc.LoadMultiple]: packages keep their
+// Synopsis, and symbols their names, declarations, and signatures, but doc
+// comments are left empty and neither rendered to HTML nor parsed. Test
+// files are not read, so there are no examples and test functions, unless
+// enabled with [WithTestFiles]; notes are left out as well.
+func WithSynopsisOnly(enabled bool) Option {
+	return func(g *Godoc) {
+		g.synopsisOnly = enabled
+	}
+}
+
+// WithStripLicenseHeader removes the license boilerplate leading some
+// package comments, such as "Copyright 2009 The Go Authors. All rights
+// reserved." or an SPDX license identifier, from the package documentation
+// before it is rendered and summarized. Only the paragraphs at the very start
+// of the comment that read as a copyright or license notice are removed.
+func WithStripLicenseHeader(enabled bool) Option {
+	return func(g *Godoc) {
+		g.stripLicense = enabled
+	}
+}
+
+// WithSource reads packages from the Go files of fsys instead of loading
+// them with the go command, e.g. to document embedded sources or to test
+// documentation extraction without network or disk access. The import path
+// of a package names its directory under root, which is the root of fsys if
+// empty, and build constraints are matched against the configured platform
+// and build tags.
+//
+// Type information is best-effort, since imported packages are not read
+// from fsys, and packages are never fetched nor cached. A nil fsys restores
+// loading with the go command.
+func WithSource(fsys fs.FS, root string) Option {
+	return func(g *Godoc) {
+		g.source = fsys
+		g.sourceRoot = root
+	}
+}
+
 // SetOptions applies the given options to the [Godoc] instance.
 //
 // Note that applying options may override previously set values.
@@ -46,3 +446,181 @@ func (g *Godoc) SetOptions(opts ...Option) {
 		opt(g)
 	}
 }
+
+// Config describes the effective configuration of a [Godoc] instance.
+type Config struct {
+	GOOS      string        // Target operating system
+	GOARCH    string        // Target architecture
+	Workdir   string        // Working directory used to resolve modules
+	GoVersion string        // Go version keying standard library docs
+	SumDB     string        // Checksum database for fetches; empty if inherited
+	GoProxy   string        // Module proxy for fetches; empty if inherited
+	GoBinary  string        // Path of the go binary; empty for "go" from PATH
+	Timeout   time.Duration // Timeout of each call; 0 if unlimited
+
+	// GoEnv are the go environment variables set with [WithGoEnv].
+	GoEnv map[string]string
+
+	// Platforms are the additional platforms whose struct fields are merged
+	// into the documentation.
+	Platforms []string
+
+	// BuildTags are the additional build tags set with [WithBuildTags].
+	BuildTags []string
+
+	// ModMode is the module mode set with [WithModMode].
+	ModMode ModMode
+
+	// CacheMode is the cache mode set with [WithCacheMode].
+	CacheMode CacheMode
+
+	// CacheDir is the cache directory set with [WithCacheDir], or empty
+	// for the default one.
+	CacheDir string
+
+	// CacheTTL is the cache TTL set with [WithCacheTTL], or 0 if cached
+	// documentation does not expire.
+	CacheTTL time.Duration
+
+	// MaxCacheSize is the maximum number of cache entries set with
+	// [WithMaxCacheSize], or the default one.
+	MaxCacheSize int
+
+	// MaxConcurrentFetches is the limit on concurrent remote module
+	// fetches, or 0 if unlimited.
+	MaxConcurrentFetches int
+
+	// Concurrency is the limit on concurrent package loads set with
+	// [WithConcurrency].
+	Concurrency int
+}
+
+// Config returns the effective configuration of the [Godoc] instance.
+//
+// GoVersion is the version of the go binary set with [WithGoBinary], if
+// any, and the version of the running Go toolchain otherwise.
+//
+// GOOS and GOARCH fall back to the variables set with [WithGoEnv], and then
+// to the values of the go environment, when they were not set with
+// [WithGOOS] or [WithGOARCH].
+func (g *Godoc) Config() Config {
+	cfg := Config{
+		GOOS:      g.goos,
+		GOARCH:    g.goarch,
+		Workdir:   g.workdir,
+		GoVersion: runtime.Version(),
+		SumDB:     g.sumDB,
+		GoProxy:   g.goProxy,
+		GoBinary:  g.goBinary,
+		GoEnv:     maps.Clone(g.goEnv),
+		Timeout:   max(g.timeout, 0),
+		Platforms: slices.Clone(g.platforms),
+		BuildTags: slices.Clone(g.buildTags),
+		ModMode:   g.modMode,
+		CacheMode: g.cacheMode,
+		CacheDir:  g.cacheDir,
+		CacheTTL:  max(g.cacheTTL, 0),
+
+		MaxCacheSize: g.maxCacheEntries(),
+
+		MaxConcurrentFetches: cap(g.fetchSem),
+
+		Concurrency: g.concurrency(),
+	}
+
+	if g.goBinary != "" {
+		if version, err := g.goBinaryVersion(); err == nil {
+			cfg.GoVersion = version
+		}
+	}
+
+	if cfg.GOOS == "" {
+		cfg.GOOS = cmp.Or(g.goEnv["GOOS"], build.Default.GOOS)
+	}
+
+	if cfg.GOARCH == "" {
+		cfg.GOARCH = cmp.Or(g.goEnv["GOARCH"], build.Default.GOARCH)
+	}
+
+	return cfg
+}
+
+// buildFlags returns the go command flags selecting the build tags set with
+// [WithBuildTags] and the module mode set with [WithModMode], if any.
+func (g *Godoc) buildFlags() []string {
+	var flags []string
+	if len(g.buildTags) > 0 {
+		flags = append(flags, "-tags="+strings.Join(g.buildTags, ","))
+	}
+
+	if mod := g.modMode.flag(); mod != "" {
+		flags = append(flags, "-mod="+mod)
+	}
+
+	return flags
+}
+
+// buildOptions holds the options that affect how documentation is built.
+type buildOptions struct {
+	rawComments  bool
+	prettyTypes  bool
+	inlineDepth  int
+	sortMode     SortMode
+	implements   bool
+	unexported   bool
+	headingLevel int
+	synopsisOnly bool
+	stripLicense bool
+
+	resolveDocLinks bool
+}
+
+// defaultHeadingLevel is the HTML heading level of package comments, unless
+// set with [WithHeadingLevel].
+const defaultHeadingLevel = 2
+
+// docText returns the given doc comment text, or "" if only synopses are
+// built.
+func (o buildOptions) docText(text string) string {
+	if o.synopsisOnly {
+		return ""
+	}
+
+	return text
+}
+
+// pkgHeadingLevel returns the HTML heading level of package comments. Symbol
+// comments use the next level.
+func (o buildOptions) pkgHeadingLevel() int {
+	if o.headingLevel < 1 || o.headingLevel > 5 {
+		return defaultHeadingLevel
+	}
+
+	return o.headingLevel
+}
+
+// typeString renders t according to the options.
+func (o buildOptions) typeString(t types.Type) string {
+	if o.prettyTypes {
+		return prettyTypeString(t)
+	}
+
+	return t.String()
+}
+
+// buildOptions returns the documentation build options of the instance.
+func (g *Godoc) buildOptions() buildOptions {
+	return buildOptions{
+		rawComments:  g.rawComments,
+		prettyTypes:  g.prettyTypes,
+		inlineDepth:  g.inlineTypes,
+		sortMode:     g.sortMode,
+		implements:   g.implements,
+		unexported:   g.unexported,
+		headingLevel: g.headingLevel,
+		synopsisOnly: g.synopsisOnly,
+		stripLicense: g.stripLicense,
+
+		resolveDocLinks: g.resolveDocLinks,
+	}
+}
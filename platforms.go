@@ -0,0 +1,186 @@
+package godoc
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// platform is a GOOS/GOARCH pair.
+type platform struct {
+	goos, goarch string
+}
+
+// String returns the platform in "goos/goarch" form.
+func (p platform) String() string {
+	return p.goos + "/" + p.goarch
+}
+
+// parsePlatform parses a platform in "goos/goarch" or "goos" form. A missing
+// GOARCH defaults to goarch.
+func parsePlatform(s, goarch string) (platform, error) {
+	goos, arch, found := strings.Cut(strings.TrimSpace(s), "/")
+	if !found {
+		arch = goarch
+	}
+
+	if goos == "" || arch == "" {
+		return platform{}, fmt.Errorf("%w: %q", ErrInvalidPlatform, s)
+	}
+
+	return platform{goos: goos, goarch: arch}, nil
+}
+
+// primaryPlatform returns the platform documentation is built for.
+func (d *Godoc) primaryPlatform() platform {
+	cfg := d.Config()
+
+	return platform{goos: cfg.GOOS, goarch: cfg.GOARCH}
+}
+
+// extraPlatforms returns the additional platforms whose struct fields are
+// merged into the documentation, skipping the primary platform.
+func (d *Godoc) extraPlatforms() ([]platform, error) {
+	primary := d.primaryPlatform()
+
+	var platforms []platform
+	for _, s := range d.platforms {
+		p, err := parsePlatform(s, primary.goarch)
+		if err != nil {
+			return nil, err
+		}
+
+		if p == primary {
+			continue
+		}
+
+		platforms = append(platforms, p)
+	}
+
+	return platforms, nil
+}
+
+// mergePlatformFields loads importPath from dir for every extra platform and
+// merges the struct fields declared there into the types of pkgDoc and
+// symbols. Fields that are not declared on every platform record the
+// platforms declaring them. Platforms the package cannot be loaded for are
+// skipped.
+//
+// The platforms are loaded with type information if typed is set, as the
+// package was, so that field types are rendered alike across platforms.
+func (d *Godoc) mergePlatformFields(importPath, dir string, typed bool, pkgDoc *PackageDoc, symbols map[string]SymbolDoc) error {
+	platforms, err := d.extraPlatforms()
+	if err != nil || len(platforms) == 0 {
+		return err
+	}
+
+	primary := d.primaryPlatform()
+	all := []platform{primary}
+	fieldsByType := make(map[string][]platformFields, len(pkgDoc.Types))
+	for _, t := range pkgDoc.Types {
+		if len(t.Fields) > 0 {
+			fieldsByType[t.Name] = []platformFields{{platform: primary, fields: t.Fields}}
+		}
+	}
+
+	opts := d.buildOptions()
+	for _, p := range platforms {
+		alt := *d
+		alt.goos, alt.goarch = p.goos, p.goarch
+
+		dpkg, fset, typesInfo, astInfo, pkgPath, _, _, err := alt.loadDocPkg(importPath, dir, typed)
+		if err != nil {
+			continue
+		}
+
+		all = append(all, p)
+		for _, t := range toPkgDoc(dpkg, fset, typesInfo, astInfo, pkgPath, opts).Types {
+			if _, ok := fieldsByType[t.Name]; ok && len(t.Fields) > 0 {
+				fieldsByType[t.Name] = append(fieldsByType[t.Name], platformFields{platform: p, fields: t.Fields})
+			}
+		}
+	}
+
+	for i, t := range pkgDoc.Types {
+		if sets, ok := fieldsByType[t.Name]; ok {
+			pkgDoc.Types[i].Fields = mergeFields(sets, len(all))
+		}
+	}
+
+	for key, sym := range symbols {
+		if sym.Kind != "type" || sym.TypeDoc == nil {
+			continue
+		}
+
+		if sets, ok := fieldsByType[sym.TypeDoc.Name]; ok {
+			td := *sym.TypeDoc
+			td.Fields = mergeFields(sets, len(all))
+			sym.TypeDoc = &td
+			symbols[key] = sym
+		}
+	}
+
+	return nil
+}
+
+// platformFields are the struct fields of a type on a platform.
+type platformFields struct {
+	platform platform
+	fields   []FieldDoc
+}
+
+// fieldVariant is a field declared with the same type on some platforms.
+type fieldVariant struct {
+	field     FieldDoc
+	platforms []string
+}
+
+// mergeFields merges the field sets of a type loaded on numPlatforms
+// platforms. Fields keep the order of the first platform declaring them, and
+// those missing on some platform list the platforms declaring them. A field
+// whose type differs across platforms is listed once per type, each with the
+// platforms declaring it with that type.
+func mergeFields(sets []platformFields, numPlatforms int) []FieldDoc {
+	var (
+		names    []string
+		variants = make(map[string][]fieldVariant)
+	)
+
+	for _, set := range sets {
+		platform := set.platform.String()
+		for _, f := range set.fields {
+			vs, ok := variants[f.Name]
+			if !ok {
+				names = append(names, f.Name)
+			}
+
+			i := slices.IndexFunc(vs, func(v fieldVariant) bool { return v.field.Type == f.Type })
+			if i < 0 {
+				i = len(vs)
+				vs = append(vs, fieldVariant{field: f})
+			}
+
+			// Blank fields may be declared several times on a platform.
+			if !slices.Contains(vs[i].platforms, platform) {
+				vs[i].platforms = append(vs[i].platforms, platform)
+			}
+
+			variants[f.Name] = vs
+		}
+	}
+
+	var merged []FieldDoc
+	for _, name := range names {
+		for _, v := range variants[name] {
+			f := v.field
+			f.Platforms = nil
+			if len(v.platforms) < numPlatforms {
+				f.Platforms = v.platforms
+			}
+
+			merged = append(merged, f)
+		}
+	}
+
+	return merged
+}
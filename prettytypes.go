@@ -0,0 +1,129 @@
+package godoc
+
+import (
+	"go/types"
+	"strconv"
+	"strings"
+)
+
+// prettyTypeString renders t compactly for [WithPrettyTypes]: packages are
+// qualified by name, function types omit parameter names, and the bodies of
+// anonymous struct and non-empty interface types are abbreviated.
+func prettyTypeString(t types.Type) string {
+	var sb strings.Builder
+	writePrettyType(&sb, t)
+
+	return sb.String()
+}
+
+// prettyQualifier qualifies package-level objects by package name.
+func prettyQualifier(pkg *types.Package) string {
+	return pkg.Name()
+}
+
+// writePrettyType writes the compact rendering of t to sb.
+func writePrettyType(sb *strings.Builder, t types.Type) {
+	switch t := t.(type) {
+	case *types.Pointer:
+		sb.WriteByte('*')
+		writePrettyType(sb, t.Elem())
+	case *types.Slice:
+		sb.WriteString("[]")
+		writePrettyType(sb, t.Elem())
+	case *types.Array:
+		sb.WriteString("[" + strconv.FormatInt(t.Len(), 10) + "]")
+		writePrettyType(sb, t.Elem())
+	case *types.Map:
+		sb.WriteString("map[")
+		writePrettyType(sb, t.Key())
+		sb.WriteByte(']')
+		writePrettyType(sb, t.Elem())
+	case *types.Chan:
+		writePrettyChan(sb, t)
+	case *types.Signature:
+		sb.WriteString("func")
+		writePrettySignature(sb, t)
+	case *types.Struct:
+		if t.NumFields() == 0 {
+			sb.WriteString("struct{}")
+		} else {
+			sb.WriteString("struct{...}")
+		}
+	case *types.Interface:
+		if t.NumMethods() == 0 && t.NumEmbeddeds() == 0 {
+			sb.WriteString("interface{}")
+		} else {
+			sb.WriteString("interface{...}")
+		}
+	default:
+		sb.WriteString(types.TypeString(t, prettyQualifier))
+	}
+}
+
+// writePrettyChan writes the compact rendering of the channel type t to sb.
+func writePrettyChan(sb *strings.Builder, t *types.Chan) {
+	parens := false
+	switch t.Dir() {
+	case types.SendRecv:
+		sb.WriteString("chan ")
+		// chan (<-chan T) requires parentheses
+		if c, ok := t.Elem().(*types.Chan); ok && c.Dir() == types.RecvOnly {
+			parens = true
+		}
+	case types.SendOnly:
+		sb.WriteString("chan<- ")
+	case types.RecvOnly:
+		sb.WriteString("<-chan ")
+	}
+
+	if parens {
+		sb.WriteByte('(')
+	}
+
+	writePrettyType(sb, t.Elem())
+
+	if parens {
+		sb.WriteByte(')')
+	}
+}
+
+// writePrettySignature writes the parameter and result types of sig to sb,
+// omitting their names.
+func writePrettySignature(sb *strings.Builder, sig *types.Signature) {
+	sb.WriteByte('(')
+	params := sig.Params()
+	for i := 0; i < params.Len(); i++ {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+
+		typ := params.At(i).Type()
+		if sig.Variadic() && i == params.Len()-1 {
+			sb.WriteString("...")
+			if s, ok := typ.(*types.Slice); ok {
+				typ = s.Elem()
+			}
+		}
+
+		writePrettyType(sb, typ)
+	}
+	sb.WriteByte(')')
+
+	results := sig.Results()
+	switch results.Len() {
+	case 0:
+	case 1:
+		sb.WriteByte(' ')
+		writePrettyType(sb, results.At(0).Type())
+	default:
+		sb.WriteString(" (")
+		for i := 0; i < results.Len(); i++ {
+			if i > 0 {
+				sb.WriteString(", ")
+			}
+
+			writePrettyType(sb, results.At(i).Type())
+		}
+		sb.WriteByte(')')
+	}
+}
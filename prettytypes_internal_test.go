@@ -0,0 +1,54 @@
+package godoc
+
+import (
+	"go/token"
+	"go/types"
+	"testing"
+)
+
+func TestPrettyTypeString(t *testing.T) {
+	pkg := types.NewPackage("net/http", "http")
+	request := types.NewNamed(types.NewTypeName(token.NoPos, pkg, "Request", nil), types.NewStruct(nil, nil), nil)
+	errType := types.Universe.Lookup("error").Type()
+	str := types.Typ[types.String]
+	ctx := types.NewNamed(types.NewTypeName(token.NoPos, types.NewPackage("context", "context"), "Context", nil), types.NewInterfaceType(nil, nil), nil)
+
+	param := func(name string, typ types.Type) *types.Var {
+		return types.NewParam(token.NoPos, nil, name, typ)
+	}
+
+	tests := []struct {
+		typ  types.Type
+		want string
+	}{
+		{types.NewPointer(request), "*http.Request"},
+		{types.NewMap(str, types.NewSlice(request)), "map[string][]http.Request"},
+		{types.NewArray(types.NewPointer(request), 4), "[4]*http.Request"},
+		{types.NewChan(types.SendRecv, types.NewChan(types.RecvOnly, str)), "chan (<-chan string)"},
+		{types.NewChan(types.SendOnly, str), "chan<- string"},
+		{
+			types.NewSignatureType(nil, nil, nil,
+				types.NewTuple(param("ctx", ctx), param("req", types.NewPointer(request))),
+				types.NewTuple(param("", types.NewPointer(request)), param("err", errType)),
+				false),
+			"func(context.Context, *http.Request) (*http.Request, error)",
+		},
+		{
+			types.NewSignatureType(nil, nil, nil,
+				types.NewTuple(param("format", str), param("args", types.NewSlice(types.Universe.Lookup("any").Type()))),
+				types.NewTuple(param("", errType)),
+				true),
+			"func(string, ...any) error",
+		},
+		{types.NewStruct([]*types.Var{types.NewField(token.NoPos, nil, "X", str, false)}, nil), "struct{...}"},
+		{types.NewStruct(nil, nil), "struct{}"},
+		{types.NewInterfaceType(nil, nil), "interface{}"},
+		{types.NewInterfaceType([]*types.Func{types.NewFunc(token.NoPos, nil, "Close", types.NewSignatureType(nil, nil, nil, nil, types.NewTuple(param("", errType)), false))}, nil), "interface{...}"},
+	}
+
+	for _, tt := range tests {
+		if got := prettyTypeString(tt.typ); got != tt.want {
+			t.Errorf("prettyTypeString(%s): expected %q, got %q", tt.typ, tt.want, got)
+		}
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"go/doc"
 	"go/token"
 	"go/types"
+	"strconv"
 	"strings"
 
 	"slices"
@@ -43,7 +44,8 @@ func pkgRequiresTypesInfo(p *doc.Package) bool {
 }
 
 // typeRequiresTypesInfo checks if the given *[doc.Type] requires *[types.Info]
-// for accurate documentation.
+// for accurate documentation: interfaces embedding other interfaces, and
+// structs with embedded fields, whose methods are promoted to the struct.
 func typeRequiresTypesInfo(t *doc.Type) bool {
 	if t == nil {
 		return false
@@ -54,12 +56,19 @@ func typeRequiresTypesInfo(t *doc.Type) bool {
 		return false
 	}
 
-	iface, ok := typeSpec.Type.(*ast.InterfaceType)
-	if !ok || iface.Methods == nil {
+	var fields *ast.FieldList
+	switch typ := typeSpec.Type.(type) {
+	case *ast.InterfaceType:
+		fields = typ.Methods
+	case *ast.StructType:
+		fields = typ.Fields
+	}
+
+	if fields == nil {
 		return false
 	}
 
-	for _, field := range iface.Methods.List {
+	for _, field := range fields.List {
 		if len(field.Names) == 0 {
 			return true
 		}
@@ -126,6 +135,35 @@ func renderInterfaceDecl(name string, iface *ast.InterfaceType, fset *token.File
 	return strings.Join(lines, "\n")
 }
 
+// interfaceEmbeds returns the interfaces embedded in the declaration of an
+// interface type, as written in the source (e.g. "Reader" or "io.Reader").
+// Type set elements of constraint interfaces, such as "~int | ~string", are
+// not included.
+func interfaceEmbeds(spec *ast.TypeSpec, fset *token.FileSet) []string {
+	if spec == nil {
+		return nil
+	}
+
+	iface, ok := spec.Type.(*ast.InterfaceType)
+	if !ok || iface.Methods == nil {
+		return nil
+	}
+
+	var embeds []string
+	for _, field := range iface.Methods.List {
+		if len(field.Names) > 0 {
+			continue
+		}
+
+		switch field.Type.(type) {
+		case *ast.Ident, *ast.SelectorExpr, *ast.IndexExpr, *ast.IndexListExpr:
+			embeds = append(embeds, exprString(field.Type, fset))
+		}
+	}
+
+	return embeds
+}
+
 // renderStructDecl renders the declaration of a struct type as a string.
 func renderStructDecl(name string, st *ast.StructType, fset *token.FileSet, astInfo *packageAST) string {
 	lines := []string{fmt.Sprintf("type %s struct {", name)}
@@ -199,8 +237,10 @@ func formatCommentLines(raw string) []string {
 }
 
 // interfaceMethodDocs extracts method documentation for an interface type.
-func interfaceMethodDocs(t *doc.Type, typesInfo *types.Info) []MethodDoc {
-	if t == nil || typesInfo == nil || t.Decl == nil {
+// Without type information, only the methods declared in the interface
+// itself are listed, as those of embedded interfaces are unknown.
+func interfaceMethodDocs(t *doc.Type, fset *token.FileSet, typesInfo *types.Info, opts buildOptions) []MethodDoc {
+	if t == nil || t.Decl == nil {
 		return nil
 	}
 
@@ -214,6 +254,10 @@ func interfaceMethodDocs(t *doc.Type, typesInfo *types.Info) []MethodDoc {
 		return nil
 	}
 
+	if typesInfo == nil {
+		return interfaceMethodDocsFromAST(t.Name, ifaceAST, fset, opts)
+	}
+
 	obj, _ := typesInfo.Defs[typeSpec.Name].(*types.TypeName)
 	if obj == nil {
 		return nil
@@ -233,15 +277,7 @@ func interfaceMethodDocs(t *doc.Type, typesInfo *types.Info) []MethodDoc {
 				continue
 			}
 
-			name := field.Names[0].Name
-			docText := ""
-			if field.Doc != nil {
-				docText = field.Doc.Text()
-			} else if field.Comment != nil {
-				docText = field.Comment.Text()
-			}
-
-			docMap[name] = docText
+			docMap[field.Names[0].Name] = interfaceFieldDoc(field)
 		}
 	}
 
@@ -249,22 +285,149 @@ func interfaceMethodDocs(t *doc.Type, typesInfo *types.Info) []MethodDoc {
 	for i := 0; i < ifaceType.NumMethods(); i++ {
 		method := ifaceType.Method(i)
 		sig, _ := method.Type().(*types.Signature)
+		docText := docMap[method.Name()]
+		deprecated, note := deprecation(docText)
 
 		methods = append(methods, MethodDoc{
 			Recv:     t.Name,
 			RecvType: t.Name,
 			Name:     method.Name(),
-			Args:     argsFromSignature(sig, nil),
-			Returns:  resultsFromSignature(sig, nil),
-			Doc:      docMap[method.Name()],
+			Args:     argsFromSignature(sig, nil, opts),
+			Returns:  resultsFromSignature(sig, nil, opts),
+			Variadic: sig != nil && sig.Variadic(),
+			Doc:      opts.docText(docText),
+
+			Deprecated:     deprecated,
+			DeprecatedNote: note,
 		})
 	}
 
 	return methods
 }
 
+// interfaceMethodDocsFromAST extracts the documentation of the methods
+// declared in the given interface type from its syntax alone.
+func interfaceMethodDocsFromAST(name string, iface *ast.InterfaceType, fset *token.FileSet, opts buildOptions) []MethodDoc {
+	if iface.Methods == nil {
+		return nil
+	}
+
+	var methods []MethodDoc
+	for _, field := range iface.Methods.List {
+		ft, ok := field.Type.(*ast.FuncType)
+		if !ok || len(field.Names) == 0 {
+			continue
+		}
+
+		decl := &ast.FuncDecl{Name: field.Names[0], Type: ft}
+		docText := interfaceFieldDoc(field)
+		deprecated, note := deprecation(docText)
+
+		methods = append(methods, MethodDoc{
+			Recv:     name,
+			RecvType: name,
+			Name:     decl.Name.Name,
+			Args:     extractArgs(decl, fset, nil, opts),
+			Returns:  extractResults(decl, fset, nil, opts),
+			Variadic: isVariadicDecl(decl, nil),
+			Doc:      opts.docText(docText),
+
+			Deprecated:     deprecated,
+			DeprecatedNote: note,
+		})
+	}
+
+	return methods
+}
+
+// interfaceFieldDoc returns the doc comment of an interface method, or its
+// line comment if it has none.
+func interfaceFieldDoc(field *ast.Field) string {
+	switch {
+	case field.Doc != nil:
+		return field.Doc.Text()
+	case field.Comment != nil:
+		return field.Comment.Text()
+	default:
+		return ""
+	}
+}
+
+// promotedMethod describes a method promoted to a struct type from one of
+// its embedded fields.
+type promotedMethod struct {
+	sig     *types.Signature
+	from    string // Type declaring the method
+	pointer bool   // Whether the method is only in the method set of *T
+}
+
+// promotedMethods returns the exported methods promoted to the struct type
+// of t from its embedded fields, keyed by name. They are looked up in the
+// method set of *T, which holds the methods of embedded values and pointers
+// alike.
+func promotedMethods(t *doc.Type, typesInfo *types.Info, opts buildOptions) map[string]promotedMethod {
+	typeSpec := typeSpecForDocType(t)
+	if typeSpec == nil || typesInfo == nil {
+		return nil
+	}
+
+	obj, _ := typesInfo.Defs[typeSpec.Name].(*types.TypeName)
+	if obj == nil {
+		return nil
+	}
+
+	named, _ := obj.Type().(*types.Named)
+	if named == nil {
+		return nil
+	}
+
+	if _, ok := named.Underlying().(*types.Struct); !ok {
+		return nil
+	}
+
+	valueSet := types.NewMethodSet(named)
+	pointerSet := types.NewMethodSet(types.NewPointer(named))
+
+	var promoted map[string]promotedMethod
+	for sel := range pointerSet.Methods() {
+		fn, ok := sel.Obj().(*types.Func)
+		if !ok || len(sel.Index()) < 2 || !fn.Exported() {
+			continue
+		}
+
+		recv := fn.Signature().Recv().Type()
+		if ptr, ok := recv.(*types.Pointer); ok {
+			recv = ptr.Elem()
+		}
+
+		if promoted == nil {
+			promoted = make(map[string]promotedMethod)
+		}
+
+		promoted[fn.Name()] = promotedMethod{
+			sig:     fn.Signature(),
+			from:    opts.typeString(recv),
+			pointer: valueSet.Lookup(fn.Pkg(), fn.Name()) == nil,
+		}
+	}
+
+	return promoted
+}
+
+// promote marks m as promoted to the type named recv.
+func (p promotedMethod) promote(m *MethodDoc, recv string) {
+	m.Promoted = true
+	m.PromotedFrom = p.from
+	m.RecvName = ""
+	m.RecvType = recv
+	m.RecvPointer = p.pointer
+	if p.pointer {
+		m.RecvType = "*" + recv
+	}
+}
+
 // structFieldDocs extracts field documentation for a struct type.
-func structFieldDocs(t *doc.Type, fset *token.FileSet, typesInfo *types.Info, astInfo *packageAST) []FieldDoc {
+func structFieldDocs(t *doc.Type, fset *token.FileSet, typesInfo *types.Info, astInfo *packageAST, opts buildOptions) []FieldDoc {
 	if t == nil || t.Decl == nil {
 		return nil
 	}
@@ -281,7 +444,7 @@ func structFieldDocs(t *doc.Type, fset *token.FileSet, typesInfo *types.Info, as
 
 	fields := make([]FieldDoc, 0, len(structType.Fields.List))
 	for _, field := range structType.Fields.List {
-		typeStr := fieldTypeString(field, typesInfo, fset)
+		typeStr := fieldTypeString(field, typesInfo, fset, opts)
 
 		docText := ""
 		if field.Doc != nil {
@@ -297,24 +460,43 @@ func structFieldDocs(t *doc.Type, fset *token.FileSet, typesInfo *types.Info, as
 			tag = strings.Trim(field.Tag.Value, "`")
 		}
 
+		parsedTag := parseStructTag(tag)
+
+		deprecated, note := deprecation(docText)
+
+		rawDoc := ""
+		if opts.rawComments {
+			rawDoc = rawCommentText(field, astInfo)
+		}
+
 		if len(field.Names) == 0 {
 			name := embeddedFieldName(field, fset, typeStr)
 			fields = append(fields, FieldDoc{
-				Name:     name,
-				Type:     typeStr,
-				Doc:      docText,
-				Tag:      tag,
-				Embedded: true,
+				Name:       name,
+				Type:       typeStr,
+				Doc:        opts.docText(docText),
+				Tag:        tag,
+				Embedded:   true,
+				Deprecated: deprecated,
+				RawDoc:     rawDoc,
+
+				DeprecatedNote: note,
+				ParsedTag:      parsedTag,
 			})
 			continue
 		}
 
 		for _, ident := range field.Names {
 			fields = append(fields, FieldDoc{
-				Name: ident.Name,
-				Type: typeStr,
-				Doc:  docText,
-				Tag:  tag,
+				Name:       ident.Name,
+				Type:       typeStr,
+				Doc:        opts.docText(docText),
+				Tag:        tag,
+				Deprecated: deprecated,
+				RawDoc:     rawDoc,
+
+				DeprecatedNote: note,
+				ParsedTag:      parsedTag,
 			})
 		}
 	}
@@ -322,15 +504,72 @@ func structFieldDocs(t *doc.Type, fset *token.FileSet, typesInfo *types.Info, as
 	return fields
 }
 
+// parseStructTag parses the key:"value" pairs of a struct tag in the
+// conventional format of [reflect.StructTag], keeping the first value of a
+// repeated key. It returns nil if the tag is empty or malformed.
+func parseStructTag(tag string) map[string]string {
+	var values map[string]string
+	for tag != "" {
+		tag = strings.TrimLeft(tag, " ")
+		if tag == "" {
+			break
+		}
+
+		// A key is a non-empty run of non-space, non-control characters
+		// other than a quote or colon, followed by a colon and a quoted
+		// string.
+		i := 0
+		for i < len(tag) && tag[i] > ' ' && tag[i] != ':' && tag[i] != '"' && tag[i] != 0x7f {
+			i++
+		}
+
+		if i == 0 || i+1 >= len(tag) || tag[i] != ':' || tag[i+1] != '"' {
+			return nil
+		}
+
+		key := tag[:i]
+		tag = tag[i+1:]
+
+		i = 1
+		for i < len(tag) && tag[i] != '"' {
+			if tag[i] == '\\' {
+				i++
+			}
+			i++
+		}
+
+		if i >= len(tag) {
+			return nil
+		}
+
+		value, err := strconv.Unquote(tag[:i+1])
+		if err != nil {
+			return nil
+		}
+
+		tag = tag[i+1:]
+
+		if values == nil {
+			values = make(map[string]string)
+		}
+
+		if _, ok := values[key]; !ok {
+			values[key] = value
+		}
+	}
+
+	return values
+}
+
 // fieldTypeString returns the string representation of a struct field's type.
-func fieldTypeString(field *ast.Field, typesInfo *types.Info, fset *token.FileSet) string {
+func fieldTypeString(field *ast.Field, typesInfo *types.Info, fset *token.FileSet, opts buildOptions) string {
 	if field == nil || field.Type == nil {
 		return ""
 	}
 
 	if typesInfo != nil {
 		if tv, ok := typesInfo.Types[field.Type]; ok && tv.Type != nil {
-			return tv.Type.String()
+			return opts.typeString(tv.Type)
 		}
 	}
 
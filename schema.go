@@ -0,0 +1,35 @@
+package godoc
+
+import (
+	"encoding/json"
+
+	"github.com/google/jsonschema-go/jsonschema"
+)
+
+// JSONSchema returns the JSON Schema describing the JSON encoding of a
+// [Result], i.e. either a [PackageDoc] or a [SymbolDoc]. Property
+// descriptions are taken from the jsonschema struct tags of the result types.
+func JSONSchema() ([]byte, error) {
+	pkgSchema, err := jsonschema.For[PackageDoc](nil)
+	if err != nil {
+		return nil, err
+	}
+	pkgSchema.Title = "PackageDoc"
+	pkgSchema.Description = "Documentation for a Go package."
+
+	symSchema, err := jsonschema.For[SymbolDoc](nil)
+	if err != nil {
+		return nil, err
+	}
+	symSchema.Title = "SymbolDoc"
+	symSchema.Description = "Documentation for a symbol (type, method, function, const, or var)."
+
+	schema := &jsonschema.Schema{
+		Schema:      "https://json-schema.org/draft/2020-12/schema",
+		Title:       "Result",
+		Description: "Go documentation result.",
+		OneOf:       []*jsonschema.Schema{pkgSchema, symSchema},
+	}
+
+	return json.MarshalIndent(schema, "", "  ")
+}
@@ -0,0 +1,113 @@
+package godoc
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// maxSnippetLen is the maximum length, in runes, of the snippets of search
+// matches.
+const maxSnippetLen = 120
+
+// Search returns the documented symbols of the package with the given import
+// path whose selector, name, or documentation contains query, ignoring case.
+// A query enclosed in slashes, such as "/^Marshal/", is a regular expression
+// instead.
+//
+// Symbols matching by name come first, followed by those matching only by
+// their documentation, each ordered by name. The Snippet of a name match is
+// the first sentence of its documentation, and that of a documentation match
+// the line containing the match.
+//
+// Version is interpreted as in [Godoc.Load].
+func (d *Godoc) Search(importPath, query, version string) ([]SymbolRef, error) {
+	d, cancel := d.withTimeout()
+	defer cancel()
+
+	if err := validateInputs(importPath, ""); err != nil {
+		return nil, err
+	}
+
+	re, err := searchRegexp(query)
+	if err != nil {
+		return nil, err
+	}
+
+	symbols, err := d.symbolIndex(importPath, normalizeVersion(version))
+	if err != nil {
+		return nil, err
+	}
+
+	var byName, byDoc []SymbolRef
+	for key, sym := range symbols {
+		ref := SymbolRef{Name: key, Kind: sym.Kind, Receiver: sym.Receiver}
+
+		// Match the bare name too, so that anchored expressions such as
+		// "/^New/" match "Reader.NewReader".
+		if re.MatchString(key) || re.MatchString(sym.Name) {
+			ref.Snippet = truncateSnippet(Synopsis(sym.DocText))
+			byName = append(byName, ref)
+
+			continue
+		}
+
+		if line, ok := matchingLine(re, sym.DocText); ok {
+			ref.Snippet = truncateSnippet(line)
+			byDoc = append(byDoc, ref)
+		}
+	}
+
+	byRefName := func(a, b SymbolRef) int {
+		return strings.Compare(a.Name, b.Name)
+	}
+
+	slices.SortFunc(byName, byRefName)
+	slices.SortFunc(byDoc, byRefName)
+
+	return slices.Concat(byName, byDoc), nil
+}
+
+// searchRegexp returns the case-insensitive regular expression of a search
+// query.
+func searchRegexp(query string) (*regexp.Regexp, error) {
+	if strings.TrimSpace(query) == "" {
+		return nil, fmt.Errorf("%w: cannot be empty", ErrInvalidSearch)
+	}
+
+	expr := regexp.QuoteMeta(query)
+	if len(query) > 2 && strings.HasPrefix(query, "/") && strings.HasSuffix(query, "/") {
+		expr = query[1 : len(query)-1]
+	}
+
+	re, err := regexp.Compile("(?i)" + expr)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidSearch, err)
+	}
+
+	return re, nil
+}
+
+// matchingLine returns the first line of text matching re, with surrounding
+// white space removed.
+func matchingLine(re *regexp.Regexp, text string) (string, bool) {
+	for line := range strings.Lines(text) {
+		if re.MatchString(line) {
+			return strings.TrimSpace(line), true
+		}
+	}
+
+	return "", false
+}
+
+// truncateSnippet shortens s to at most maxSnippetLen runes, marking the
+// truncation with an ellipsis.
+func truncateSnippet(s string) string {
+	runes := []rune(s)
+	if len(runes) <= maxSnippetLen {
+		return s
+	}
+
+	return strings.TrimSpace(string(runes[:maxSnippetLen-1])) + "…"
+}
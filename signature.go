@@ -2,6 +2,7 @@ package godoc
 
 import (
 	"bytes"
+	"fmt"
 	"go/ast"
 	"go/printer"
 	"go/token"
@@ -10,11 +11,12 @@ import (
 )
 
 // methodReceiverInfo extracts the receiver name and type from the given
-// *[ast.FuncDecl]. It uses the provided *[token.FileSet] and *[types.Info] to
-// resolve type information when available.
-func methodReceiverInfo(decl *ast.FuncDecl, fset *token.FileSet, typesInfo *types.Info) (string, string) {
+// *[ast.FuncDecl], and reports whether the receiver is a pointer. It uses the
+// provided *[token.FileSet] and *[types.Info] to resolve type information
+// when available.
+func methodReceiverInfo(decl *ast.FuncDecl, fset *token.FileSet, typesInfo *types.Info, opts buildOptions) (string, string, bool) {
 	if decl == nil || decl.Recv == nil || len(decl.Recv.List) == 0 {
-		return "", ""
+		return "", "", false
 	}
 
 	field := decl.Recv.List[0]
@@ -27,7 +29,7 @@ func methodReceiverInfo(decl *ast.FuncDecl, fset *token.FileSet, typesInfo *type
 	recvType := ""
 	if typesInfo != nil && field.Type != nil {
 		if tv, ok := typesInfo.Types[field.Type]; ok && tv.Type != nil {
-			recvType = tv.Type.String()
+			recvType = opts.typeString(tv.Type)
 		}
 	}
 
@@ -35,7 +37,9 @@ func methodReceiverInfo(decl *ast.FuncDecl, fset *token.FileSet, typesInfo *type
 		recvType = exprString(field.Type, fset)
 	}
 
-	return recvName, recvType
+	_, pointer := ast.Unparen(field.Type).(*ast.StarExpr)
+
+	return recvName, recvType, pointer
 }
 
 // receiverDisplayName returns a cleaned-up version of the receiver type for
@@ -78,10 +82,43 @@ func exprString(expr ast.Expr, fset *token.FileSet) string {
 	return buf.String()
 }
 
+// funcDecl renders the declaration of the given function or method as
+// written in the source, without its body and doc comment.
+func funcDecl(decl *ast.FuncDecl, fset *token.FileSet) string {
+	if decl == nil || decl.Name == nil || decl.Type == nil {
+		return ""
+	}
+
+	var buf bytes.Buffer
+	if err := printer.Fprint(&buf, fset, &ast.FuncDecl{Recv: decl.Recv, Name: decl.Name, Type: decl.Type}); err != nil {
+		return ""
+	}
+
+	return buf.String()
+}
+
+// valueGroupDecl renders the declaration of the given constant or variable
+// group as written in the source, without its doc comment.
+func valueGroupDecl(decl *ast.GenDecl, fset *token.FileSet) string {
+	if decl == nil || len(decl.Specs) == 0 {
+		return ""
+	}
+
+	d := *decl
+	d.Doc = nil
+
+	var buf bytes.Buffer
+	if err := gofmtPrinter.Fprint(&buf, fset, &d); err != nil {
+		return ""
+	}
+
+	return buf.String()
+}
+
 // extractArgs extracts argument information from the given function or method
 // declaration. It uses the provided *[token.FileSet] and *[types.Info] to
 // resolve type information when available.
-func extractArgs(decl *ast.FuncDecl, fset *token.FileSet, typesInfo *types.Info) []ArgInfo {
+func extractArgs(decl *ast.FuncDecl, fset *token.FileSet, typesInfo *types.Info, opts buildOptions) []ArgInfo {
 	if decl == nil || decl.Type == nil || decl.Type.Params == nil {
 		return nil
 	}
@@ -99,7 +136,7 @@ func extractArgs(decl *ast.FuncDecl, fset *token.FileSet, typesInfo *types.Info)
 	}
 
 	if sig := signatureForDecl(decl, typesInfo); sig != nil {
-		return argsFromSignature(sig, names)
+		return argsFromSignature(sig, names, opts)
 	}
 
 	var args []ArgInfo
@@ -110,12 +147,12 @@ func extractArgs(decl *ast.FuncDecl, fset *token.FileSet, typesInfo *types.Info)
 			if ell, ok := field.Type.(*ast.Ellipsis); ok {
 				if ell.Elt != nil {
 					if tv, ok := typesInfo.Types[ell.Elt]; ok {
-						typ = "..." + tv.Type.String()
+						typ = "..." + opts.typeString(tv.Type)
 					}
 				}
 			} else {
 				if tv, ok := typesInfo.Types[field.Type]; ok {
-					typ = tv.Type.String()
+					typ = opts.typeString(tv.Type)
 				}
 			}
 		}
@@ -136,10 +173,32 @@ func extractArgs(decl *ast.FuncDecl, fset *token.FileSet, typesInfo *types.Info)
 	return args
 }
 
+// isVariadicDecl reports whether the function or method declaration has a
+// variadic last parameter, from its signature when type information is
+// available.
+func isVariadicDecl(decl *ast.FuncDecl, typesInfo *types.Info) bool {
+	if decl == nil || decl.Type == nil || decl.Type.Params == nil {
+		return false
+	}
+
+	if sig := signatureForDecl(decl, typesInfo); sig != nil {
+		return sig.Variadic()
+	}
+
+	params := decl.Type.Params.List
+	if len(params) == 0 {
+		return false
+	}
+
+	_, ok := params[len(params)-1].Type.(*ast.Ellipsis)
+
+	return ok
+}
+
 // extractResults extracts return value information from the given function or
 // method declaration. It uses the provided *[token.FileSet] and *[types.Info]
 // to resolve type information when available.
-func extractResults(decl *ast.FuncDecl, fset *token.FileSet, typesInfo *types.Info) []ArgInfo {
+func extractResults(decl *ast.FuncDecl, fset *token.FileSet, typesInfo *types.Info, opts buildOptions) []ArgInfo {
 	if decl == nil || decl.Type == nil || decl.Type.Results == nil {
 		return nil
 	}
@@ -157,7 +216,7 @@ func extractResults(decl *ast.FuncDecl, fset *token.FileSet, typesInfo *types.In
 	}
 
 	if sig := signatureForDecl(decl, typesInfo); sig != nil {
-		return resultsFromSignature(sig, names)
+		return resultsFromSignature(sig, names, opts)
 	}
 
 	var results []ArgInfo
@@ -165,7 +224,7 @@ func extractResults(decl *ast.FuncDecl, fset *token.FileSet, typesInfo *types.In
 		typ := ""
 		if field.Type != nil && typesInfo != nil {
 			if tv, ok := typesInfo.Types[field.Type]; ok && tv.Type != nil {
-				typ = tv.Type.String()
+				typ = opts.typeString(tv.Type)
 			}
 		}
 
@@ -186,6 +245,69 @@ func extractResults(decl *ast.FuncDecl, fset *token.FileSet, typesInfo *types.In
 	return results
 }
 
+// extractTypeParams extracts the type parameters of the given generic
+// function declaration, with their constraints. It uses the provided
+// *[token.FileSet] and *[types.Info] to resolve type information when
+// available.
+func extractTypeParams(decl *ast.FuncDecl, fset *token.FileSet, typesInfo *types.Info, opts buildOptions) []ArgInfo {
+	if decl == nil || decl.Type == nil || decl.Type.TypeParams == nil {
+		return nil
+	}
+
+	if sig := signatureForDecl(decl, typesInfo); sig != nil && sig.TypeParams().Len() > 0 {
+		return typeParamsFromList(sig.TypeParams(), opts)
+	}
+
+	return typeParamsFromFields(decl.Type.TypeParams, fset)
+}
+
+// typeSpecTypeParams extracts the type parameters of the given generic type
+// declaration, with their constraints.
+func typeSpecTypeParams(spec *ast.TypeSpec, fset *token.FileSet, typesInfo *types.Info, opts buildOptions) []ArgInfo {
+	if spec == nil || spec.TypeParams == nil {
+		return nil
+	}
+
+	if typesInfo != nil && spec.Name != nil {
+		if obj, ok := typesInfo.Defs[spec.Name].(*types.TypeName); ok {
+			if named, ok := obj.Type().(*types.Named); ok && named.TypeParams().Len() > 0 {
+				return typeParamsFromList(named.TypeParams(), opts)
+			}
+		}
+	}
+
+	return typeParamsFromFields(spec.TypeParams, fset)
+}
+
+// typeParamsFromList converts the given *[types.TypeParamList] to type
+// parameter information.
+func typeParamsFromList(list *types.TypeParamList, opts buildOptions) []ArgInfo {
+	params := make([]ArgInfo, 0, list.Len())
+	for i := 0; i < list.Len(); i++ {
+		tp := list.At(i)
+		params = append(params, ArgInfo{
+			Name: tp.Obj().Name(),
+			Type: opts.typeString(tp.Constraint()),
+		})
+	}
+
+	return params
+}
+
+// typeParamsFromFields converts the given type parameter *[ast.FieldList] to
+// type parameter information, rendering constraints as written.
+func typeParamsFromFields(list *ast.FieldList, fset *token.FileSet) []ArgInfo {
+	var params []ArgInfo
+	for _, field := range list.List {
+		constraint := exprString(field.Type, fset)
+		for _, name := range field.Names {
+			params = append(params, ArgInfo{Name: name.Name, Type: constraint})
+		}
+	}
+
+	return params
+}
+
 // signatureForDecl retrieves the *[types.Signature] for the given function or
 // method declaration using the provided *[types.Info].
 func signatureForDecl(decl *ast.FuncDecl, typesInfo *types.Info) *types.Signature {
@@ -215,7 +337,7 @@ func signatureForDecl(decl *ast.FuncDecl, typesInfo *types.Info) *types.Signatur
 // argsFromSignature extracts argument information from the given
 // *[types.Signature]. It uses the provided name hints to fill in argument
 // names when available.
-func argsFromSignature(sig *types.Signature, nameHints []string) []ArgInfo {
+func argsFromSignature(sig *types.Signature, nameHints []string, opts buildOptions) []ArgInfo {
 	if sig == nil {
 		return nil
 	}
@@ -233,10 +355,10 @@ func argsFromSignature(sig *types.Signature, nameHints []string) []ArgInfo {
 			name = nameHints[i]
 		}
 
-		typeStr := param.Type().String()
+		typeStr := opts.typeString(param.Type())
 		if sig.Variadic() && i == params.Len()-1 {
 			if slice, ok := param.Type().(*types.Slice); ok {
-				typeStr = "..." + slice.Elem().String()
+				typeStr = "..." + opts.typeString(slice.Elem())
 			} else {
 				typeStr = "..." + typeStr
 			}
@@ -245,6 +367,7 @@ func argsFromSignature(sig *types.Signature, nameHints []string) []ArgInfo {
 		args = append(args, ArgInfo{
 			Name: name,
 			Type: typeStr,
+			Ref:  namedTypeRef(param.Type()),
 		})
 	}
 
@@ -254,7 +377,7 @@ func argsFromSignature(sig *types.Signature, nameHints []string) []ArgInfo {
 // resultsFromSignature extracts return value information from the given
 // *[types.Signature]. It uses the provided name hints to fill in result names
 // when available.
-func resultsFromSignature(sig *types.Signature, nameHints []string) []ArgInfo {
+func resultsFromSignature(sig *types.Signature, nameHints []string, opts buildOptions) []ArgInfo {
 	if sig == nil {
 		return nil
 	}
@@ -274,9 +397,123 @@ func resultsFromSignature(sig *types.Signature, nameHints []string) []ArgInfo {
 
 		outs = append(outs, ArgInfo{
 			Name: name,
-			Type: res.Type().String(),
+			Type: opts.typeString(res.Type()),
+			Ref:  namedTypeRef(res.Type()),
 		})
 	}
 
 	return outs
 }
+
+// namedTypeRef returns a reference to the named type of t, after removing
+// pointers, slices, arrays, and channels, or nil if there is none or it is
+// predeclared, such as error.
+func namedTypeRef(t types.Type) *TypeRef {
+	for {
+		switch u := t.(type) {
+		case *types.Pointer:
+			t = u.Elem()
+			continue
+		case *types.Slice:
+			t = u.Elem()
+			continue
+		case *types.Array:
+			t = u.Elem()
+			continue
+		case *types.Chan:
+			t = u.Elem()
+			continue
+		}
+
+		break
+	}
+
+	named, ok := t.(interface{ Obj() *types.TypeName })
+	if !ok {
+		return nil
+	}
+
+	obj := named.Obj()
+	if _, isParam := t.(*types.TypeParam); isParam || obj.Pkg() == nil {
+		return nil
+	}
+
+	return &TypeRef{ImportPath: obj.Pkg().Path(), Name: obj.Name()}
+}
+
+// formatParams formats the given arguments as a Go parameter list, without
+// the surrounding parentheses.
+func formatParams(args []ArgInfo) string {
+	parts := make([]string, 0, len(args))
+	for _, arg := range args {
+		switch {
+		case arg.Name != "" && arg.Type != "":
+			parts = append(parts, arg.Name+" "+arg.Type)
+		case arg.Type != "":
+			parts = append(parts, arg.Type)
+		case arg.Name != "":
+			parts = append(parts, arg.Name)
+		default:
+			parts = append(parts, "_")
+		}
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+// formatResults formats the given return values as a Go result list,
+// including the leading space.
+func formatResults(returns []ArgInfo) string {
+	if len(returns) == 0 {
+		return ""
+	}
+
+	if len(returns) == 1 && returns[0].Name == "" {
+		return " " + formatParams(returns)
+	}
+
+	return " (" + formatParams(returns) + ")"
+}
+
+// formatTypeParams formats the given type parameters as a Go type parameter
+// list, including the surrounding brackets.
+func formatTypeParams(typeParams []ArgInfo) string {
+	if len(typeParams) == 0 {
+		return ""
+	}
+
+	return "[" + formatParams(typeParams) + "]"
+}
+
+// funcSignature renders the signature of the given function.
+func funcSignature(f FuncDoc) string {
+	return fmt.Sprintf("func %s%s(%s)%s", f.Name, formatTypeParams(f.TypeParams), formatParams(f.Args), formatResults(f.Returns))
+}
+
+// Signature returns the signature of the method, including its receiver
+// clause when known, as shown in the documentation.
+func (m MethodDoc) Signature() string {
+	return methodSignature(m)
+}
+
+// methodSignature renders the signature of the given method, including its
+// receiver clause when known.
+func methodSignature(m MethodDoc) string {
+	recvType := m.RecvType
+	if recvType == "" && m.Recv != "" {
+		recvType = m.Recv
+		if m.RecvPointer {
+			recvType = "*" + recvType
+		}
+	}
+
+	recv := ""
+	switch {
+	case recvType != "" && m.RecvName != "":
+		recv = fmt.Sprintf("(%s %s) ", m.RecvName, recvType)
+	case recvType != "":
+		recv = fmt.Sprintf("(%s) ", recvType)
+	}
+
+	return fmt.Sprintf("func %s%s(%s)%s", recv, m.Name, formatParams(m.Args), formatResults(m.Returns))
+}
@@ -0,0 +1,260 @@
+package godoc
+
+import (
+	"fmt"
+	"html"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// siteStylesheet is the name of the stylesheet of a doc site, at its root.
+const siteStylesheet = "style.css"
+
+// siteCSS is the stylesheet of a doc site.
+const siteCSS = `body {
+	max-width: 60rem;
+	margin: 0 auto;
+	padding: 1rem 2rem;
+	font-family: system-ui, sans-serif;
+	line-height: 1.5;
+	color: #202224;
+}
+
+a {
+	color: #007d9c;
+	text-decoration: none;
+}
+
+a:hover {
+	text-decoration: underline;
+}
+
+h2 {
+	border-bottom: 1px solid #dadce0;
+}
+
+pre {
+	padding: 0.75rem 1rem;
+	overflow-x: auto;
+	background: #f8f8f8;
+	border-radius: 4px;
+}
+
+code {
+	font-family: ui-monospace, monospace;
+}
+
+nav ul {
+	list-style: none;
+	padding-left: 0;
+}
+
+nav .level-2 {
+	padding-left: 1.5rem;
+}
+
+nav .level-3 {
+	padding-left: 3rem;
+}
+
+.type-page {
+	font-size: 0.9rem;
+}
+`
+
+// WriteHTMLSite writes the documentation of the package with the given
+// import path to outDir as a static, self-contained HTML site, e.g. to
+// browse the documentation of private modules without pkg.go.dev.
+//
+// An import path ending in "/..." also documents the packages below it, as
+// [Godoc.LoadModule] does for a module: "example.com/mod/..." documents the
+// module example.com/mod, and "./..." the main module. Version is
+// interpreted as in [Godoc.Load].
+//
+// Each package has an index.html page in the directory of its path relative
+// to the documented import path, laid out like [PackageDoc.FullHTML] and
+// listing the packages below it, and one type-<Name>.html page per type.
+// Doc links to symbols and packages of the site link to their pages; other
+// doc links point to pkg.go.dev. A minimal stylesheet is written as
+// style.css at the root.
+func (d *Godoc) WriteHTMLSite(importPath, version, outDir string) error {
+	d, cancel := d.withTimeout()
+	defer cancel()
+
+	root, tree := strings.CutSuffix(importPath, "/...")
+	if err := validateInputs(root, ""); err != nil {
+		return err
+	}
+
+	var docs []PackageDoc
+	if tree {
+		var err error
+		if docs, err = d.loadPackageTree(root, version); err != nil {
+			return err
+		}
+	} else {
+		pkgDoc, _, err := d.getOrLoadPkg(root, version)
+		if err != nil {
+			return err
+		}
+
+		docs = []PackageDoc{pkgDoc}
+	}
+
+	site := newHTMLSite(docs)
+	pages := map[string]string{siteStylesheet: siteCSS}
+	for _, p := range docs {
+		dir := site.dirs[p.ImportPath]
+		pages[path.Join(dir, "index.html")] = p.fullHTML(site)
+		for _, t := range p.Types {
+			pages[path.Join(dir, typePageName(t.Name))] = p.typeHTML(t, site)
+		}
+	}
+
+	// The packages may not include one at the root, e.g. for a module
+	// without a package at its root.
+	if _, ok := pages["index.html"]; !ok {
+		pages["index.html"] = site.indexHTML()
+	}
+
+	for name, content := range pages {
+		file := filepath.Join(outDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+			return fmt.Errorf("write site: %w", err)
+		}
+
+		if err := os.WriteFile(file, []byte(content), 0o644); err != nil {
+			return fmt.Errorf("write site: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// typePageName returns the file name of the page of the named type in a
+// doc site. It has a prefix so that types cannot clash with index.html.
+func typePageName(name string) string {
+	return "type-" + name + ".html"
+}
+
+// typeHTML renders the page of type t of the package in a doc site.
+func (p PackageDoc) typeHTML(t TypeDoc, site *htmlSite) string {
+	h := htmlWriter{
+		importPath: p.ImportPath,
+		lookupSym:  p.lookupSym,
+		site:       site,
+		typePage:   t.Name,
+	}
+
+	h.head(fmt.Sprintf("%s.%s - %s", p.Name, t.Name, p.ImportPath))
+	h.printf("<nav><a href=\"index.html\">package %s</a></nav>\n", html.EscapeString(p.Name))
+	h.typeDoc(1, t)
+	h.printf("</body>\n</html>\n")
+
+	return h.sb.String()
+}
+
+// htmlSite describes the packages of a doc site.
+type htmlSite struct {
+	docs []PackageDoc      // Sorted by import path
+	dirs map[string]string // Import path -> directory of the package pages
+	root string            // Import path of the root directory
+}
+
+// newHTMLSite returns the site of the given packages, sorted by import path,
+// rooted at the longest path prefix common to them.
+func newHTMLSite(docs []PackageDoc) *htmlSite {
+	s := &htmlSite{docs: docs, dirs: make(map[string]string, len(docs))}
+
+	for i, p := range docs {
+		if i == 0 {
+			s.root = p.ImportPath
+			continue
+		}
+
+		for s.root != "" && p.ImportPath != s.root && !strings.HasPrefix(p.ImportPath, s.root+"/") {
+			s.root = path.Dir(s.root)
+			if s.root == "." {
+				s.root = ""
+			}
+		}
+	}
+
+	for _, p := range docs {
+		s.dirs[p.ImportPath] = strings.TrimPrefix(strings.TrimPrefix(p.ImportPath, s.root), "/")
+	}
+
+	return s
+}
+
+// rootURL returns the relative URL of the root of the site from the pages
+// of the given package.
+func (s *htmlSite) rootURL(importPath string) string {
+	dir := s.dirs[importPath]
+	if dir == "" {
+		return ""
+	}
+
+	return strings.Repeat("../", strings.Count(dir, "/")+1)
+}
+
+// pageURL returns the relative URL of the page of the package to from the
+// pages of the package from, and whether to is a package of the site. It is
+// safe to call on a nil site.
+func (s *htmlSite) pageURL(from, to string) (string, bool) {
+	if s == nil {
+		return "", false
+	}
+
+	dir, ok := s.dirs[to]
+	if !ok {
+		return "", false
+	}
+
+	return s.rootURL(from) + path.Join(dir, "index.html"), true
+}
+
+// subpackages writes the list of the packages of the site below the
+// package of the page, if any.
+func (h *htmlWriter) subpackages() {
+	dir := h.site.dirs[h.importPath]
+
+	var below []PackageDoc
+	for _, p := range h.site.docs {
+		pdir := h.site.dirs[p.ImportPath]
+		if pdir != dir && (dir == "" || strings.HasPrefix(pdir, dir+"/")) {
+			below = append(below, p)
+		}
+	}
+
+	if len(below) == 0 {
+		return
+	}
+
+	h.heading(2, "pkg-subdirectories", "Directories")
+	h.printf("<ul>\n")
+	for _, p := range below {
+		rel := strings.TrimPrefix(strings.TrimPrefix(h.site.dirs[p.ImportPath], dir), "/")
+		h.printf("<li><a href=\"%s\">%s</a>", html.EscapeString(path.Join(rel, "index.html")), html.EscapeString(rel))
+		if p.Synopsis != "" {
+			h.printf(" - %s", html.EscapeString(p.Synopsis))
+		}
+		h.printf("</li>\n")
+	}
+	h.printf("</ul>\n")
+}
+
+// indexHTML renders the root page of a site without a package at its root,
+// which only lists the packages of the site.
+func (s *htmlSite) indexHTML() string {
+	h := htmlWriter{site: s}
+
+	h.head(s.root)
+	h.printf("<h1>%s</h1>\n", html.EscapeString(s.root))
+	h.subpackages()
+	h.printf("</body>\n</html>\n")
+
+	return h.sb.String()
+}
@@ -0,0 +1,96 @@
+package godoc
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWriteHTMLSite(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod":          "module example.com/app\n\ngo 1.21\n",
+		"app.go":          "// Package app uses [example.com/app/sub.Client].\npackage app\n\n// Run runs a [Server].\nfunc Run() {}\n\n// Server serves, see [Server.Serve] and [Run].\ntype Server struct{}\n\n// Serve serves.\nfunc (s *Server) Serve() {}\n",
+		"sub/sub.go":      "// Package sub is a sub-package.\npackage sub\n\n// Client is a client.\ntype Client struct{}\n",
+		"sub/deep/d.go":   "// Package deep is nested deeper.\npackage deep\n",
+		"internal/x/x.go": "// Package x is internal.\npackage x\n",
+	}
+
+	for name, src := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("failed creating directory of %s: %v", name, err)
+		}
+
+		if err := os.WriteFile(path, []byte(src), 0o644); err != nil {
+			t.Fatalf("failed writing %s: %v", name, err)
+		}
+	}
+
+	g := New(WithWorkdir(dir), WithCacheMode(CacheDisabled))
+	out := t.TempDir()
+	if err := g.WriteHTMLSite("./...", "", out); err != nil {
+		t.Fatalf("WriteHTMLSite failed: %v", err)
+	}
+
+	read := func(name string) string {
+		t.Helper()
+
+		data, err := os.ReadFile(filepath.Join(out, filepath.FromSlash(name)))
+		if err != nil {
+			t.Fatalf("failed reading %s: %v", name, err)
+		}
+
+		return string(data)
+	}
+
+	for name, wants := range map[string][]string{
+		"style.css": {"body {"},
+		"index.html": {
+			`<link rel="stylesheet" href="style.css">`,
+			`<a href="sub/index.html#Client">example.com/app/sub.Client</a>`,
+			`<p class="type-page"><a href="type-Server.html">Server</a></p>`,
+			`<h2 id="pkg-subdirectories">Directories</h2>`,
+			`<li><a href="sub/index.html">sub</a> - Package sub is a sub-package.</li>`,
+			`<li><a href="sub/deep/index.html">sub/deep</a>`,
+		},
+		"type-Server.html": {
+			`<nav><a href="index.html">package app</a></nav>`,
+			`<h1 id="Server">type Server</h1>`,
+			`<a href="#Server.Serve">Server.Serve</a>`,
+			`<a href="index.html#Run">Run</a>`,
+			`<h2 id="Server.Serve">func (*Server) Serve</h2>`,
+		},
+		"sub/index.html": {
+			`<link rel="stylesheet" href="../style.css">`,
+			`<li><a href="deep/index.html">deep</a>`,
+		},
+		"sub/deep/index.html":  {`<link rel="stylesheet" href="../../style.css">`},
+		"sub/type-Client.html": {`<h1 id="Client">type Client</h1>`},
+	} {
+		page := read(name)
+		for _, want := range wants {
+			if !strings.Contains(page, want) {
+				t.Errorf("expected %s to contain %q, got:\n%s", name, want, page)
+			}
+		}
+	}
+
+	if _, err := os.Stat(filepath.Join(out, "internal")); !os.IsNotExist(err) {
+		t.Errorf("expected internal packages to be left out, got %v", err)
+	}
+
+	single := t.TempDir()
+	if err := g.WriteHTMLSite("example.com/app/sub", "", single); err != nil {
+		t.Fatalf("WriteHTMLSite of a single package failed: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(single, "type-Client.html")); err != nil {
+		t.Errorf("expected the type page of the single package at the root: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(single, "deep")); !os.IsNotExist(err) {
+		t.Errorf("expected sub-packages to be left out without /..., got %v", err)
+	}
+}
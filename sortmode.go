@@ -0,0 +1,70 @@
+package godoc
+
+import (
+	"cmp"
+	"go/token"
+	"slices"
+)
+
+// SortMode selects the order of the constants, variables, functions, types,
+// and methods of the documentation.
+type SortMode int
+
+const (
+	// SortAlpha orders declarations by name. It is the default.
+	SortAlpha SortMode = iota
+
+	// SortSource keeps declarations in the order they appear in the source
+	// files of the package, for packages that group related declarations
+	// deliberately. Methods promoted from embedded fields, which have no
+	// declaration of their own, come last.
+	SortSource
+)
+
+// sortDocs sorts docs, which are declared at the parallel positions pos, in
+// source order in [SortSource] mode and with compare otherwise.
+//
+// Source order is the order of the file names, then of the offsets within
+// the files, as positions in fset do not follow the file names when files
+// are parsed concurrently.
+func sortDocs[T any](docs []T, pos []token.Pos, fset *token.FileSet, mode SortMode, compare func(a, b T) int) {
+	if mode != SortSource || fset == nil || len(pos) != len(docs) {
+		slices.SortFunc(docs, compare)
+		return
+	}
+
+	type sourceDoc struct {
+		doc T
+		pos token.Position
+	}
+
+	sorted := make([]sourceDoc, len(docs))
+	for i, doc := range docs {
+		sorted[i] = sourceDoc{doc: doc, pos: fset.Position(pos[i])}
+	}
+
+	slices.SortStableFunc(sorted, func(a, b sourceDoc) int {
+		return compareSourcePos(a.pos, b.pos)
+	})
+
+	for i, s := range sorted {
+		docs[i] = s.doc
+	}
+}
+
+// compareSourcePos compares two declaration positions in source order,
+// ordering declarations without a position last.
+func compareSourcePos(a, b token.Position) int {
+	if a.IsValid() != b.IsValid() {
+		if a.IsValid() {
+			return -1
+		}
+
+		return 1
+	}
+
+	return cmp.Or(
+		cmp.Compare(a.Filename, b.Filename),
+		cmp.Compare(a.Offset, b.Offset),
+	)
+}
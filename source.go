@@ -0,0 +1,177 @@
+package godoc
+
+import (
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io"
+	"io/fs"
+	"path"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// sourceDir returns the directory of the source file system set with
+// [WithSource] holding the package with the given import path.
+func (d *Godoc) sourceDir(importPath string) (string, error) {
+	dir := path.Join(d.sourceRoot, importPath)
+	if dir == "" {
+		dir = "."
+	}
+
+	if !fs.ValidPath(dir) {
+		return "", fmt.Errorf("invalid source directory %q for %q", dir, importPath)
+	}
+
+	return dir, nil
+}
+
+// sourceContext returns the build context matching the files of the source
+// file system against the build constraints of the instance.
+func (d *Godoc) sourceContext() build.Context {
+	cfg := d.Config()
+
+	ctxt := build.Default
+	ctxt.GOOS, ctxt.GOARCH = cfg.GOOS, cfg.GOARCH
+	ctxt.BuildTags = cfg.BuildTags
+	ctxt.JoinPath = path.Join
+	ctxt.OpenFile = func(name string) (io.ReadCloser, error) {
+		return d.source.Open(name)
+	}
+
+	return ctxt
+}
+
+// parseSourceFiles parses the Go files in dir of the source file system
+// that match the build constraints of the instance, either the regular files
+// or the _test.go ones, into fset. Test files that cannot be parsed are
+// skipped, as they only contribute examples.
+func (d *Godoc) parseSourceFiles(fset *token.FileSet, dir string, tests bool, mode parser.Mode) ([]*ast.File, error) {
+	entries, err := fs.ReadDir(d.source, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	ctxt := d.sourceContext()
+
+	var files []*ast.File
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") != tests {
+			continue
+		}
+
+		if ok, err := ctxt.MatchFile(dir, name); err != nil || !ok {
+			continue
+		}
+
+		filename := path.Join(dir, name)
+		src, err := fs.ReadFile(d.source, filename)
+		if err != nil {
+			return nil, err
+		}
+
+		f, err := parser.ParseFile(fset, filename, src, mode)
+		if err != nil {
+			if tests {
+				continue
+			}
+
+			return nil, err
+		}
+
+		files = append(files, f)
+	}
+
+	return files, nil
+}
+
+// loadSourcePkg parses the package with the given import path from the
+// source file system set with [WithSource], in the shape of a package of the
+// packages loader, along with its test files if wantTests is set.
+//
+// Type information is best-effort: imported packages are faked as empty ones,
+// so that nothing is read outside of the source, and type errors are ignored.
+func (d *Godoc) loadSourcePkg(importPath string, needTypes, wantTests bool) (*packages.Package, []*ast.File, error) {
+	dir, err := d.sourceDir(importPath)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	fset := token.NewFileSet()
+	files, err := d.parseSourceFiles(fset, dir, false, parser.ParseComments)
+	if err != nil {
+		return nil, nil, fmt.Errorf("parse source of %q: %w", importPath, err)
+	}
+
+	if len(files) == 0 {
+		return nil, nil, fmt.Errorf("no Go files found for %q in source", importPath)
+	}
+
+	p := &packages.Package{
+		ID:      importPath,
+		Name:    files[0].Name.Name,
+		PkgPath: importPath,
+		Fset:    fset,
+		Syntax:  files,
+	}
+
+	for _, f := range files {
+		if f.Name.Name != p.Name {
+			return nil, nil, fmt.Errorf("found packages %s and %s for %q in source", p.Name, f.Name.Name, importPath)
+		}
+
+		p.GoFiles = append(p.GoFiles, fset.File(f.Pos()).Name())
+	}
+
+	if needTypes {
+		p.TypesInfo = &types.Info{
+			Types:      make(map[ast.Expr]types.TypeAndValue),
+			Instances:  make(map[*ast.Ident]types.Instance),
+			Defs:       make(map[*ast.Ident]types.Object),
+			Uses:       make(map[*ast.Ident]types.Object),
+			Implicits:  make(map[ast.Node]types.Object),
+			Selections: make(map[*ast.SelectorExpr]*types.Selection),
+			Scopes:     make(map[ast.Node]*types.Scope),
+		}
+
+		conf := types.Config{
+			Importer:    emptyImporter{},
+			FakeImportC: true,
+			Error:       func(error) {},
+		}
+
+		p.Types, _ = conf.Check(importPath, fset, files, p.TypesInfo)
+	}
+
+	var tests []*ast.File
+	if wantTests {
+		tests, _ = d.parseSourceFiles(fset, dir, true, parser.ParseComments)
+	}
+
+	return p, tests, nil
+}
+
+// loadSourcePkgName returns the name declared in the package clause of the
+// package with the given import path in the source file system.
+func (d *Godoc) loadSourcePkgName(importPath string) (string, error) {
+	dir, err := d.sourceDir(importPath)
+	if err != nil {
+		return "", err
+	}
+
+	files, err := d.parseSourceFiles(token.NewFileSet(), dir, false, parser.PackageClauseOnly)
+	if err != nil {
+		return "", fmt.Errorf("parse source of %q: %w", importPath, err)
+	}
+
+	if len(files) == 0 {
+		return "", fmt.Errorf("no Go files found for %q in source", importPath)
+	}
+
+	return files[0].Name.Name, nil
+}
@@ -0,0 +1,144 @@
+package godoc
+
+import (
+	"container/list"
+	"runtime"
+	"sync"
+)
+
+const (
+	stdlibCacheMaxEntries = 512
+)
+
+// lruCache is a small, thread-safe, in-process LRU cache of [cacheEntry]
+// values. A nil *lruCache is valid and behaves as an always-empty cache.
+type lruCache struct {
+	mu    sync.Mutex
+	max   int
+	ll    *list.List
+	items map[string]*list.Element
+}
+
+// lruItem is the value stored in each [lruCache] list element.
+type lruItem struct {
+	key   string
+	entry cacheEntry
+}
+
+// newLRUCache creates a new [lruCache] holding at most max entries.
+func newLRUCache(max int) *lruCache {
+	if max <= 0 {
+		max = 1
+	}
+
+	return &lruCache{
+		max:   max,
+		ll:    list.New(),
+		items: make(map[string]*list.Element, max),
+	}
+}
+
+// Get returns the entry stored under key, marking it as recently used.
+func (c *lruCache) Get(key string) (cacheEntry, bool) {
+	if c == nil {
+		return cacheEntry{}, false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.items[key]
+	if !ok {
+		return cacheEntry{}, false
+	}
+
+	c.ll.MoveToFront(elem)
+
+	return elem.Value.(*lruItem).entry, true
+}
+
+// Set stores entry under key, evicting the least recently used entry when
+// the cache is full. It returns the keys of the evicted entries.
+func (c *lruCache) Set(key string, entry cacheEntry) (evicted []string) {
+	if c == nil {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.items[key]; ok {
+		elem.Value.(*lruItem).entry = entry
+		c.ll.MoveToFront(elem)
+
+		return nil
+	}
+
+	c.items[key] = c.ll.PushFront(&lruItem{key: key, entry: entry})
+
+	for c.ll.Len() > c.max {
+		oldest := c.ll.Back()
+		c.ll.Remove(oldest)
+
+		oldKey := oldest.Value.(*lruItem).key
+		delete(c.items, oldKey)
+		evicted = append(evicted, oldKey)
+	}
+
+	return evicted
+}
+
+// DeleteFunc removes the entries for which del returns true.
+func (c *lruCache) DeleteFunc(del func(cacheEntry) bool) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key, elem := range c.items {
+		if del(elem.Value.(*lruItem).entry) {
+			c.ll.Remove(elem)
+			delete(c.items, key)
+		}
+	}
+}
+
+// Len returns the number of entries in the cache.
+func (c *lruCache) Len() int {
+	if c == nil {
+		return 0
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.ll.Len()
+}
+
+// stdlibCacheKey returns the [stdlibCache] key for the given import path and
+// selector under the running Go toolchain and the configured target platform.
+func (d *Godoc) stdlibCacheKey(importPath, sel string) string {
+	return d.cacheKey(importPath, runtime.Version()+"/"+d.goos+"/"+d.goarch, sel)
+}
+
+// setStdlibEntry stores entry in the [stdlibCache] under key, reporting the
+// evicted entries to the eviction callback.
+func (d *Godoc) setStdlibEntry(key string, entry cacheEntry) {
+	for _, evicted := range stdlibCache.Set(key, entry) {
+		d.evicted(evicted)
+	}
+}
+
+// mayBeStdlib reports whether importPath could refer to a standard library
+// package, and is therefore eligible for the [stdlibCache] fast path.
+func mayBeStdlib(importPath string) bool {
+	return importPath != "." && !isRemoteImportPath(importPath)
+}
+
+// isStdlibEntry reports whether the given cache metadata describes a standard
+// library package built by the running Go toolchain.
+func isStdlibEntry(meta cacheMetadata) bool {
+	return meta.ModuleVersion == "" && meta.GoVersion == runtime.Version()
+}
@@ -0,0 +1,79 @@
+package godoc
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+)
+
+// maxSuggestions is the maximum number of similar symbols suggested when a
+// symbol is not found.
+const maxSuggestions = 5
+
+// suggestSymbols returns up to [maxSuggestions] names similar to sel, most
+// similar first: names equal to sel but for case, then names that sel is a
+// case-insensitive prefix of, then names within a small edit distance.
+func suggestSymbols(sel string, names []string) []string {
+	type candidate struct {
+		name  string
+		score int
+	}
+
+	lowerSel := strings.ToLower(sel)
+	maxDist := max(1, len(sel)/3)
+
+	var candidates []candidate
+	for _, name := range names {
+		lower := strings.ToLower(name)
+		switch {
+		case lower == lowerSel:
+			candidates = append(candidates, candidate{name, 0})
+		case strings.HasPrefix(lower, lowerSel):
+			candidates = append(candidates, candidate{name, 1})
+		default:
+			if dist := levenshtein(lowerSel, lower); dist <= maxDist {
+				candidates = append(candidates, candidate{name, 1 + dist})
+			}
+		}
+	}
+
+	slices.SortFunc(candidates, func(a, b candidate) int {
+		return cmp.Or(
+			cmp.Compare(a.score, b.score),
+			cmp.Compare(len(a.name), len(b.name)),
+			strings.Compare(a.name, b.name),
+		)
+	})
+
+	var suggestions []string
+	for _, c := range candidates[:min(len(candidates), maxSuggestions)] {
+		suggestions = append(suggestions, c.name)
+	}
+
+	return suggestions
+}
+
+// levenshtein returns the edit distance between a and b, in bytes.
+func levenshtein(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+
+		prev, curr = curr, prev
+	}
+
+	return prev[len(b)]
+}
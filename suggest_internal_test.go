@@ -0,0 +1,51 @@
+package godoc
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSuggestSymbols(t *testing.T) {
+	names := []string{"Printf", "Println", "Print", "Sprintf", "Fprintf", "Errorf", "Stringer", "State"}
+
+	tests := []struct {
+		sel  string
+		want []string
+	}{
+		{"printf", []string{"Printf", "Print", "Fprintf", "Sprintf", "Println"}},
+		{"Prin", []string{"Print", "Printf", "Println"}},
+		{"Prinft", []string{"Print", "Printf"}},
+		{"Stringr", []string{"Stringer"}},
+		{"Unrelated", nil},
+	}
+
+	for _, tt := range tests {
+		if got := suggestSymbols(tt.sel, names); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("suggestSymbols(%q) = %v, want %v", tt.sel, got, tt.want)
+		}
+	}
+
+	many := []string{"A1", "A2", "A3", "A4", "A5", "A6"}
+	if got := suggestSymbols("a", many); len(got) != maxSuggestions {
+		t.Errorf("expected at most %d suggestions, got %v", maxSuggestions, got)
+	}
+}
+
+func TestSymbolNotFoundErrorMessage(t *testing.T) {
+	tests := []struct {
+		suggestions []string
+		want        string
+	}{
+		{nil, `selector "printf" not found in "fmt"`},
+		{[]string{"Printf"}, `selector "printf" not found in "fmt"; did you mean Printf?`},
+		{[]string{"Printf", "Sprintf"}, `selector "printf" not found in "fmt"; did you mean Printf or Sprintf?`},
+		{[]string{"Printf", "Sprintf", "Fprintf"}, `selector "printf" not found in "fmt"; did you mean Printf, Sprintf, or Fprintf?`},
+	}
+
+	for _, tt := range tests {
+		err := &SymbolNotFoundError{ImportPath: "fmt", Symbol: "printf", Suggestions: tt.suggestions}
+		if got := err.Error(); got != tt.want {
+			t.Errorf("Error() = %q, want %q", got, tt.want)
+		}
+	}
+}
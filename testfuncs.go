@@ -0,0 +1,106 @@
+package godoc
+
+import (
+	"go/ast"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// testFuncs holds the names of the test, benchmark, and fuzz functions
+// declared in a package's test files.
+type testFuncs struct {
+	tests, benchmarks, fuzzes []string
+}
+
+// collectTestFuncs returns the test, benchmark, and fuzz functions declared in
+// the given test files, following the naming and signature conventions of
+// "go test". Names are sorted.
+func collectTestFuncs(files []*ast.File) testFuncs {
+	var funcs testFuncs
+	for _, f := range files {
+		for _, decl := range f.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Recv != nil || fn.Type.TypeParams != nil {
+				continue
+			}
+
+			name := fn.Name.Name
+			switch {
+			case isTestFunc(fn, "Test", "T"):
+				funcs.tests = append(funcs.tests, name)
+			case isTestFunc(fn, "Benchmark", "B"):
+				funcs.benchmarks = append(funcs.benchmarks, name)
+			case isTestFunc(fn, "Fuzz", "F"):
+				funcs.fuzzes = append(funcs.fuzzes, name)
+			}
+		}
+	}
+
+	sort.Strings(funcs.tests)
+	sort.Strings(funcs.benchmarks)
+	sort.Strings(funcs.fuzzes)
+
+	return funcs
+}
+
+// testHelperFile returns a copy of the test file f without the test,
+// benchmark, fuzz, and example functions run by "go test", leaving the
+// helpers it declares.
+func testHelperFile(f *ast.File) *ast.File {
+	helpers := *f
+	helpers.Decls = nil
+	for _, decl := range f.Decls {
+		if fn, ok := decl.(*ast.FuncDecl); ok && fn.Recv == nil && isTestHarnessFunc(fn) {
+			continue
+		}
+
+		helpers.Decls = append(helpers.Decls, decl)
+	}
+
+	return &helpers
+}
+
+// isTestHarnessFunc reports whether fn is a test, benchmark, fuzz, or
+// example function.
+func isTestHarnessFunc(fn *ast.FuncDecl) bool {
+	if isTestFunc(fn, "Test", "T") || isTestFunc(fn, "Benchmark", "B") || isTestFunc(fn, "Fuzz", "F") {
+		return true
+	}
+
+	return strings.HasPrefix(fn.Name.Name, "Example") && fn.Type.Params.NumFields() == 0 && fn.Type.Results.NumFields() == 0
+}
+
+// isTestFunc reports whether fn is named prefix followed by a suffix that
+// does not start with a lowercase letter, and takes a single *testing.<arg>
+// parameter, as "go test" requires.
+func isTestFunc(fn *ast.FuncDecl, prefix, arg string) bool {
+	name := fn.Name.Name
+	if len(name) < len(prefix) || name[:len(prefix)] != prefix {
+		return false
+	}
+
+	if r, _ := utf8.DecodeRuneInString(name[len(prefix):]); unicode.IsLower(r) {
+		return false
+	}
+
+	params := fn.Type.Params
+	if params == nil || len(params.List) != 1 || len(params.List[0].Names) > 1 || fn.Type.Results != nil {
+		return false
+	}
+
+	ptr, ok := params.List[0].Type.(*ast.StarExpr)
+	if !ok {
+		return false
+	}
+
+	switch typ := ptr.X.(type) {
+	case *ast.SelectorExpr: // *testing.T
+		return typ.Sel.Name == arg
+	case *ast.Ident: // *T, with testing dot-imported
+		return typ.Name == arg
+	default:
+		return false
+	}
+}
@@ -0,0 +1,50 @@
+package godoc
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"slices"
+	"testing"
+)
+
+const testFuncsSrc = `package p_test
+
+import (
+	"testing"
+	. "testing"
+)
+
+func TestMain(m *testing.M)          {}
+func TestA(t *testing.T)             {}
+func Test(t *testing.T)              {}
+func Testify(t *testing.T)           {}
+func Test_b(t *T)                    {}
+func TestHelper(t *testing.T) error  { return nil }
+func TestTwo(a, b *testing.T)        {}
+func BenchmarkA(b *testing.B)        {}
+func BenchmarkWrong(t *testing.T)    {}
+func FuzzA(f *testing.F)             {}
+func (s suite) TestMethod(t *testing.T) {}
+`
+
+func TestCollectTestFuncs(t *testing.T) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "p_test.go", testFuncsSrc, 0)
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+
+	got := collectTestFuncs([]*ast.File{f})
+	if want := []string{"Test", "TestA", "Test_b"}; !slices.Equal(got.tests, want) {
+		t.Errorf("tests: expected %v, got %v", want, got.tests)
+	}
+
+	if want := []string{"BenchmarkA"}; !slices.Equal(got.benchmarks, want) {
+		t.Errorf("benchmarks: expected %v, got %v", want, got.benchmarks)
+	}
+
+	if want := []string{"FuzzA"}; !slices.Equal(got.fuzzes, want) {
+		t.Errorf("fuzzes: expected %v, got %v", want, got.fuzzes)
+	}
+}
@@ -0,0 +1,85 @@
+package godoc
+
+import "go/doc/comment"
+
+// TOCEntry is an entry of the table of contents of a package.
+//
+// IDs follow the anchors of pkg.go.dev: "pkg-overview", "pkg-constants",
+// "pkg-variables", "pkg-functions", and "pkg-types" for the sections, the
+// ID assigned by [comment.Heading.DefaultID] for doc comment headings, the
+// name for constants, variables, functions, and types, and "Type.Method" for
+// methods.
+type TOCEntry struct {
+	Title string `json:"title" jsonschema:"entry title"`
+	ID    string `json:"id" jsonschema:"anchor of the entry"`
+	Kind  string `json:"kind" jsonschema:"entry kind (section, heading, const, var, func, type, or method)"`
+	Level int    `json:"level" jsonschema:"nesting level, starting at 1 for sections"`
+}
+
+// TableOfContents returns the table of contents of the package: the
+// overview with the headings of the package comment, followed by a section
+// for each non-empty group of constants, variables, functions, and types.
+// Types list their constructors and methods, which are therefore not listed
+// among the functions.
+func (p PackageDoc) TableOfContents() []TOCEntry {
+	toc := []TOCEntry{{Title: "Overview", ID: "pkg-overview", Kind: "section", Level: 1}}
+
+	parsed := p.docParsed
+	if parsed == nil && p.DocText != "" {
+		parsed = parseDocText(p.DocText, p.lookupSym)
+	}
+
+	if parsed != nil {
+		for _, block := range parsed.Content {
+			if h, ok := block.(*comment.Heading); ok {
+				toc = append(toc, TOCEntry{Title: manText(h.Text), ID: h.DefaultID(), Kind: "heading", Level: 2})
+			}
+		}
+	}
+
+	toc = appendValueTOC(toc, "Constants", "pkg-constants", "const", p.Consts)
+	toc = appendValueTOC(toc, "Variables", "pkg-variables", "var", p.Vars)
+
+	var funcs []TOCEntry
+	for _, f := range p.standaloneFuncs() {
+		funcs = append(funcs, TOCEntry{Title: f.Name, ID: f.Name, Kind: "func", Level: 2})
+	}
+
+	if len(funcs) > 0 {
+		toc = append(toc, TOCEntry{Title: "Functions", ID: "pkg-functions", Kind: "section", Level: 1})
+		toc = append(toc, funcs...)
+	}
+
+	if len(p.Types) > 0 {
+		toc = append(toc, TOCEntry{Title: "Types", ID: "pkg-types", Kind: "section", Level: 1})
+		for _, t := range p.Types {
+			toc = append(toc, TOCEntry{Title: t.Name, ID: t.Name, Kind: "type", Level: 2})
+			for _, f := range t.Constructors {
+				toc = append(toc, TOCEntry{Title: f.Name, ID: f.Name, Kind: "func", Level: 3})
+			}
+
+			for _, m := range t.Methods {
+				toc = append(toc, TOCEntry{Title: m.Name, ID: t.Name + "." + m.Name, Kind: "method", Level: 3})
+			}
+		}
+	}
+
+	return toc
+}
+
+// appendValueTOC appends a section listing the names of the given constants
+// or variables, if any.
+func appendValueTOC(toc []TOCEntry, title, id, kind string, values []ValueDoc) []TOCEntry {
+	if len(values) == 0 {
+		return toc
+	}
+
+	toc = append(toc, TOCEntry{Title: title, ID: id, Kind: "section", Level: 1})
+	for _, v := range values {
+		for _, name := range v.Names {
+			toc = append(toc, TOCEntry{Title: name, ID: name, Kind: kind, Level: 2})
+		}
+	}
+
+	return toc
+}
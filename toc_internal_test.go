@@ -0,0 +1,44 @@
+package godoc
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTableOfContents(t *testing.T) {
+	p := PackageDoc{
+		Name:    "foo",
+		DocText: "Package foo does things.\n\n# Getting started\n\nUse [New].\n\n# Advanced use\n\nMore.\n",
+		Vars:    []ValueDoc{{Names: []string{"ErrA", "ErrB"}}},
+		Funcs:   []FuncDoc{{Name: "Helper"}, {Name: "New"}},
+		Types: []TypeDoc{{
+			Name:         "Client",
+			Constructors: []FuncDoc{{Name: "New"}},
+			Methods:      []MethodDoc{{Name: "Do"}},
+		}},
+	}
+
+	want := []TOCEntry{
+		{Title: "Overview", ID: "pkg-overview", Kind: "section", Level: 1},
+		{Title: "Getting started", ID: "hdr-Getting_started", Kind: "heading", Level: 2},
+		{Title: "Advanced use", ID: "hdr-Advanced_use", Kind: "heading", Level: 2},
+		{Title: "Variables", ID: "pkg-variables", Kind: "section", Level: 1},
+		{Title: "ErrA", ID: "ErrA", Kind: "var", Level: 2},
+		{Title: "ErrB", ID: "ErrB", Kind: "var", Level: 2},
+		{Title: "Functions", ID: "pkg-functions", Kind: "section", Level: 1},
+		{Title: "Helper", ID: "Helper", Kind: "func", Level: 2},
+		{Title: "Types", ID: "pkg-types", Kind: "section", Level: 1},
+		{Title: "Client", ID: "Client", Kind: "type", Level: 2},
+		{Title: "New", ID: "New", Kind: "func", Level: 3},
+		{Title: "Do", ID: "Client.Do", Kind: "method", Level: 3},
+	}
+
+	if got := p.TableOfContents(); !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected table of contents:\n got: %+v\nwant: %+v", got, want)
+	}
+
+	empty := PackageDoc{Name: "empty"}
+	if got := empty.TableOfContents(); len(got) != 1 || got[0].ID != "pkg-overview" {
+		t.Errorf("expected only the overview for an empty package, got %+v", got)
+	}
+}
@@ -3,69 +3,235 @@ package godoc
 import (
 	"encoding/json"
 	"go/doc/comment"
+	"sync"
 )
 
 // FuncDoc represents documentation for a function.
 type FuncDoc struct {
-	Name    string    `json:"name" jsonschema:"function name"`
-	Args    []ArgInfo `json:"args" jsonschema:"function arguments"`
-	Returns []ArgInfo `json:"returns,omitempty" jsonschema:"function return values"`
-	Doc     string    `json:"doc" jsonschema:"function documentation"`
+	Name       string    `json:"name" jsonschema:"function name"`
+	Decl       string    `json:"decl" jsonschema:"function declaration"`
+	TypeParams []ArgInfo `json:"type_params,omitempty" jsonschema:"type parameters with their constraints"`
+	Args       []ArgInfo `json:"args" jsonschema:"function arguments"`
+	Returns    []ArgInfo `json:"returns,omitempty" jsonschema:"function return values"`
+	Variadic   bool      `json:"variadic,omitempty" jsonschema:"whether the last argument is variadic"`
+	Doc        string    `json:"doc" jsonschema:"function documentation"`
+
+	Deprecated     bool   `json:"deprecated,omitempty" jsonschema:"whether the function is deprecated"`
+	DeprecatedNote string `json:"deprecated_note,omitempty" jsonschema:"text of the Deprecated: paragraph"`
+
+	DocLinks []DocLink `json:"doc_links,omitempty" jsonschema:"doc links of the function documentation"`
 }
 
 // ValueDoc represents documentation for a constant or variable.
 type ValueDoc struct {
-	Names []string `json:"names" jsonschema:"value identifiers"`
-	Doc   string   `json:"doc" jsonschema:"value documentation"`
+	Names  []string `json:"names" jsonschema:"value identifiers"`
+	Values []string `json:"values,omitempty" jsonschema:"constant or variable values aligned with names"`
+	Doc    string   `json:"doc" jsonschema:"value documentation"`
+	Decl   string   `json:"decl,omitempty" jsonschema:"constant or variable declaration"`
+
+	DocLinks []DocLink `json:"doc_links,omitempty" jsonschema:"doc links of the value documentation"`
 }
 
 // ArgInfo represents information about a function or method argument.
 type ArgInfo struct {
 	Name string `json:"name" jsonschema:"argument name"`
 	Type string `json:"type" jsonschema:"argument type"`
+
+	// Ref is the named type of the argument, after removing pointers,
+	// slices, arrays, and channels, when type information is available.
+	// It is nil for predeclared and unnamed types.
+	Ref *TypeRef `json:"ref,omitempty" jsonschema:"named type of the argument for linking"`
+}
+
+// TypeRef identifies a named type by its package, e.g. to link to its
+// documentation.
+type TypeRef struct {
+	ImportPath string `json:"import_path" jsonschema:"import path of the package declaring the type"`
+	Name       string `json:"name" jsonschema:"type name"`
 }
 
 // MethodDoc represents documentation for a method.
 type MethodDoc struct {
-	Recv     string    `json:"recv" jsonschema:"receiver type name"`
-	RecvName string    `json:"recv_name,omitempty" jsonschema:"receiver identifier"`
-	RecvType string    `json:"recv_type,omitempty" jsonschema:"receiver type"`
-	Name     string    `json:"name" jsonschema:"method name"`
-	Args     []ArgInfo `json:"args" jsonschema:"method arguments"`
-	Returns  []ArgInfo `json:"returns,omitempty" jsonschema:"method return values"`
-	Doc      string    `json:"doc" jsonschema:"method documentation"`
+	Recv        string    `json:"recv" jsonschema:"receiver type name"`
+	RecvName    string    `json:"recv_name,omitempty" jsonschema:"receiver identifier"`
+	RecvType    string    `json:"recv_type,omitempty" jsonschema:"receiver type"`
+	RecvPointer bool      `json:"recv_pointer,omitempty" jsonschema:"whether the method has a pointer receiver"`
+	Name        string    `json:"name" jsonschema:"method name"`
+	Args        []ArgInfo `json:"args" jsonschema:"method arguments"`
+	Returns     []ArgInfo `json:"returns,omitempty" jsonschema:"method return values"`
+	Variadic    bool      `json:"variadic,omitempty" jsonschema:"whether the last argument is variadic"`
+	Doc         string    `json:"doc" jsonschema:"method documentation"`
+
+	Deprecated     bool   `json:"deprecated,omitempty" jsonschema:"whether the method is deprecated"`
+	DeprecatedNote string `json:"deprecated_note,omitempty" jsonschema:"text of the Deprecated: paragraph"`
+
+	// Promoted reports whether the method is promoted from an embedded
+	// field, and PromotedFrom is the type declaring it, where its doc
+	// comment is found: only the methods promoted from unexported types
+	// have their Doc.
+	Promoted     bool   `json:"promoted,omitempty" jsonschema:"whether the method is promoted from an embedded field"`
+	PromotedFrom string `json:"promoted_from,omitempty" jsonschema:"type declaring a promoted method"`
+
+	DocLinks []DocLink `json:"doc_links,omitempty" jsonschema:"doc links of the method documentation"`
 }
 
 // FieldDoc represents documentation for a struct field.
 type FieldDoc struct {
-	Name     string `json:"name" jsonschema:"field name"`
-	Type     string `json:"type" jsonschema:"field type"`
-	Doc      string `json:"doc" jsonschema:"field documentation"`
-	Tag      string `json:"tag,omitempty" jsonschema:"field tag"`
-	Embedded bool   `json:"embedded,omitempty" jsonschema:"whether the field is embedded"`
+	Name       string `json:"name" jsonschema:"field name"`
+	Type       string `json:"type" jsonschema:"field type"`
+	Doc        string `json:"doc" jsonschema:"field documentation"`
+	Tag        string `json:"tag,omitempty" jsonschema:"field tag"`
+	Embedded   bool   `json:"embedded,omitempty" jsonschema:"whether the field is embedded"`
+	Deprecated bool   `json:"deprecated,omitempty" jsonschema:"whether the field is deprecated"`
+	RawDoc     string `json:"raw_doc,omitempty" jsonschema:"raw field comments including directives"`
+
+	DeprecatedNote string `json:"deprecated_note,omitempty" jsonschema:"text of the Deprecated: paragraph"`
+
+	// ParsedTag maps the keys of Tag to their values, parsed in the
+	// conventional format of [reflect.StructTag]: the tag
+	// `json:"name,omitempty" xml:"n"` maps "json" to "name,omitempty" and
+	// "xml" to "n". It is empty if the tag is malformed.
+	ParsedTag map[string]string `json:"parsed_tag,omitempty" jsonschema:"values of the field tag by key"`
+
+	// Platforms lists the platforms ("goos/goarch") declaring the field when
+	// merging fields across platforms and the field is not declared on all
+	// of them. It is empty if the field is declared everywhere. A field whose
+	// type differs across platforms is listed once per type.
+	Platforms []string `json:"platforms,omitempty" jsonschema:"platforms declaring the field if not all"`
+
+	DocLinks []DocLink `json:"doc_links,omitempty" jsonschema:"doc links of the field documentation"`
+
+	DocHTML   string       `json:"-" jsonschema:"field documentation HTML"`
+	docParsed *comment.Doc // For lazy HTML generation
+	html      *lazyHTML    // Memoized HTML, shared by copies
+}
+
+// HTML returns the HTML documentation of the field, rendered with the
+// comment printer of its package, like that of the other symbols. It is
+// rendered on first use and memoized for all copies of the field;
+// concurrent calls are safe. The documentation of fields restored from the
+// cache is rendered with a default printer.
+func (f FieldDoc) HTML() string {
+	switch {
+	case f.DocHTML != "" || f.Doc == "":
+		return f.DocHTML
+	case f.docParsed == nil:
+		return renderDocHTML(nil, new(comment.Parser).Parse(f.Doc))
+	case f.html != nil:
+		return f.html.render(f.docParsed)
+	default:
+		return renderDocHTML(nil, f.docParsed)
+	}
 }
 
 // TypeDoc represents documentation for a type, including its fields and methods.
 type TypeDoc struct {
-	Name    string      `json:"name" jsonschema:"type name"`
-	Doc     string      `json:"doc" jsonschema:"type documentation"`
-	Decl    string      `json:"decl" jsonschema:"type declaration"`
-	Kind    string      `json:"kind" jsonschema:"type category"`
-	Fields  []FieldDoc  `json:"fields" jsonschema:"struct fields"`
-	Methods []MethodDoc `json:"methods" jsonschema:"associated methods"`
+	Name       string      `json:"name" jsonschema:"type name"`
+	TypeParams []ArgInfo   `json:"type_params,omitempty" jsonschema:"type parameters with their constraints"`
+	Doc        string      `json:"doc" jsonschema:"type documentation"`
+	Decl       string      `json:"decl" jsonschema:"type declaration"`
+	Kind       string      `json:"kind" jsonschema:"type category"`
+	Fields     []FieldDoc  `json:"fields" jsonschema:"struct fields"`
+	Embeds     []string    `json:"embeds,omitempty" jsonschema:"interfaces embedded in an interface type"`
+	Methods    []MethodDoc `json:"methods" jsonschema:"associated methods"`
+
+	// AliasTarget is the type denoted by an alias declaration, as written
+	// in the source, such as "uint8" for "type byte = uint8".
+	AliasTarget string `json:"alias_target,omitempty" jsonschema:"type denoted by a type alias"`
+
+	// Constructors are the package-level functions returning the type, as
+	// grouped by go doc. They are also listed in PackageDoc.Funcs.
+	Constructors []FuncDoc `json:"constructors,omitempty" jsonschema:"functions constructing the type"`
+
+	// Implements are the interfaces of the package implemented by a concrete
+	// type, and ImplementedBy the concrete types of the package implementing
+	// an interface type. They are only set with WithImplements.
+	Implements    []string `json:"implements,omitempty" jsonschema:"interfaces of the package implemented by the type"`
+	ImplementedBy []string `json:"implemented_by,omitempty" jsonschema:"concrete types of the package implementing the interface"`
+
+	Deprecated     bool   `json:"deprecated,omitempty" jsonschema:"whether the type is deprecated"`
+	DeprecatedNote string `json:"deprecated_note,omitempty" jsonschema:"text of the Deprecated: paragraph"`
+
+	DocLinks []DocLink `json:"doc_links,omitempty" jsonschema:"doc links of the type documentation"`
+}
+
+// DocLink is a doc link of a doc comment, such as [io.Reader] or
+// [Client.Do], resolved to the documentation it refers to.
+type DocLink struct {
+	Text       string `json:"text" jsonschema:"link text"`
+	Target     string `json:"target" jsonschema:"absolute URL of the linked documentation on pkg.go.dev"`
+	ImportPath string `json:"import_path" jsonschema:"import path of the linked package"`
+	Name       string `json:"name,omitempty" jsonschema:"linked symbol (e.g. Reader or Client.Do); empty for a package"`
+}
+
+// ExampleDoc represents a runnable example from the package's test files.
+type ExampleDoc struct {
+	Name   string `json:"name" jsonschema:"example name without the Example prefix (e.g. Printf_withWidth)"`
+	Code   string `json:"code" jsonschema:"formatted example code"`
+	Output string `json:"output,omitempty" jsonschema:"expected output"`
+	Doc    string `json:"doc,omitempty" jsonschema:"example documentation"`
+}
+
+// NoteDoc represents a marked comment, such as "BUG(uid): body", in the
+// package's source.
+type NoteDoc struct {
+	UID  string `json:"uid" jsonschema:"user ID or key of the marker, e.g. the author"`
+	Body string `json:"body" jsonschema:"note text"`
+}
+
+// InlineType is the declaration of a type referenced by a symbol, inlined
+// with [WithInlineTypes].
+type InlineType struct {
+	Name string `json:"name" jsonschema:"type name"`
+	Decl string `json:"decl" jsonschema:"type declaration"`
+}
+
+// Position is the location of a declaration in its source file.
+type Position struct {
+	File   string `json:"file" jsonschema:"absolute path of the source file; for remote packages a path in the module cache"`
+	Line   int    `json:"line" jsonschema:"1-based line number"`
+	Column int    `json:"column" jsonschema:"1-based column number, in bytes"`
 }
 
 // PackageDoc represents documentation for a Go package.
+//
+// Its contents are in a stable order, so that equal packages always encode
+// to identical JSON: constant and variable groups, functions, types, methods,
+// and examples are sorted by name, while struct fields keep their declaration
+// order.
 type PackageDoc struct {
-	ImportPath string     `json:"import_path" jsonschema:"package import path"`
-	Name       string     `json:"name" jsonschema:"package name"`
-	Synopsis   string     `json:"synopsis" jsonschema:"package synopsis"`
-	DocText    string     `json:"doc" jsonschema:"package documentation text"`
-	DocHTML    string     `json:"-" jsonschema:"package documentation HTML"`
-	Consts     []ValueDoc `json:"consts" jsonschema:"package constants"`
-	Vars       []ValueDoc `json:"vars" jsonschema:"package variables"`
-	Funcs      []FuncDoc  `json:"funcs" jsonschema:"package functions"`
-	Types      []TypeDoc  `json:"types" jsonschema:"package types"`
+	ImportPath string               `json:"import_path" jsonschema:"package import path"`
+	Name       string               `json:"name" jsonschema:"package name"`
+	Synopsis   string               `json:"synopsis" jsonschema:"package synopsis"`
+	DocText    string               `json:"doc" jsonschema:"package documentation text"`
+	DocHTML    string               `json:"-" jsonschema:"package documentation HTML"`
+	Consts     []ValueDoc           `json:"consts" jsonschema:"package constants"`
+	Vars       []ValueDoc           `json:"vars" jsonschema:"package variables"`
+	Funcs      []FuncDoc            `json:"funcs" jsonschema:"package functions"`
+	Types      []TypeDoc            `json:"types" jsonschema:"package types"`
+	Examples   []ExampleDoc         `json:"examples,omitempty" jsonschema:"examples of the package and its symbols"`
+	Tests      []string             `json:"tests,omitempty" jsonschema:"test function names"`
+	Benchmarks []string             `json:"benchmarks,omitempty" jsonschema:"benchmark function names"`
+	Fuzzes     []string             `json:"fuzzes,omitempty" jsonschema:"fuzz test function names"`
+	Notes      map[string][]NoteDoc `json:"notes,omitempty" jsonschema:"marked comments by marker, such as BUG or TODO"`
+
+	// ModuleVersion is the version the module providing the package was
+	// resolved to, e.g. "v1.2.3" for the version query "latest". It is empty
+	// for the standard library and the main module.
+	ModuleVersion string `json:"module_version,omitempty" jsonschema:"resolved version of the module providing the package"`
+
+	// BuildConstraints are the distinct "//go:build" expressions of the
+	// files selected for the target platform and build tags, such as
+	// "linux && amd64". Constraints implied by file names are not included.
+	BuildConstraints []string `json:"build_constraints,omitempty" jsonschema:"//go:build expressions of the selected files"`
+
+	// DocLinks are the doc links of the package comment, such as
+	// [io.Reader], resolved to their documentation on pkg.go.dev. They are
+	// only set with [WithResolveDocLinks], as are those of the other
+	// documentation of the package.
+	DocLinks []DocLink `json:"doc_links,omitempty" jsonschema:"doc links of the package documentation"`
+
+	docParsed *comment.Doc // For doc link collection
 }
 
 // Text returns the plain text documentation for the package.
@@ -88,18 +254,49 @@ func (p PackageDoc) MarshalJSON() ([]byte, error) {
 // SymbolDoc represents documentation for a specific symbol (type, method,
 // function, const, or var).
 type SymbolDoc struct {
-	ImportPath   string `json:"import_path" jsonschema:"package import path"`
-	Package      string `json:"package" jsonschema:"package name"`
-	Kind         string `json:"kind" jsonschema:"symbol kind"`
-	Name         string `json:"name" jsonschema:"symbol name"`
-	Receiver     string `json:"receiver,omitempty" jsonschema:"receiver type name"`
-	ReceiverName string `json:"receiver_name,omitempty" jsonschema:"receiver identifier"`
-	ReceiverType string `json:"receiver_type,omitempty" jsonschema:"receiver type"`
+	ImportPath   string       `json:"import_path" jsonschema:"package import path"`
+	Package      string       `json:"package" jsonschema:"package name"`
+	Kind         string       `json:"kind" jsonschema:"symbol kind"`
+	Name         string       `json:"name" jsonschema:"symbol name"`
+	Receiver     string       `json:"receiver,omitempty" jsonschema:"receiver type name"`
+	ReceiverName string       `json:"receiver_name,omitempty" jsonschema:"receiver identifier"`
+	ReceiverType string       `json:"receiver_type,omitempty" jsonschema:"receiver type"`
+	RecvPointer  bool         `json:"recv_pointer,omitempty" jsonschema:"whether the method has a pointer receiver"`
+	Decl         string       `json:"decl,omitempty" jsonschema:"function, method, or type declaration"`
+	Value        string       `json:"value,omitempty" jsonschema:"constant or variable value"`
+	Implementers []string     `json:"implementers,omitempty" jsonschema:"concrete types implementing the interface"`
+	TypeParams   []ArgInfo    `json:"type_params,omitempty" jsonschema:"type parameters of a generic function or type"`
+	Examples     []ExampleDoc `json:"examples,omitempty" jsonschema:"examples of the symbol"`
+	Pos          *Position    `json:"pos,omitempty" jsonschema:"position of the symbol's name in its source file"`
+
+	// Deprecated and DeprecatedNote shadow those of the embedded FuncDoc
+	// and TypeDoc, which would otherwise be ambiguous.
+	Deprecated     bool   `json:"deprecated,omitempty" jsonschema:"whether the symbol is deprecated"`
+	DeprecatedNote string `json:"deprecated_note,omitempty" jsonschema:"text of the Deprecated: paragraph"`
+
+	// DocLinks likewise shadows the doc links of the embedded FuncDoc and
+	// TypeDoc.
+	DocLinks []DocLink `json:"doc_links,omitempty" jsonschema:"doc links of the symbol documentation"`
+
+	// IsAlias reports whether a type symbol is a type alias rather than a
+	// defined type, and AliasTarget is the type it denotes, as in TypeDoc.
+	IsAlias     bool   `json:"is_alias,omitempty" jsonschema:"whether the type is a type alias"`
+	AliasTarget string `json:"alias_target,omitempty" jsonschema:"type denoted by a type alias"`
+
+	// InlineTypes are the declarations of the package's types referenced by
+	// the symbol, when enabled with [WithInlineTypes].
+	InlineTypes []InlineType `json:"inline_types,omitempty" jsonschema:"declarations of the package types referenced by the symbol"`
+
+	// ModuleVersion is the resolved version of the module providing the
+	// package, as in PackageDoc.
+	ModuleVersion string `json:"module_version,omitempty" jsonschema:"resolved version of the module providing the package"`
+
 	*FuncDoc
 	*TypeDoc
 	DocText   string       `json:"doc" jsonschema:"symbol documentation text"`
 	DocHTML   string       `json:"-" jsonschema:"symbol documentation HTML"`
 	docParsed *comment.Doc // For lazy HTML generation
+	html      *lazyHTML    // Memoized HTML, shared by copies
 }
 
 // Text returns the plain text documentation for the symbol.
@@ -107,14 +304,45 @@ func (s SymbolDoc) Text() string {
 	return s.DocText
 }
 
-// HTML returns the HTML documentation for the symbol.
+// HTML returns the HTML documentation for the symbol. It is rendered on
+// first use and memoized for all copies of the symbol; concurrent calls are
+// safe.
 func (s SymbolDoc) HTML() string {
-	if s.DocHTML == "" && s.docParsed != nil {
-		r := comment.Printer{HeadingLevel: 3}
-		s.DocHTML = string(r.HTML(s.docParsed))
+	switch {
+	case s.DocHTML != "" || s.docParsed == nil:
+		return s.DocHTML
+	case s.html != nil:
+		return s.html.render(s.docParsed)
+	default:
+		return renderDocHTML(nil, s.docParsed)
 	}
+}
 
-	return s.DocHTML
+// lazyHTML memoizes the HTML rendering of a parsed doc comment. It is held
+// by pointer so that it survives the value receivers of [SymbolDoc].
+type lazyHTML struct {
+	once    sync.Once
+	printer *comment.Printer
+	html    string
+}
+
+// render returns the HTML of parsed, rendering it on the first call only.
+func (l *lazyHTML) render(parsed *comment.Doc) string {
+	l.once.Do(func() {
+		l.html = renderDocHTML(l.printer, parsed)
+	})
+
+	return l.html
+}
+
+// renderDocHTML renders parsed as HTML with the given printer, or with
+// level-3 headings if printer is nil.
+func renderDocHTML(printer *comment.Printer, parsed *comment.Doc) string {
+	if printer == nil {
+		printer = &comment.Printer{HeadingLevel: 3}
+	}
+
+	return string(printer.HTML(parsed))
 }
 
 // MarshalJSON implements [json.Marshaler] while omitting internal fields.
@@ -124,10 +352,21 @@ func (s SymbolDoc) MarshalJSON() ([]byte, error) {
 	return json.Marshal(alias(s))
 }
 
+// SymbolRef identifies a documented symbol of a package.
+type SymbolRef struct {
+	Name     string `json:"name" jsonschema:"selector of the symbol as accepted by Load (e.g. Client, Client.Do, or NewClient)"`
+	Kind     string `json:"kind" jsonschema:"symbol kind (const, var, func, type, or method)"`
+	Receiver string `json:"receiver,omitempty" jsonschema:"receiver type name of a method"`
+
+	// Snippet is the documentation excerpt matching a [Godoc.Search].
+	Snippet string `json:"snippet,omitempty" jsonschema:"documentation excerpt of a search match"`
+}
+
 // Result is an interface for documentation results, providing access to
-// documentation text, HTML, and JSON serialization.
+// documentation text, HTML, Markdown, and JSON serialization.
 type Result interface {
 	Text() string
 	HTML() string
+	Markdown() string
 	MarshalJSON() ([]byte, error)
 }
@@ -4,14 +4,20 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"go/doc"
+	"hash/fnv"
+	"maps"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"slices"
 	"strings"
 	"sync"
+	"time"
 
 	"golang.org/x/mod/modfile"
+	"golang.org/x/tools/go/packages"
 )
 
 // validateInputs checks the import path and selector for validity and security.
@@ -35,8 +41,201 @@ func validateInputs(importPath, sel string) error {
 	return nil
 }
 
+// normalizeSelector converts the alternate "Type/Method" and "Type#Method"
+// selector separators into the canonical "Type.Method" form.
+func normalizeSelector(sel string) string {
+	return selectorSeparatorReplacer.Replace(sel)
+}
+
+// Synopsis returns the first sentence of the given doc text, such as
+// [SymbolDoc.DocText], the way go doc summarizes packages. It returns an
+// empty string for text starting with a copyright or license notice.
+func Synopsis(docText string) string {
+	return new(doc.Package).Synopsis(docText)
+}
+
+// goGet runs "go get target" in dir, waiting for a free fetch slot first if
+// the number of concurrent fetches is limited.
+func (d *Godoc) goGet(dir, target string) error {
+	release, err := d.acquireFetchSlot()
+	if err != nil {
+		return fmt.Errorf("go get %s: %w", target, err)
+	}
+	defer release()
+
+	return d.runGo(dir, "get", target)
+}
+
+// goList runs "go list target" in dir with the configured build tags.
+func (d *Godoc) goList(dir, target string) error {
+	args := append([]string{"list"}, d.buildFlags()...)
+
+	return d.runGo(dir, append(args, target)...)
+}
+
+// acquireFetchSlot waits for a free fetch slot if the number of concurrent
+// fetches is limited, and returns a function releasing it.
+func (d *Godoc) acquireFetchSlot() (func(), error) {
+	return d.acquireSlot(d.fetchSem)
+}
+
+// acquireLoadSlot waits for a free package load slot if the number of
+// concurrent loads is limited, and returns a function releasing it.
+func (d *Godoc) acquireLoadSlot() (func(), error) {
+	return d.acquireSlot(d.loadSem)
+}
+
+// acquireSlot waits for a free slot of sem, unless sem is nil, or for the
+// context of d to be done.
+func (d *Godoc) acquireSlot(sem chan struct{}) (func(), error) {
+	if sem == nil {
+		return func() {}, nil
+	}
+
+	ctx := d.context()
+	select {
+	case sem <- struct{}{}:
+		return func() { <-sem }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// concurrency returns the limit on concurrent package loads.
+func (d *Godoc) concurrency() int {
+	if d.loadSem == nil {
+		return runtime.NumCPU()
+	}
+
+	return cap(d.loadSem)
+}
+
+// goCmdEnv returns the environment for the go commands run by [Godoc.runGo].
+func (d *Godoc) goCmdEnv() []string {
+	// Keep env, but force module mode and ignore any parent go.work.
+	env := append(os.Environ(), "GO111MODULE=on", "GOWORK=off")
+	env = append(env, d.goEnvList()...)
+	if d != nil && d.sumDB != "" {
+		env = append(env, "GOSUMDB="+d.sumDB)
+	}
+
+	if d != nil && d.goProxy != "" {
+		env = append(env, "GOPROXY="+d.goProxy)
+	}
+
+	if d != nil && d.goBinary != "" {
+		// Run exactly the configured binary, without toolchain switching.
+		env = append(env, "GOTOOLCHAIN=local")
+	}
+
+	return env
+}
+
+// goEnvList returns the variables set with [WithGoEnv] as "KEY=value"
+// entries, sorted by key. Later entries of an environment take precedence,
+// so options setting the same variables are appended after them.
+func (d *Godoc) goEnvList() []string {
+	if d == nil || len(d.goEnv) == 0 {
+		return nil
+	}
+
+	env := make([]string, 0, len(d.goEnv))
+	for _, key := range slices.Sorted(maps.Keys(d.goEnv)) {
+		env = append(env, key+"="+d.goEnv[key])
+	}
+
+	return env
+}
+
+// goBinaryVersion returns the Go version (e.g. "go1.25.1") of the configured
+// go binary.
+func (d *Godoc) goBinaryVersion() (string, error) {
+	if v, ok := goBinaryVersions.Load(d.goBinary); ok {
+		return v.(string), nil
+	}
+
+	cmd := exec.CommandContext(d.context(), d.goBinary, "env", "GOVERSION")
+	cmd.Env = append(os.Environ(), "GOTOOLCHAIN=local")
+
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("%w: %s: %v", ErrInvalidGoBinary, d.goBinary, err)
+	}
+
+	version := strings.TrimSpace(string(out))
+	if !strings.HasPrefix(version, "go") {
+		return "", fmt.Errorf("%w: %s: unexpected version %q", ErrInvalidGoBinary, d.goBinary, version)
+	}
+
+	goBinaryVersions.Store(d.goBinary, version)
+
+	return version, nil
+}
+
+// goBinaryPathDir returns a directory holding a link to the configured go
+// binary named after its Go version, such as "go1.25.1". The link is kept in
+// the user cache directory, so that it is created once per binary.
+//
+// With the directory first in PATH, a go command switching to that version
+// runs the configured binary instead of downloading the toolchain.
+func (d *Godoc) goBinaryPathDir(version string) (string, error) {
+	bin, err := exec.LookPath(d.goBinary)
+	if err != nil {
+		return "", err
+	}
+
+	if bin, err = filepath.Abs(bin); err != nil {
+		return "", err
+	}
+
+	root, err := getCacheDir()
+	if err != nil {
+		return "", err
+	}
+
+	hash := fnv.New64a()
+	hash.Write([]byte(bin))
+
+	dir := filepath.Join(root, "toolchains", fmt.Sprintf("%016x", hash.Sum64()))
+	link := filepath.Join(dir, version)
+	if runtime.GOOS == "windows" {
+		link += ".exe"
+	}
+
+	if target, err := os.Readlink(link); err == nil && target == bin {
+		return dir, nil
+	}
+
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", err
+	}
+
+	_ = os.Remove(link)
+	if err := os.Symlink(bin, link); err != nil {
+		// Another process may have created the same link meanwhile.
+		if target, readErr := os.Readlink(link); readErr != nil || target != bin {
+			return "", err
+		}
+	}
+
+	return dir, nil
+}
+
+// goCmdWaitDelay bounds how long a go command killed on cancellation is
+// waited for, e.g. while the processes it started, such as git, still hold
+// its output open.
+const goCmdWaitDelay = 100 * time.Millisecond
+
 // runGo executes a 'go' command with the given arguments in the specified dir.
 func (d *Godoc) runGo(dir string, args ...string) error {
+	_, err := d.goOutput(dir, args...)
+
+	return err
+}
+
+// goOutput executes a 'go' command with the given arguments in the specified
+// dir and returns its standard output, which may be non-empty on failure.
+func (d *Godoc) goOutput(dir string, args ...string) ([]byte, error) {
 	ctx := context.Background()
 	if d != nil {
 		ctx = d.context()
@@ -44,48 +243,115 @@ func (d *Godoc) runGo(dir string, args ...string) error {
 
 	var stderr bytes.Buffer
 
-	cmd := exec.CommandContext(ctx, "go", args...)
+	goBin := "go"
+	if d != nil && d.goBinary != "" {
+		goBin = d.goBinary
+	}
+
+	cmd := exec.CommandContext(ctx, goBin, args...)
 	cmd.Dir = dir
-	// Keep env, but force module mode and ignore any parent go.work.
-	cmd.Env = append(os.Environ(), "GO111MODULE=on", "GOWORK=off")
+	cmd.Env = d.goCmdEnv()
 	cmd.Stderr = &stderr
+	cmd.WaitDelay = goCmdWaitDelay
 
-	if err := cmd.Run(); err != nil {
+	out, err := cmd.Output()
+	if err != nil {
 		if ctxErr := ctx.Err(); ctxErr != nil {
-			return fmt.Errorf("go %s: %v", strings.Join(args, " "), ctxErr)
+			return out, fmt.Errorf("go %s: %w", strings.Join(args, " "), ctxErr)
 		}
 
 		// TODO(dwisiswant0): Consider including stderr output in the error.
 
-		return fmt.Errorf("%w", err)
+		return out, fmt.Errorf("%w", err)
 	}
 
-	return nil
+	return out, nil
 }
 
 // getVersionFromMod reads the go.mod file in workdir to find the version of
-// the specified importPath. If not found or any error occurs, it returns an
-// empty string.
+// the module providing the specified importPath. If not found or any error
+// occurs, it returns an empty string.
 func getVersionFromMod(workdir, importPath string) string {
+	_, version := requiredModule(workdir, importPath)
+
+	return version
+}
+
+// requiredModule reads the go.mod file in workdir and returns the path and
+// version of the required module that provides importPath, i.e. the longest
+// required module path that is importPath itself or one of its parents. If
+// not found or any error occurs, it returns empty strings.
+func requiredModule(workdir, importPath string) (string, string) {
 	modFilePath := filepath.Join(workdir, "go.mod")
 
 	data, err := os.ReadFile(modFilePath)
 	if err != nil {
-		return ""
+		return "", ""
 	}
 
 	f, err := modfile.Parse(modFilePath, data, nil)
 	if err != nil {
-		return ""
+		return "", ""
 	}
 
+	var modPath, version string
 	for _, r := range f.Require {
-		if r.Mod.Path == importPath {
-			return strings.TrimSpace(r.Mod.Version)
+		if r.Mod.Path != importPath && !strings.HasPrefix(importPath, r.Mod.Path+"/") {
+			continue
+		}
+
+		if len(r.Mod.Path) > len(modPath) {
+			modPath = r.Mod.Path
+			version = strings.TrimSpace(r.Mod.Version)
 		}
 	}
 
-	return ""
+	return modPath, version
+}
+
+// matchFilePattern reports whether the base name of the given file matches
+// pattern, which is either a [filepath.Match] pattern or, if it has no
+// pattern metacharacters, a file name suffix such as "_linux.go".
+func matchFilePattern(filename, pattern string) (bool, error) {
+	name := filepath.Base(filename)
+	if !strings.ContainsAny(pattern, `*?[\`) {
+		return strings.HasSuffix(name, pattern), nil
+	}
+
+	ok, err := filepath.Match(pattern, name)
+	if err != nil {
+		return false, fmt.Errorf("%w: %q", ErrInvalidFilePattern, pattern)
+	}
+
+	return ok, nil
+}
+
+// normalizeVersion trims surrounding whitespace and an optional leading "@"
+// from a version or version query, so "@latest" and "latest" are equivalent.
+func normalizeVersion(version string) string {
+	return strings.TrimPrefix(strings.TrimSpace(version), "@")
+}
+
+// isRelativeVersionQuery reports whether version is a query resolved
+// relative to the currently required version of a module ("upgrade" or
+// "patch").
+func isRelativeVersionQuery(version string) bool {
+	return version == "upgrade" || version == "patch"
+}
+
+// moduleVersionMatches reports whether the module a package was loaded from
+// satisfies the requested version. Any version is satisfied when no version
+// was requested, and by packages outside of a module of known version: those
+// of the standard library, of the main module, or of a module whose version
+// is unknown. Otherwise, the module version must equal the requested one, so
+// that a version query such as "latest" is never satisfied by the required
+// version of a module.
+func moduleVersionMatches(module *packages.Module, version string) bool {
+	if version == "" || module == nil || module.Version == "" || module.Main {
+		return true
+	}
+
+	return module.Version == version
 }
 
 // getPkgVersion gets the appropriate version string for the package based on
@@ -122,4 +388,8 @@ func resetCacheGlobals() {
 	globalCache = nil
 	cacheFilePath = ""
 	cachePersistent = false
+	memoryCacheOnce = sync.Once{}
+	memoryCache = nil
+	dirCaches.Clear()
+	stdlibCache = newLRUCache(stdlibCacheMaxEntries)
 }
@@ -0,0 +1,208 @@
+package godoc
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/doc"
+	"go/token"
+	"go/types"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// declValues returns the values of the constants or variables declared by
+// the given *[doc.Value], aligned with its Names.
+//
+// Constant values are taken from typesInfo when available, so iota-based
+// constants resolve to their actual value; otherwise, as for variables, the
+// initializer expression is rendered as written. Variables initialized from
+// a multi-valued expression and values spanning several lines, such as large
+// composite literals, are left empty. It returns nil if no value could be
+// determined.
+func declValues(v *doc.Value, fset *token.FileSet, typesInfo *types.Info) []string {
+	if v == nil || v.Decl == nil || (v.Decl.Tok != token.CONST && v.Decl.Tok != token.VAR) {
+		return nil
+	}
+
+	byName := make(map[string]string, len(v.Names))
+	for _, spec := range v.Decl.Specs {
+		vs, ok := spec.(*ast.ValueSpec)
+		if !ok {
+			continue
+		}
+
+		for i, name := range vs.Names {
+			var val string
+			if v.Decl.Tok == token.CONST {
+				val = constValue(name, vs, i, fset, typesInfo)
+			} else if len(vs.Values) == len(vs.Names) {
+				val = valueExprString(vs.Values[i], fset)
+			}
+
+			if val != "" {
+				byName[name.Name] = val
+			}
+		}
+	}
+
+	if len(byName) == 0 {
+		return nil
+	}
+
+	values := make([]string, len(v.Names))
+	for i, name := range v.Names {
+		values[i] = byName[name]
+	}
+
+	return values
+}
+
+// constValue returns the value of the i-th constant declared by vs.
+func constValue(name *ast.Ident, vs *ast.ValueSpec, i int, fset *token.FileSet, typesInfo *types.Info) string {
+	if typesInfo != nil {
+		if c, ok := typesInfo.Defs[name].(*types.Const); ok {
+			if val := constValueString(c.Val()); val != "" {
+				return val
+			}
+		}
+	}
+
+	// Without type information, iota-based values cannot be resolved.
+	if i < len(vs.Values) && !usesIota(vs.Values[i]) {
+		return valueExprString(vs.Values[i], fset)
+	}
+
+	return ""
+}
+
+// usesIota reports whether the given expression refers to iota.
+func usesIota(expr ast.Expr) bool {
+	var found bool
+	ast.Inspect(expr, func(n ast.Node) bool {
+		if id, ok := n.(*ast.Ident); ok && id.Name == "iota" {
+			found = true
+		}
+
+		return !found
+	})
+
+	return found
+}
+
+// valueExprString renders the given initializer expression, or returns an
+// empty string if it spans several lines.
+func valueExprString(expr ast.Expr, fset *token.FileSet) string {
+	s := exprString(expr, fset)
+	if strings.Contains(s, "\n") {
+		return ""
+	}
+
+	return s
+}
+
+// constValueString formats the given [constant.Value] the way it would be
+// written in Go source. Floating-point and complex values are written
+// exactly when possible, and otherwise with the shortest representation of
+// the nearest float64, as by go doc.
+func constValueString(val constant.Value) string {
+	switch val.Kind() {
+	case constant.Unknown:
+		return ""
+	case constant.String:
+		return strconv.Quote(constant.StringVal(val))
+	case constant.Float:
+		return floatValueString(val)
+	case constant.Complex:
+		if s := val.ExactString(); !strings.Contains(s, "/") {
+			return s
+		}
+
+		return fmt.Sprintf("(%s + %si)", floatValueString(constant.Real(val)), floatValueString(constant.Imag(val)))
+	default:
+		return val.ExactString()
+	}
+}
+
+// floatValueString formats the given numeric [constant.Value] exactly if
+// it is not a fraction, and otherwise as the nearest float64.
+func floatValueString(val constant.Value) string {
+	if s := val.ExactString(); !strings.Contains(s, "/") {
+		return s
+	}
+
+	f, _ := constant.Float64Val(val)
+
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+// evalConsts type-checks the files of a package loaded without type
+// information, so that its computed constants, such as iota-based ones, can
+// be evaluated without loading the package again. Imported packages are
+// faked as empty, so constants depending on them stay unknown, and type
+// errors are ignored. It returns nil if no constant needs evaluating.
+//
+// The files must be checked before go/doc drops their unexported
+// declarations, which exported constants may depend on.
+func evalConsts(fset *token.FileSet, files []*ast.File, importPath string) *types.Info {
+	if !slices.ContainsFunc(files, fileRequiresConstEval) {
+		return nil
+	}
+
+	info := &types.Info{Defs: make(map[*ast.Ident]types.Object)}
+	conf := types.Config{
+		Importer:         emptyImporter{},
+		IgnoreFuncBodies: true,
+		FakeImportC:      true,
+		Error:            func(error) {},
+	}
+
+	_, _ = conf.Check(importPath, fset, files, info)
+
+	return info
+}
+
+// fileRequiresConstEval reports whether the given file declares a constant
+// that is not a basic literal, such as an iota expression or an implicitly
+// repeated value.
+func fileRequiresConstEval(f *ast.File) bool {
+	for _, decl := range f.Decls {
+		gd, ok := decl.(*ast.GenDecl)
+		if !ok || gd.Tok != token.CONST {
+			continue
+		}
+
+		for _, spec := range gd.Specs {
+			vs, ok := spec.(*ast.ValueSpec)
+			if !ok {
+				continue
+			}
+
+			if len(vs.Values) < len(vs.Names) {
+				return true
+			}
+
+			for _, expr := range vs.Values {
+				if _, ok := expr.(*ast.BasicLit); !ok {
+					return true
+				}
+			}
+		}
+	}
+
+	return false
+}
+
+// emptyImporter is a [types.Importer] faking each imported package as an
+// empty one, for best-effort type checking without loading dependencies.
+type emptyImporter struct{}
+
+// Import implements [types.Importer].
+func (emptyImporter) Import(importPath string) (*types.Package, error) {
+	pkg := types.NewPackage(importPath, path.Base(importPath))
+	pkg.MarkComplete()
+
+	return pkg, nil
+}
@@ -0,0 +1,212 @@
+package godoc
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/doc"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"reflect"
+	"testing"
+)
+
+const valuesTestSrc = `package p
+
+// Color is a color.
+type Color int
+
+// Colors.
+const (
+	Red Color = iota
+	Green
+	Blue
+)
+
+// Limits.
+const (
+	Max   = 1 << 4
+	Name  = "godoc"
+	Ratio = 1.5
+	Pi    = 3.14159265358979323846264338327950288419716939937510582097494459
+)
+
+// Defaults.
+var (
+	DefaultColor      = Green
+	DefaultName, Size = split()
+	Table             = map[string]int{
+		"a": 1,
+	}
+)
+
+func split() (string, int) { return "", 0 }
+`
+
+func loadValuesTestPkg(t *testing.T, withTypes bool) (*doc.Package, *token.FileSet, *types.Info) {
+	t.Helper()
+
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "p.go", valuesTestSrc, parser.ParseComments)
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+
+	var info *types.Info
+	if withTypes {
+		info = &types.Info{Defs: make(map[*ast.Ident]types.Object)}
+		conf := types.Config{Importer: importer.Default()}
+		if _, err := conf.Check("p", fset, []*ast.File{f}, info); err != nil {
+			t.Fatalf("type check failed: %v", err)
+		}
+	}
+
+	dpkg, err := doc.NewFromFiles(fset, []*ast.File{f}, "example.com/p")
+	if err != nil {
+		t.Fatalf("doc failed: %v", err)
+	}
+
+	return dpkg, fset, info
+}
+
+func TestConstValuesWithTypesInfo(t *testing.T) {
+	dpkg, fset, info := loadValuesTestPkg(t, true)
+
+	if len(dpkg.Types) != 1 || len(dpkg.Types[0].Consts) != 1 {
+		t.Fatalf("expected Color constants grouped under type")
+	}
+
+	got := declValues(dpkg.Types[0].Consts[0], fset, info)
+	want := []string{"0", "1", "2"}
+	if len(got) != len(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("value %d: expected %q, got %q", i, want[i], got[i])
+		}
+	}
+
+	got = declValues(dpkg.Consts[0], fset, info)
+	want = []string{"16", `"godoc"`, "1.5", "3.141592653589793"}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("value %d: expected %q, got %q", i, want[i], got[i])
+		}
+	}
+}
+
+func TestConstValuesWithoutTypesInfo(t *testing.T) {
+	dpkg, fset, _ := loadValuesTestPkg(t, false)
+
+	if pkgRequiresTypesInfo(dpkg) {
+		t.Fatalf("expected iota constants not to require type info")
+	}
+
+	if got := declValues(dpkg.Types[0].Consts[0], fset, nil); got != nil {
+		t.Fatalf("expected no values for iota constants without type info, got %v", got)
+	}
+
+	got := declValues(dpkg.Consts[0], fset, nil)
+	want := []string{"1 << 4", `"godoc"`, "1.5", "3.14159265358979323846264338327950288419716939937510582097494459"}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("value %d: expected %q, got %q", i, want[i], got[i])
+		}
+	}
+}
+
+func TestEvalConsts(t *testing.T) {
+	const src = `package p
+
+import "time"
+
+type Color int
+
+const (
+	Red Color = iota
+	Green
+)
+
+const Answer = half * 2
+
+const half = 21
+
+const Timeout = 2 * time.Second
+`
+
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "p.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+
+	// The constants are evaluated before go/doc drops the unexported ones.
+	info := evalConsts(fset, []*ast.File{f}, "example.com/p")
+	if info == nil {
+		t.Fatalf("expected computed constants to be evaluated")
+	}
+
+	dpkg, err := doc.NewFromFiles(fset, []*ast.File{f}, "example.com/p")
+	if err != nil {
+		t.Fatalf("doc failed: %v", err)
+	}
+
+	if got := declValues(dpkg.Types[0].Consts[0], fset, info); !reflect.DeepEqual(got, []string{"0", "1"}) {
+		t.Errorf("expected iota constants to evaluate to [0 1], got %v", got)
+	}
+
+	want := map[string]string{"Answer": "42", "Timeout": "2 * time.Second"}
+	for _, c := range dpkg.Consts {
+		if got := declValues(c, fset, info); len(got) != 1 || got[0] != want[c.Names[0]] {
+			t.Errorf("%s: expected %q, got %v", c.Names[0], want[c.Names[0]], got)
+		}
+	}
+
+	lit, err := parser.ParseFile(fset, "lit.go", "package p\n\nconst Name = \"godoc\"\n", 0)
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+
+	if info := evalConsts(fset, []*ast.File{lit}, "example.com/p"); info != nil {
+		t.Errorf("expected no evaluation for literal constants")
+	}
+}
+
+func TestConstValueString(t *testing.T) {
+	tests := []struct {
+		val  constant.Value
+		want string
+	}{
+		{constant.MakeFromLiteral("1.5", token.FLOAT, 0), "1.5"},
+		{constant.MakeFromLiteral("1e3", token.FLOAT, 0), "1000"},
+		{constant.MakeFromLiteral("3.14159265358979323846264338327950288419716939937510582097494459", token.FLOAT, 0), "3.141592653589793"},
+		{constant.MakeFromLiteral("2i", token.IMAG, 0), "(0 + 2i)"},
+		{constant.MakeFromLiteral("0.1i", token.IMAG, 0), "(0 + 0.1i)"},
+		{constant.MakeInt64(-3), "-3"},
+	}
+
+	for _, tt := range tests {
+		if got := constValueString(tt.val); got != tt.want {
+			t.Errorf("constValueString(%v) = %q, want %q", tt.val, got, tt.want)
+		}
+	}
+}
+
+func TestVarValues(t *testing.T) {
+	dpkg, fset, info := loadValuesTestPkg(t, true)
+
+	if len(dpkg.Vars) != 1 {
+		t.Fatalf("expected one var group, got %d", len(dpkg.Vars))
+	}
+
+	got := declValues(dpkg.Vars[0], fset, info)
+	want := map[string]string{"DefaultColor": "Green", "DefaultName": "", "Size": "", "Table": ""}
+	for i, name := range dpkg.Vars[0].Names {
+		if got[i] != want[name] {
+			t.Errorf("%s: expected %q, got %q", name, want[name], got[i])
+		}
+	}
+}
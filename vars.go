@@ -1,7 +1,9 @@
 package godoc
 
 import (
+	"go/printer"
 	"regexp"
+	"strings"
 	"sync"
 
 	"go.dw1.io/fastcache"
@@ -13,6 +15,52 @@ var (
 	cacheFilePath   string
 	cachePersistent bool
 	cacheMu         sync.Mutex
+	memoryCacheOnce sync.Once
+	memoryCache     *fastcache.Cache[string, cacheEntry]
+	dirCaches       sync.Map // cache directory -> *dirCache
+	stdlibCache     = newLRUCache(stdlibCacheMaxEntries)
+	fetchSem        = make(chan struct{}, DefaultMaxConcurrentFetches)
+
+	goBinaryVersions sync.Map // go binary path -> Go version
+
+	// buildEnvVars are the go environment variables that may change the
+	// files or declarations of a package, and thus its documentation.
+	buildEnvVars = map[string]bool{
+		"CGO_ENABLED":  true,
+		"GOARCH":       true,
+		"GOEXPERIMENT": true,
+		"GOFLAGS":      true,
+		"GOOS":         true,
+	}
+
+	// licensePrefixes and licensePhrases identify, in lower case, the
+	// copyright and license notices removed with WithStripLicenseHeader:
+	// paragraphs starting with a prefix or containing a phrase.
+	licensePrefixes = []string{"copyright", "(c)", "©", "spdx-license-identifier"}
+	licensePhrases  = []string{
+		"all rights reserved",
+		"licensed under",
+		"use of this source code is governed",
+		"permission is hereby granted",
+		"redistribution and use in source and binary forms",
+		"the software is provided",
+		"this program is free software",
+	}
 
 	selectorRegex = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*(\.[a-zA-Z_][a-zA-Z0-9_]*)*$`)
+
+	selectorSeparatorReplacer = strings.NewReplacer("/", ".", "#", ".")
+
+	exampleOutputRe = regexp.MustCompile(`(?i)//[[:space:]]*(unordered )?output:`)
+
+	// gofmtPrinter prints code laid out as by gofmt, aligning with spaces.
+	gofmtPrinter = printer.Config{Mode: printer.UseSpaces | printer.TabIndent, Tabwidth: 8}
+
+	// qualifiedTypeRe matches the import path qualified names in type
+	// strings, such as "net/http.Header".
+	qualifiedTypeRe = regexp.MustCompile(`[A-Za-z0-9_.~-]+(?:/[A-Za-z0-9_.~-]+)+`)
+
+	// docLinksRe matches doc links, with the import path of links to other
+	// packages, such as "net/http." in [net/http.Client], as first group.
+	docLinksRe = regexp.MustCompile(`\\?\[((?:[a-z][A-Za-z0-9_.~/-]*?\.)?)([A-Z][A-Za-z0-9_.]*)\\?\]`)
 )
@@ -0,0 +1,52 @@
+package godoc
+
+import "runtime/debug"
+
+// godocModulePath is the path of this module, to find its version in the
+// build information.
+const godocModulePath = "go.dw1.io/godoc"
+
+// version is the version reported by [Version], if set at build time with
+// -ldflags "-X go.dw1.io/godoc.version=v1.2.3".
+var version string
+
+// Version returns the version of the godoc module the program was built
+// with, e.g. to record which build produced some documentation.
+//
+// Unless set at build time with -ldflags "-X go.dw1.io/godoc.version=...",
+// it is read from the build information embedded in the binary: the
+// version of the module when it is a dependency or installed with
+// "go install", or "(devel)" for a build of a local checkout. It is
+// "(unknown)" if the binary has no build information.
+func Version() string {
+	if version != "" {
+		return version
+	}
+
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "(unknown)"
+	}
+
+	mod := &info.Main
+	if mod.Path != godocModulePath {
+		mod = nil
+		for _, dep := range info.Deps {
+			if dep.Path == godocModulePath {
+				mod = dep
+				break
+			}
+		}
+	}
+
+	switch {
+	case mod == nil:
+		return "(unknown)"
+	case mod.Replace != nil && mod.Replace.Version != "":
+		return mod.Replace.Version
+	case mod.Version == "":
+		return "(devel)"
+	default:
+		return mod.Version
+	}
+}
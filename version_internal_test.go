@@ -0,0 +1,17 @@
+package godoc
+
+import "testing"
+
+func TestVersion(t *testing.T) {
+	if got := Version(); got == "" {
+		t.Errorf("expected a version from the build information")
+	}
+
+	old := version
+	t.Cleanup(func() { version = old })
+
+	version = "v1.2.3"
+	if got := Version(); got != "v1.2.3" {
+		t.Errorf("expected the version set at build time, got %q", got)
+	}
+}